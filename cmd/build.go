@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
 	"phpier/internal/config"
 	"phpier/internal/docker"
 	"phpier/internal/errors"
@@ -14,6 +19,7 @@ import (
 var (
 	noCache    bool
 	regenerate bool
+	buildForce bool
 )
 
 // buildCmd represents the build command
@@ -26,12 +32,25 @@ This command will:
 - Build only the app container using the project's Dockerfile.php
 - Support forcing a rebuild with the --no-cache flag
 - Validate that the project is properly initialized
-- Use existing configuration files (preserves customizations)
+- Use the existing configuration files, unless --regenerate is given
+- Pass build.args from the x-phpier block and host proxy settings as build arguments
+  (they are in .phpier.yml, so 'phpier up' builds with them too)
+
+--regenerate re-renders .phpier.yml and the files in .phpier from the x-phpier block.
+Changes made to .phpier.yml outside the x-phpier block are replaced, so it asks first
+when the file would change; --force skips the question.
+
+Dockerfile snippets in .phpier/Dockerfile.d are included on --regenerate. Name them
+after a hook point: after-system-deps*.dockerfile, after-extensions*.dockerfile or
+final*.dockerfile. The first two are built into the shared base image, without the
+project files as build context, so COPY and ADD of project files only work in final
+snippets.
 
 Examples:
   phpier build                    # Build the app container using existing files
   phpier build --no-cache         # Force a clean rebuild without using cache
-  phpier build --regenerate       # Regenerate config files before building`,
+  phpier build --regenerate       # Regenerate config files before building
+  phpier build --regenerate -f    # Regenerate without asking before rewriting .phpier.yml`,
 	RunE: runBuild,
 }
 
@@ -41,6 +60,7 @@ func init() {
 	// Flags
 	buildCmd.Flags().BoolVar(&noCache, "no-cache", false, "Do not use cache when building the image")
 	buildCmd.Flags().BoolVar(&regenerate, "regenerate", false, "Regenerate configuration files before building")
+	buildCmd.Flags().BoolVarP(&buildForce, "force", "f", false, "Rewrite .phpier.yml on --regenerate without asking")
 }
 
 func runBuild(cmd *cobra.Command, args []string) error {
@@ -60,15 +80,33 @@ func runBuild(cmd *cobra.Command, args []string) error {
 
 	// Regenerate files only if requested
 	if regenerate {
-		logrus.Infof("🔄 Regenerating configuration files...")
 		engine := templates.NewEngine()
+		if !buildForce {
+			rendered, err := generator.RenderProjectCompose(engine, projectCfg, globalCfg)
+			if err != nil {
+				return errors.WrapError(errors.ErrorTypeTemplateError, "Failed to generate .phpier.yml", err)
+			}
+			if existing, err := os.ReadFile(".phpier.yml"); err == nil && string(existing) != rendered {
+				fmt.Print("Regenerating rewrites .phpier.yml from its x-phpier block; changes outside x-phpier are lost. Continue? [y/N] ")
+				answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+				if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+					fmt.Println("Aborted.")
+					return nil
+				}
+			}
+		}
+
+		logrus.Infof("🔄 Regenerating configuration files...")
 		if err := generator.GenerateProjectFiles(engine, projectCfg, globalCfg); err != nil {
 			return errors.WrapError(errors.ErrorTypeUnknown, "Failed to generate project files", err)
 		}
+		if err := generator.GenerateProjectCompose(engine, projectCfg, globalCfg); err != nil {
+			return errors.WrapError(errors.ErrorTypeTemplateError, "Failed to generate .phpier.yml", err)
+		}
 	}
 
 	// Create Docker Compose manager
-	composeManager, err := docker.NewProjectComposeManager(projectCfg, globalCfg)
+	composeManager, err := docker.NewProjectComposeManager(cmd.Context(), projectCfg, globalCfg, commandTimeout)
 	if err != nil {
 		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to create Docker client", err)
 	}
@@ -25,6 +25,7 @@ func TestBuildCommandFlags(t *testing.T) {
 		flagType string
 	}{
 		{"no-cache flag", "no-cache", "bool"},
+		{"force flag", "force", "bool"},
 	}
 
 	for _, tt := range tests {
@@ -7,10 +7,19 @@ import (
 
 	"phpier/internal/config"
 	"phpier/internal/docker"
+	"phpier/internal/errors"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+var (
+	dbCredentialsServer bool
+	dbAddEngine         string
+	dbAddVersion        string
+	dbAddPort           int
+)
+
 // dbCmd represents the db command
 var dbCmd = &cobra.Command{
 	Use:   "db",
@@ -18,19 +27,22 @@ var dbCmd = &cobra.Command{
 	Long: `Manage database services (MySQL, PostgreSQL, MariaDB).
 	
 This command provides database service management capabilities including:
-- Enable/disable individual database services
-- View status of all database services
-- View credentials for enabled services
-- List all available database services
-
-Multiple database services can run simultaneously.`,
+- Add, remove, enable and disable named database instances
+- View status of all database instances
+- View credentials for enabled instances
+- List all configured database instances
+- Create and drop per-project databases and users
+
+Database services are named instances, so several of them, including different versions of
+the same engine, can run simultaneously.`,
 }
 
 // dbListCmd represents the db list command
 var dbListCmd = &cobra.Command{
 	Use:   "list",
-	Short: "List all database services and their enabled/disabled status",
-	Long: `List all available database services and show whether they are enabled or disabled.
+	Short: "List all database instances and their enabled/disabled status",
+	Long: `List all configured database instances with their engine, version and port, and show
+whether they are enabled or disabled.
 
 This shows the configuration status, not the running status.`,
 	RunE: runDbList,
@@ -50,21 +62,23 @@ their connection information.`,
 // dbCredentialsCmd represents the db credentials command
 var dbCredentialsCmd = &cobra.Command{
 	Use:   "credentials",
-	Short: "Show database credentials for enabled services",
-	Long: `Show database connection credentials for all enabled database services.
+	Short: "Show the credentials of the project's database or of the enabled services",
+	Long: `Show the connection credentials of the project's own database (see 'phpier db create').
 
-This displays the host, port, username, and password information needed
-to connect to each enabled database service.`,
+Outside a project, for projects without a database of their own, or with --server, this
+displays the host, port, username, and password information needed to connect to each
+enabled database service.`,
 	RunE: runDbCredentials,
 }
 
 // dbEnableCmd represents the db enable command
 var dbEnableCmd = &cobra.Command{
-	Use:   "enable [mysql|postgresql|mariadb]",
-	Short: "Enable a database service",
-	Long: `Enable a specific database service (MySQL, PostgreSQL, or MariaDB).
+	Use:   "enable <instance|mysql|postgresql|mariadb>",
+	Short: "Enable a database instance",
+	Long: `Enable a database instance by its name, or the first instance of an engine (MySQL,
+PostgreSQL, or MariaDB).
 
-The service will be included in the global docker-compose configuration
+The instance will be included in the global docker-compose configuration
 and started with the next 'phpier global up' command.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runDbEnable,
@@ -72,26 +86,71 @@ and started with the next 'phpier global up' command.`,
 
 // dbDisableCmd represents the db disable command
 var dbDisableCmd = &cobra.Command{
-	Use:   "disable [mysql|postgresql|mariadb]",
-	Short: "Disable a database service",
-	Long: `Disable a specific database service (MySQL, PostgreSQL, or MariaDB).
+	Use:   "disable <instance|mysql|postgresql|mariadb>",
+	Short: "Disable a database instance",
+	Long: `Disable a database instance by its name, or the enabled instance of an engine (MySQL,
+PostgreSQL, or MariaDB). Its data volume is kept.
 
-The service will be removed from the global docker-compose configuration
+The instance will be removed from the global docker-compose configuration
 and stopped if currently running.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runDbDisable,
 }
 
+// dbAddCmd represents the db add command
+var dbAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a named database instance",
+	Long: `Add a database instance, e.g. a second MySQL server of another version. It runs in
+its own phpier-<name> container with its own data volume, and projects reach it by its
+name on the shared network.
+
+Without --port, the first port after the engine's default that no other instance uses
+is published on the host.
+
+Examples:
+  phpier db add legacy --engine mysql --version 5.7
+  phpier db add pg16 --engine postgresql --version 16 --port 5433`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDbAdd,
+}
+
+// dbRemoveCmd represents the db remove command
+var dbRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a named database instance",
+	Long: `Remove a database instance from the global configuration. 'phpier global up' stops
+its container; its data volume is kept, so adding it again under the same name brings the
+data back.
+
+The databases of the projects on the instance go away with it, so the removal is refused
+while a known project uses it, unless --force is given. Those projects then need a database
+on another instance ('phpier db drop' and 'phpier db create --instance <name>').`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDbRemove,
+}
+
 func runDbList(cmd *cobra.Command, args []string) error {
 	globalConfig, err := config.LoadGlobalConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load global config: %w", err)
 	}
 
+	if len(globalConfig.Services.Databases) == 0 {
+		fmt.Println("No database instances are configured.")
+		fmt.Println("Use 'phpier db add <name> --engine mysql' to add one.")
+		return nil
+	}
+
 	fmt.Println("Database Services:")
-	fmt.Printf("✓ MySQL      [%s]\n", getEnabledStatus(globalConfig.Services.Databases.MySQL.Enabled))
-	fmt.Printf("✓ PostgreSQL [%s]\n", getEnabledStatus(globalConfig.Services.Databases.PostgreSQL.Enabled))
-	fmt.Printf("✓ MariaDB    [%s]\n", getEnabledStatus(globalConfig.Services.Databases.MariaDB.Enabled))
+	for _, instance := range globalConfig.Services.Databases {
+		mark := "✓"
+		if !instance.Enabled {
+			mark = "✗"
+		}
+		fmt.Printf("%s %-12s %-10s %-8s port %-6d [%s]\n", mark, instance.Name, config.DatabaseEngineLabel(instance.Engine),
+			instance.Version, instance.Port, getEnabledStatus(instance.Enabled))
+	}
 
 	return nil
 }
@@ -104,37 +163,42 @@ func runDbStatus(cmd *cobra.Command, args []string) error {
 
 	fmt.Println("Database Services Status:")
 
-	// Check MySQL
-	if globalConfig.Services.Databases.MySQL.Enabled {
-		running := checkDatabaseRunning("mysql")
-		fmt.Printf("✓ MySQL      [enabled]  [%s]   localhost:%d\n",
-			getRunningStatus(running), globalConfig.Services.Databases.MySQL.Port)
-	} else {
-		fmt.Printf("✗ MySQL      [disabled] [stopped]   localhost:%d\n",
-			globalConfig.Services.Databases.MySQL.Port)
+	for _, instance := range globalConfig.Services.Databases {
+		printDatabaseStatus(cmd.Context(), fmt.Sprintf("%-24s", instance.Label()), instance.Name, instance, globalConfig)
 	}
 
-	// Check PostgreSQL
-	if globalConfig.Services.Databases.PostgreSQL.Enabled {
-		running := checkDatabaseRunning("postgres")
-		fmt.Printf("✓ PostgreSQL [enabled]  [%s]   localhost:%d\n",
-			getRunningStatus(running), globalConfig.Services.Databases.PostgreSQL.Port)
-	} else {
-		fmt.Printf("✗ PostgreSQL [disabled] [stopped]   localhost:%d\n",
-			globalConfig.Services.Databases.PostgreSQL.Port)
+	if globalConfig.Traefik.TCPProxy() {
+		fmt.Println("\nConnections go through Traefik TCP entrypoints (traefik.tcp_mode: proxy).")
 	}
 
-	// Check MariaDB
-	if globalConfig.Services.Databases.MariaDB.Enabled {
-		running := checkDatabaseRunning("mariadb")
-		fmt.Printf("✓ MariaDB    [enabled]  [%s]   localhost:%d\n",
-			getRunningStatus(running), globalConfig.Services.Databases.MariaDB.Port)
-	} else {
-		fmt.Printf("✗ MariaDB    [disabled] [stopped]   localhost:%d\n",
-			globalConfig.Services.Databases.MariaDB.Port)
+	return nil
+}
+
+// printDatabaseStatus prints one database status line including the connection path in use
+func printDatabaseStatus(ctx context.Context, label, serviceName string, dbConfig config.DatabaseServiceConfig, globalConfig *config.GlobalConfig) {
+	if !dbConfig.Enabled {
+		fmt.Printf("✗ %s [disabled] [stopped]   localhost:%d\n", label, dbConfig.Port)
+		return
 	}
 
-	return nil
+	running, health := checkDatabaseState(ctx, serviceName)
+	fmt.Printf("✓ %s [enabled]  [%s]   localhost:%d   %s\n",
+		label, getRunningStatus(running), dbConfig.Port, getConnectionPath(ctx, serviceName, running, health, globalConfig))
+}
+
+// getConnectionPath describes how the host reaches a database service
+func getConnectionPath(ctx context.Context, serviceName string, running bool, health string, globalConfig *config.GlobalConfig) string {
+	if !globalConfig.Traefik.TCPProxy() {
+		return "direct"
+	}
+
+	if traefikRunning, _ := checkDatabaseState(ctx, "traefik"); !traefikRunning {
+		return "via proxy (traefik stopped)"
+	}
+	if running && health != "" && health != "healthy" {
+		return fmt.Sprintf("via proxy (traefik:%s, %s - not routed yet)", serviceName, health)
+	}
+	return fmt.Sprintf("via proxy (traefik:%s)", serviceName)
 }
 
 func runDbCredentials(cmd *cobra.Command, args []string) error {
@@ -143,6 +207,14 @@ func runDbCredentials(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load global config: %w", err)
 	}
 
+	if projectCfg := loadProjectConfigIfInitialized(); !dbCredentialsServer && projectCfg != nil && projectCfg.Database.Enabled() {
+		serverConfig, _ := globalConfig.GetDatabaseService(projectCfg.Database.Server())
+		fmt.Printf("Database Credentials of %s:\n", projectCfg.Name)
+		fmt.Println()
+		printDatabaseCredentials(projectCfg.Database, serverConfig)
+		return nil
+	}
+
 	enabled := globalConfig.GetEnabledDatabases()
 	if len(enabled) == 0 {
 		fmt.Println("No database services are currently enabled.")
@@ -153,9 +225,9 @@ func runDbCredentials(cmd *cobra.Command, args []string) error {
 	fmt.Println("Database Credentials (enabled services only):")
 	fmt.Println()
 
-	for dbType, dbConfig := range enabled {
-		fmt.Printf("%s:\n", strings.Title(dbType))
-		fmt.Printf("  Host:     localhost:%d\n", dbConfig.Port)
+	for _, dbConfig := range enabled {
+		fmt.Printf("%s:\n", dbConfig.Label())
+		fmt.Printf("  Host:     localhost:%d (%s:%d from containers)\n", dbConfig.Port, dbConfig.Name, dbConfig.ContainerPort())
 		fmt.Printf("  Username: %s\n", dbConfig.Username)
 		fmt.Printf("  Password: %s\n", dbConfig.Password)
 		fmt.Println()
@@ -165,89 +237,185 @@ func runDbCredentials(cmd *cobra.Command, args []string) error {
 }
 
 func runDbEnable(cmd *cobra.Command, args []string) error {
-	dbType := strings.ToLower(args[0])
-
-	if !isValidDatabaseType(dbType) {
-		return fmt.Errorf("invalid database type '%s'. Valid options: mysql, postgresql, mariadb", dbType)
-	}
+	name := strings.ToLower(args[0])
 
 	globalConfig, err := config.LoadGlobalConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load global config: %w", err)
 	}
 
+	instance, ok := globalConfig.GetDatabaseService(name)
+	if !ok {
+		if !isValidDatabaseType(name) {
+			return unknownDatabaseInstanceError(globalConfig, name)
+		}
+		// The engine's default instance was removed; bring it back
+		instance = config.NewDatabaseInstance(config.DatabaseServiceName(name), name)
+	}
+
 	// Check if already enabled
-	if globalConfig.IsDatabaseEnabled(dbType) {
-		fmt.Printf("%s is already enabled.\n", strings.Title(dbType))
+	if instance.Enabled {
+		fmt.Printf("%s is already enabled.\n", instance.Label())
 		return nil
 	}
 
-	// Enable the service with updated defaults
-	switch dbType {
-	case "mysql":
-		globalConfig.Services.Databases.MySQL.Enabled = true
-		// Apply updated defaults if not already set
-		if globalConfig.Services.Databases.MySQL.Username == "root" {
-			globalConfig.Services.Databases.MySQL.Username = "phpier"
-			globalConfig.Services.Databases.MySQL.Password = "phpier"
-			globalConfig.Services.Databases.MySQL.Database = "phpier"
-		}
-	case "postgresql":
-		globalConfig.Services.Databases.PostgreSQL.Enabled = true
-	case "mariadb":
-		globalConfig.Services.Databases.MariaDB.Enabled = true
+	// Enable the instance with updated defaults
+	instance.Enabled = true
+	if instance.Engine == "mysql" && instance.Username == "root" {
+		instance.Username, instance.Password, instance.Database = "phpier", "phpier", "phpier"
 	}
+	globalConfig.SetDatabaseService(instance)
 
 	// Save the updated configuration
 	if err := config.SaveGlobalConfig(globalConfig); err != nil {
 		return fmt.Errorf("failed to save global config: %w", err)
 	}
 
-	fmt.Printf("✓ %s has been enabled.\n", strings.Title(dbType))
+	fmt.Printf("✓ %s has been enabled.\n", instance.Label())
 	fmt.Println("Run 'phpier global up' to start the service.")
 
 	return nil
 }
 
 func runDbDisable(cmd *cobra.Command, args []string) error {
-	dbType := strings.ToLower(args[0])
-
-	if !isValidDatabaseType(dbType) {
-		return fmt.Errorf("invalid database type '%s'. Valid options: mysql, postgresql, mariadb", dbType)
-	}
+	name := strings.ToLower(args[0])
 
 	globalConfig, err := config.LoadGlobalConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load global config: %w", err)
 	}
 
+	instance, ok := globalConfig.GetDatabaseService(name)
+	if !ok {
+		return unknownDatabaseInstanceError(globalConfig, name)
+	}
+
 	// Check if already disabled
-	if !globalConfig.IsDatabaseEnabled(dbType) {
-		fmt.Printf("%s is already disabled.\n", strings.Title(dbType))
+	if !instance.Enabled {
+		fmt.Printf("%s is already disabled.\n", instance.Label())
 		return nil
 	}
 
-	// Disable the service
-	switch dbType {
-	case "mysql":
-		globalConfig.Services.Databases.MySQL.Enabled = false
-	case "postgresql":
-		globalConfig.Services.Databases.PostgreSQL.Enabled = false
-	case "mariadb":
-		globalConfig.Services.Databases.MariaDB.Enabled = false
-	}
+	// Disable the instance
+	instance.Enabled = false
+	globalConfig.SetDatabaseService(instance)
 
 	// Save the updated configuration
 	if err := config.SaveGlobalConfig(globalConfig); err != nil {
 		return fmt.Errorf("failed to save global config: %w", err)
 	}
 
-	fmt.Printf("✗ %s has been disabled.\n", strings.Title(dbType))
+	fmt.Printf("✗ %s has been disabled.\n", instance.Label())
 	fmt.Println("Run 'phpier global up' to apply changes (the service will be stopped).")
 
 	return nil
 }
 
+func runDbAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	engine := strings.ToLower(dbAddEngine)
+	if !isValidDatabaseType(engine) {
+		return errors.NewInvalidDatabaseTypeError(engine, config.DatabaseTypes)
+	}
+	if err := config.ValidateDatabaseInstanceName(name); err != nil {
+		return errors.NewInvalidArgumentsError(err.Error())
+	}
+
+	globalConfig, err := config.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load global config: %w", err)
+	}
+	if _, exists := globalConfig.Services.Databases.Get(name); exists {
+		return errors.NewInvalidArgumentsError(fmt.Sprintf("database instance '%s' already exists", name)).
+			WithSuggestion(fmt.Sprintf("Pick another name, or 'phpier db remove %s' first", name))
+	}
+
+	instance := config.NewDatabaseInstance(name, engine)
+	instance.Enabled = true
+	if dbAddVersion != "" {
+		instance.Version = dbAddVersion
+	}
+	if dbAddPort != 0 {
+		instance.Port = dbAddPort
+	} else {
+		instance.Port = nextDatabasePort(globalConfig, instance.Port)
+	}
+	for _, other := range globalConfig.Services.Databases {
+		if other.Port == instance.Port {
+			return errors.NewInvalidArgumentsError(fmt.Sprintf("port %d is used by database instance '%s'", instance.Port, other.Name)).
+				WithSuggestion("Pick another one with --port")
+		}
+	}
+	globalConfig.SetDatabaseService(instance)
+
+	if err := config.SaveGlobalConfig(globalConfig); err != nil {
+		return fmt.Errorf("failed to save global config: %w", err)
+	}
+
+	fmt.Printf("✓ Added %s on port %d.\n", instance.Label(), instance.Port)
+	fmt.Printf("Run 'phpier global up' to start it; use --instance %s with the db, mysql, psql and mariadb commands.\n", name)
+	return nil
+}
+
+func runDbRemove(cmd *cobra.Command, args []string) error {
+	globalConfig, err := config.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load global config: %w", err)
+	}
+
+	instance, ok := globalConfig.Services.Databases.Get(args[0])
+	if !ok {
+		return unknownDatabaseInstanceError(globalConfig, args[0])
+	}
+	if projects := databaseInstanceProjects(globalConfig, instance); len(projects) > 0 {
+		if !dbForce {
+			return errors.NewPhpierError(errors.ErrorTypeInvalidArguments, fmt.Sprintf("%s holds the databases of projects", instance.Label())).
+				WithContext("projects", strings.Join(projects, ", ")).
+				WithSuggestion("Move their databases to another instance first, or use --force to remove it anyway")
+		}
+		fmt.Printf("⚠️  The databases of %s go away with %s.\n", strings.Join(projects, ", "), instance.Label())
+	}
+	globalConfig.RemoveDatabaseService(instance.Name)
+
+	if err := config.SaveGlobalConfig(globalConfig); err != nil {
+		return fmt.Errorf("failed to save global config: %w", err)
+	}
+
+	fmt.Printf("✗ Removed %s; its data volume %s_data is kept.\n", instance.Label(), instance.Name)
+	fmt.Println("Run 'phpier global up' to apply changes (the service will be stopped).")
+	return nil
+}
+
+// databaseInstanceProjects returns the names of the known projects whose database is on instance
+func databaseInstanceProjects(globalConfig *config.GlobalConfig, instance config.DatabaseServiceConfig) []string {
+	var projects []string
+	for _, project := range config.DiscoverAllProjects() {
+		if project.Path == "" {
+			continue
+		}
+		projectCfg, err := config.LoadProjectConfigFromPath(project.Path)
+		if err != nil || !projectCfg.Database.Enabled() {
+			continue
+		}
+		if server, ok := globalConfig.GetDatabaseService(projectCfg.Database.Server()); ok && server.Name == instance.Name {
+			projects = append(projects, project.Name)
+		}
+	}
+	return projects
+}
+
+// nextDatabasePort returns the first port from port up that no database instance uses
+func nextDatabasePort(globalConfig *config.GlobalConfig, port int) int {
+	used := make(map[int]bool)
+	for _, instance := range globalConfig.Services.Databases {
+		used[instance.Port] = true
+	}
+	for used[port] {
+		port++
+	}
+	return port
+}
+
 // Helper functions
 
 func getEnabledStatus(enabled bool) string {
@@ -264,30 +432,74 @@ func getRunningStatus(running bool) string {
 	return "stopped"
 }
 
-func checkDatabaseRunning(serviceName string) bool {
-	dockerClient, err := docker.NewClient()
+// checkDatabaseState returns whether a global service container is running and its healthcheck status
+func checkDatabaseState(ctx context.Context, serviceName string) (bool, string) {
+	dockerClient, err := docker.NewClient(ctx, commandTimeout)
 	if err != nil {
-		return false
+		return false, ""
 	}
 	defer dockerClient.Close()
 
-	ctx := context.Background()
 	containerID, err := dockerClient.GetContainerID("phpier", serviceName)
 	if err != nil {
-		return false
+		return false, ""
 	}
 
 	running, err := dockerClient.IsContainerRunningByID(ctx, containerID)
+	if err != nil || !running {
+		return false, ""
+	}
+
+	health, err := dockerClient.ContainerHealth(ctx, containerID)
+	if err != nil {
+		return true, ""
+	}
+
+	return true, health
+}
+
+// findDatabaseContainer returns the ID of the running container of a global database service,
+// or an error that says how to start it
+func findDatabaseContainer(ctx context.Context, dockerClient *docker.Client, label, serviceName string) (string, error) {
+	logrus.Debugf("Looking for %s container", label)
+
+	containerID, err := dockerClient.GetContainerID("phpier", serviceName)
 	if err != nil {
-		return false
+		// Check if it's a container not found error vs other errors
+		if strings.Contains(err.Error(), "Container not found") {
+			return "", fmt.Errorf("%s container is not running\n\nTry running 'phpier global up' to start the global services", label)
+		}
+		return "", fmt.Errorf("failed to find %s container: %w\n\nMake sure Docker is running and try 'phpier global up' to start the services", label, err)
+	}
+
+	logrus.Debugf("Found %s container ID: %s", label, containerID)
+
+	isRunning, err := dockerClient.IsContainerRunningByID(ctx, containerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to check %s container status: %w", label, err)
+	}
+	if !isRunning {
+		return "", fmt.Errorf("%s container is not running\n\nTry running 'phpier global up' to start the global services", label)
 	}
 
-	return running
+	return containerID, nil
+}
+
+// shellDatabaseInstance returns the instance of engine a database shell connects to: the one
+// --instance names, the project's when it runs engine, or else the engine's first
+func shellDatabaseInstance(globalConfig *config.GlobalConfig, engine, requestedInstance string) (config.DatabaseServiceConfig, error) {
+	if requestedInstance == "" {
+		if projectCfg := loadProjectConfigIfInitialized(); projectCfg != nil && projectCfg.Database.Enabled() {
+			if instance, ok := globalConfig.GetDatabaseService(projectCfg.Database.Server()); ok && instance.Engine == engine {
+				requestedInstance = instance.Name
+			}
+		}
+	}
+	return selectDatabaseInstance(globalConfig, nil, engine, requestedInstance)
 }
 
 func isValidDatabaseType(dbType string) bool {
-	validTypes := []string{"mysql", "postgresql", "mariadb"}
-	for _, valid := range validTypes {
+	for _, valid := range config.DatabaseTypes {
 		if dbType == valid {
 			return true
 		}
@@ -302,4 +514,14 @@ func init() {
 	dbCmd.AddCommand(dbCredentialsCmd)
 	dbCmd.AddCommand(dbEnableCmd)
 	dbCmd.AddCommand(dbDisableCmd)
+	dbCmd.AddCommand(dbAddCmd)
+	dbCmd.AddCommand(dbRemoveCmd)
+
+	dbRemoveCmd.Flags().BoolVarP(&dbForce, "force", "f", false, "Remove the instance even when projects use it")
+
+	dbAddCmd.Flags().StringVar(&dbAddEngine, "engine", "mysql", "Database engine: mysql, postgresql or mariadb")
+	dbAddCmd.Flags().StringVar(&dbAddVersion, "version", "", "Image version (default: the engine's default, e.g. 8.0 or 15)")
+	dbAddCmd.Flags().IntVar(&dbAddPort, "port", 0, "Port published on the host (default: the first free one from the engine's)")
+
+	dbCredentialsCmd.Flags().BoolVar(&dbCredentialsServer, "server", false, "Show the credentials of the enabled services instead of the project's database")
 }
@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"phpier/internal/config"
+	"phpier/internal/docker"
+	"phpier/internal/errors"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbEngine   string
+	dbInstance string
+	dbUser     string
+	dbPassword string
+	dbForce    bool
+)
+
+// dbCreateCmd represents the db create command
+var dbCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a database and its user on a shared database server",
+	Long: `Create a database and a user with all privileges on it on one of the shared
+database servers, so projects don't share tables.
+
+Without a name, the project's own database is created: the one recorded under
+'database' in .phpier.yml, or a new one named after the project, which is then recorded
+there. Running it again keeps the data and resets the user's password to the recorded one.
+
+Examples:
+  phpier db create                        # The project's database
+  phpier db create shop_test              # Another database, with a user of the same name
+  phpier db create reports --engine postgresql --user analyst
+  phpier db create legacy_shop --instance legacy  # On a named database instance`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDbCreate,
+}
+
+// dbDropCmd represents the db drop command
+var dbDropCmd = &cobra.Command{
+	Use:   "drop [name]",
+	Short: "Drop a database and its user from a shared database server",
+	Long: `Drop a database and its user. Without a name, the project's own database is dropped
+and removed from .phpier.yml.
+
+The data is gone for good; you are asked to confirm unless --force is given.
+
+Examples:
+  phpier db drop                 # The project's database
+  phpier db drop shop_test -f    # Another database, without asking`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDbDrop,
+}
+
+// dbUsersCmd represents the db users command
+var dbUsersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "List the users of the database instances and their databases",
+	Long: `List the users of each running database instance, or only of --instance or --engine,
+with the databases each of them has access to.`,
+	Args: cobra.NoArgs,
+	RunE: runDbUsers,
+}
+
+func init() {
+	dbCmd.AddCommand(dbCreateCmd)
+	dbCmd.AddCommand(dbDropCmd)
+	dbCmd.AddCommand(dbUsersCmd)
+
+	for _, c := range []*cobra.Command{dbCreateCmd, dbDropCmd, dbUsersCmd} {
+		addDatabaseInstanceFlags(c)
+	}
+	dbCreateCmd.Flags().StringVar(&dbUser, "user", "", "User to create (default: the database name)")
+	dbCreateCmd.Flags().StringVar(&dbPassword, "password", "", "Password of the user (default: generated)")
+	dbDropCmd.Flags().StringVar(&dbUser, "user", "", "User to drop with the database (default: the database name)")
+	dbDropCmd.Flags().BoolVarP(&dbForce, "force", "f", false, "Skip the confirmation prompt")
+}
+
+func runDbCreate(cmd *cobra.Command, args []string) error {
+	globalConfig, err := config.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg := loadProjectConfigIfInitialized()
+
+	var db config.ProjectDatabase
+	var instance config.DatabaseServiceConfig
+	record := false
+	if len(args) == 0 {
+		if projectCfg == nil {
+			return errors.NewInvalidArgumentsError("a database name is required outside a phpier project")
+		}
+		db = projectCfg.Database
+		if !db.Enabled() {
+			if instance, err = selectDatabaseInstance(globalConfig, projectCfg, dbEngine, dbInstance); err != nil {
+				return err
+			}
+			if db, err = config.NewProjectDatabase(projectCfg.Name, instance); err != nil {
+				return err
+			}
+			record = true
+		} else if instance, err = selectDatabaseInstance(globalConfig, projectCfg, "", ""); err != nil {
+			return err
+		}
+	} else {
+		if instance, err = selectDatabaseInstance(globalConfig, projectCfg, dbEngine, dbInstance); err != nil {
+			return err
+		}
+		db = config.ProjectDatabase{Engine: instance.Engine, Instance: instance.Name, Name: args[0], Username: dbUser, Password: dbPassword}
+		if db.Username == "" {
+			db.Username = db.Name
+		}
+		if db.Password == "" {
+			if db.Password, err = config.GenerateDatabasePassword(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := validateProjectDatabase(db); err != nil {
+		return err
+	}
+
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	server := databaseServer(instance)
+	if err := dockerClient.CreateDatabase(cmd.Context(), server, db); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Created database '%s' and user '%s' on %s.\n", db.Name, db.Username, instance.Label())
+
+	if record {
+		projectCfg.Database = db
+		if err := config.SaveProjectConfig(".phpier.yml", projectCfg); err != nil {
+			return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to record the database in .phpier.yml", err)
+		}
+		fmt.Println("Recorded it under 'database' in .phpier.yml.")
+		fmt.Println("Run 'phpier build --regenerate' and 'phpier up' to pass the DB_* variables to the app.")
+	}
+	fmt.Println()
+	printDatabaseCredentials(db, server.Config)
+	return nil
+}
+
+func runDbDrop(cmd *cobra.Command, args []string) error {
+	globalConfig, err := config.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg := loadProjectConfigIfInitialized()
+
+	var instance config.DatabaseServiceConfig
+	var name, username string
+	forget := false
+	switch {
+	case len(args) == 0:
+		if projectCfg == nil || !projectCfg.Database.Enabled() {
+			return errors.NewInvalidArgumentsError("a database name is required when the project has no database of its own")
+		}
+		if instance, err = selectDatabaseInstance(globalConfig, projectCfg, "", ""); err != nil {
+			return err
+		}
+		name, username = projectCfg.Database.Name, projectCfg.Database.Username
+		forget = true
+	default:
+		if instance, err = selectDatabaseInstance(globalConfig, projectCfg, dbEngine, dbInstance); err != nil {
+			return err
+		}
+		name, username = args[0], dbUser
+		if username == "" {
+			username = name
+		}
+		// Dropping the project's database by name forgets it too
+		forget = projectCfg != nil && projectCfg.Database.Name == name && isProjectDatabaseInstance(globalConfig, projectCfg, instance)
+	}
+	if err := config.ValidateDatabaseIdentifier("database", name); err != nil {
+		return errors.NewInvalidArgumentsError(err.Error())
+	}
+	if err := config.ValidateDatabaseIdentifier("user", username); err != nil {
+		return errors.NewInvalidArgumentsError(err.Error())
+	}
+	if err := docker.CheckDroppableDatabase(databaseServer(instance), name); err != nil {
+		return err
+	}
+
+	if !dbForce {
+		fmt.Printf("Drop database '%s' and user '%s' from %s? All of its data is deleted. [y/N] ", name, username, instance.Label())
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	if err := dockerClient.DropDatabase(cmd.Context(), databaseServer(instance), name, username); err != nil {
+		return err
+	}
+	fmt.Printf("✗ Dropped database '%s' and user '%s' from %s.\n", name, username, instance.Label())
+
+	if forget {
+		projectCfg.Database = config.ProjectDatabase{}
+		if err := config.SaveProjectConfig(".phpier.yml", projectCfg); err != nil {
+			return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to remove the database from .phpier.yml", err)
+		}
+		fmt.Println("Removed it from .phpier.yml; 'phpier build --regenerate' drops the DB_* variables of the app.")
+	}
+	return nil
+}
+
+func runDbUsers(cmd *cobra.Command, args []string) error {
+	globalConfig, err := config.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load global config: %w", err)
+	}
+
+	instances := globalConfig.GetEnabledDatabases()
+	if dbEngine != "" || dbInstance != "" {
+		instance, err := selectDatabaseInstance(globalConfig, nil, dbEngine, dbInstance)
+		if err != nil {
+			return err
+		}
+		instances = []config.DatabaseServiceConfig{instance}
+	}
+	if len(instances) == 0 {
+		fmt.Println("No database services are currently enabled.")
+		return nil
+	}
+
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	fmt.Printf("%-12s %-24s %s\n", "INSTANCE", "USER", "DATABASES")
+	for _, instance := range instances {
+		users, err := dockerClient.DatabaseUsers(cmd.Context(), databaseServer(instance))
+		if err != nil {
+			if dbEngine != "" || dbInstance != "" {
+				return err
+			}
+			fmt.Printf("%-12s %s\n", instance.Name, "(not running)")
+			continue
+		}
+		for _, user := range users {
+			databases := strings.Join(user.Databases, ", ")
+			if databases == "" {
+				databases = "-"
+			}
+			fmt.Printf("%-12s %-24s %s\n", instance.Name, user.Name, databases)
+		}
+	}
+	return nil
+}
+
+// loadProjectConfigIfInitialized returns the project config of the current directory, or nil
+// outside a phpier project
+func loadProjectConfigIfInitialized() *config.ProjectConfig {
+	if !isProjectInitialized() {
+		return nil
+	}
+	projectCfg, err := config.LoadProjectConfig()
+	if err != nil {
+		return nil
+	}
+	return projectCfg
+}
+
+// addDatabaseInstanceFlags adds the --engine and --instance flags that pick a database instance
+func addDatabaseInstanceFlags(c *cobra.Command) {
+	c.Flags().StringVar(&dbEngine, "engine", "", "Database engine: mysql, postgresql or mariadb (default: the project's, or the first enabled)")
+	c.Flags().StringVar(&dbInstance, "instance", "", "Database instance, e.g. legacy (default: the first enabled of the engine)")
+}
+
+// selectDatabaseInstance returns the requested instance, the first enabled instance of the
+// requested engine, the instance of the project's database or the first enabled one, and
+// checks that it is enabled
+func selectDatabaseInstance(globalConfig *config.GlobalConfig, projectCfg *config.ProjectConfig, requestedEngine, requestedInstance string) (config.DatabaseServiceConfig, error) {
+	engine := strings.ToLower(requestedEngine)
+	if engine != "" && !isValidDatabaseType(engine) {
+		return config.DatabaseServiceConfig{}, errors.NewInvalidDatabaseTypeError(engine, config.DatabaseTypes)
+	}
+
+	var instance config.DatabaseServiceConfig
+	var ok bool
+	switch {
+	case requestedInstance != "":
+		if instance, ok = globalConfig.Services.Databases.Get(requestedInstance); !ok {
+			return instance, unknownDatabaseInstanceError(globalConfig, requestedInstance)
+		}
+		if engine != "" && instance.Engine != engine {
+			return instance, errors.NewInvalidArgumentsError(fmt.Sprintf("database instance '%s' runs %s, not %s", instance.Name, instance.Engine, engine))
+		}
+	case engine != "":
+		if instance, ok = globalConfig.GetDatabaseService(engine); !ok {
+			return instance, errors.NewPhpierError(errors.ErrorTypeInvalidConfig, fmt.Sprintf("No %s instance is configured", config.DatabaseEngineLabel(engine))).
+				WithSuggestion(fmt.Sprintf("Add one with 'phpier db add <name> --engine %s', then run 'phpier global up'", engine))
+		}
+	case projectCfg != nil && projectCfg.Database.Enabled():
+		if instance, ok = globalConfig.GetDatabaseService(projectCfg.Database.Server()); !ok {
+			return instance, unknownDatabaseInstanceError(globalConfig, projectCfg.Database.Server())
+		}
+	default:
+		enabled := globalConfig.GetEnabledDatabases()
+		if len(enabled) == 0 {
+			return instance, errors.NewPhpierError(errors.ErrorTypeInvalidConfig, "No database services are enabled").
+				WithSuggestion("Enable one with 'phpier db enable mysql', then run 'phpier global up'")
+		}
+		instance = enabled[0]
+	}
+
+	if !instance.Enabled {
+		return instance, errors.NewPhpierError(errors.ErrorTypeInvalidConfig, fmt.Sprintf("%s is not enabled", instance.Label())).
+			WithSuggestion(fmt.Sprintf("Enable it with 'phpier db enable %s', then run 'phpier global up'", instance.Name))
+	}
+	return instance, nil
+}
+
+// unknownDatabaseInstanceError says which database instances there are
+func unknownDatabaseInstanceError(globalConfig *config.GlobalConfig, name string) error {
+	var names []string
+	for _, instance := range globalConfig.Services.Databases {
+		names = append(names, instance.Name)
+	}
+	return errors.NewPhpierError(errors.ErrorTypeInvalidConfig, fmt.Sprintf("Database instance '%s' not found", name)).
+		WithContext("instances", strings.Join(names, ", ")).
+		WithSuggestion("Run 'phpier db list' to see the instances, or 'phpier db add' to add one")
+}
+
+// isProjectDatabaseInstance reports whether the project's database is on instance
+func isProjectDatabaseInstance(globalConfig *config.GlobalConfig, projectCfg *config.ProjectConfig, instance config.DatabaseServiceConfig) bool {
+	if projectCfg == nil || !projectCfg.Database.Enabled() {
+		return false
+	}
+	projectInstance, ok := globalConfig.GetDatabaseService(projectCfg.Database.Server())
+	return ok && projectInstance.Name == instance.Name
+}
+
+// databaseServer returns the server of a database instance
+func databaseServer(instance config.DatabaseServiceConfig) docker.DatabaseServer {
+	return docker.DatabaseServer{Engine: instance.Engine, Config: instance}
+}
+
+// validateProjectDatabase checks the names of a database and its user
+func validateProjectDatabase(db config.ProjectDatabase) error {
+	if err := config.ValidateDatabaseIdentifier("database", db.Name); err != nil {
+		return errors.NewInvalidArgumentsError(err.Error())
+	}
+	if err := config.ValidateDatabaseIdentifier("user", db.Username); err != nil {
+		return errors.NewInvalidArgumentsError(err.Error())
+	}
+	if strings.ContainsAny(db.Password, "'\\") {
+		return errors.NewInvalidArgumentsError("the password can't contain quotes or backslashes")
+	}
+	return nil
+}
+
+// printDatabaseCredentials prints how the host and the app container connect to a database
+func printDatabaseCredentials(db config.ProjectDatabase, serverConfig config.DatabaseServiceConfig) {
+	fmt.Printf("%s:\n", serverConfig.Label())
+	fmt.Printf("  Database: %s\n", db.Name)
+	fmt.Printf("  Username: %s\n", db.Username)
+	fmt.Printf("  Password: %s\n", db.Password)
+	fmt.Printf("  Host:     localhost:%d (from the host)\n", serverConfig.Port)
+	fmt.Printf("            %s:%d (from the app container)\n", db.Host(), db.Port())
+}
@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"phpier/internal/config"
+	"phpier/internal/dbdump"
+	"phpier/internal/display"
+	"phpier/internal/docker"
+	"phpier/internal/errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	dbDumpOutput   string
+	dbDumpCompress string
+	dbDumpInput    string
+	dbDumpDrop     bool
+)
+
+// dbExportCmd represents the db export command
+var dbExportCmd = &cobra.Command{
+	Use:   "export [database]",
+	Short: "Stream a SQL dump of a database to stdout or a file",
+	Long: `Dump a database of a shared database server (mysqldump, mariadb-dump or pg_dump in
+its container) and stream it to stdout or --output, without temporary files.
+
+Without a name, the project's own database is exported, or the server's default
+database outside a project. Dumps written to stdout are gzip compressed unless --compress
+says otherwise; with --output the file extension picks the compression: .sql, .gz or .zst
+(zstd needs the zstd command on the host).
+
+Examples:
+  phpier db export > dump.sql.gz              # The project's database, gzip compressed
+  phpier db export shop --compress none > shop.sql
+  phpier db export -o backups/shop.sql.zst    # zstd compressed file
+  phpier db export reports --engine postgresql -o reports.sql.gz
+  phpier db export shop --instance legacy > legacy.sql.gz`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDbExport,
+}
+
+// dbImportCmd represents the db import command
+var dbImportCmd = &cobra.Command{
+	Use:   "import [database]",
+	Short: "Import a SQL dump from stdin or a file into a database",
+	Long: `Stream a SQL dump from stdin or --input into a database of a shared database server,
+without temporary files. Plain, gzip and zstd compressed dumps are recognized by their
+content, whatever the file is called.
+
+Without a name, the dump is imported into the project's own database, or the server's
+default database outside a project. --drop empties the database first by dropping and
+creating it again; users keep their access to it.
+
+Examples:
+  phpier db import < dump.sql.gz              # Into the project's database
+  phpier db import shop --drop -i shop.sql.zst
+  gunzip -c dump.sql.gz | phpier db import shop_test`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDbImport,
+}
+
+func init() {
+	dbCmd.AddCommand(dbExportCmd)
+	dbCmd.AddCommand(dbImportCmd)
+
+	for _, c := range []*cobra.Command{dbExportCmd, dbImportCmd} {
+		addDatabaseInstanceFlags(c)
+	}
+	dbExportCmd.Flags().StringVarP(&dbDumpOutput, "output", "o", "", "Write the dump to this file instead of stdout")
+	dbExportCmd.Flags().StringVar(&dbDumpCompress, "compress", "", "Compression: none, gzip or zstd (default: from the --output extension, gzip on stdout)")
+	dbImportCmd.Flags().StringVarP(&dbDumpInput, "input", "i", "", "Read the dump from this file instead of stdin")
+	dbImportCmd.Flags().BoolVar(&dbDumpDrop, "drop", false, "Drop and recreate the database before importing")
+}
+
+func runDbExport(cmd *cobra.Command, args []string) error {
+	compression := dbdump.Gzip
+	if dbDumpOutput != "" {
+		compression = dbdump.CompressionFromPath(dbDumpOutput)
+	}
+	if dbDumpCompress != "" {
+		var err error
+		if compression, err = dbdump.ParseCompression(dbDumpCompress); err != nil {
+			return errors.NewInvalidArgumentsError(err.Error())
+		}
+	}
+	if dbDumpOutput == "" && isTerminal(os.Stdout) {
+		return errors.NewInvalidArgumentsError("refusing to write the dump to the terminal").
+			WithSuggestion("Redirect it to a file ('phpier db export > dump.sql.gz') or use --output")
+	}
+
+	dockerClient, server, database, err := prepareDatabaseTransfer(cmd, dbEngine, dbInstance, firstArg(args))
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	var out io.Writer = os.Stdout
+	if dbDumpOutput != "" {
+		file, err := os.Create(dbDumpOutput)
+		if err != nil {
+			return errors.WrapError(errors.ErrorTypeFileSystemError, fmt.Sprintf("Failed to create %s", dbDumpOutput), err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	started := time.Now()
+	sqlBytes, streamBytes, err := exportDump(cmd, dockerClient, server, database, out, compression)
+	if err != nil {
+		if dbDumpOutput != "" {
+			// Don't leave a truncated dump that looks like a good one
+			os.Remove(dbDumpOutput)
+		}
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, display.RenderTransferSummary("Exported", database, sqlBytes, streamBytes,
+		compression != dbdump.None, time.Since(started), display.TableOptions{ColorOutput: !viper.GetBool("no-color") && isTerminal(os.Stderr)}))
+	return nil
+}
+
+func runDbImport(cmd *cobra.Command, args []string) error {
+	in := os.Stdin
+	if dbDumpInput != "" {
+		file, err := os.Open(dbDumpInput)
+		if err != nil {
+			return errors.NewFileNotFoundError(dbDumpInput)
+		}
+		defer file.Close()
+		in = file
+	} else if isTerminal(os.Stdin) {
+		return errors.NewInvalidArgumentsError("no dump to import on stdin").
+			WithSuggestion("Redirect a dump to it ('phpier db import < dump.sql.gz') or use --input")
+	}
+
+	// The size of a redirected file gives the share read so far
+	var total int64
+	if info, err := in.Stat(); err == nil && info.Mode().IsRegular() {
+		total = info.Size()
+	}
+
+	dockerClient, server, database, err := prepareDatabaseTransfer(cmd, dbEngine, dbInstance, firstArg(args))
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	started := time.Now()
+	sqlBytes, streamBytes, compression, err := importDump(cmd, dockerClient, server, database, in, total, dbDumpDrop)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, display.RenderTransferSummary("Imported", database, sqlBytes, streamBytes,
+		compression != dbdump.None, time.Since(started), display.TableOptions{ColorOutput: !viper.GetBool("no-color") && isTerminal(os.Stderr)}))
+	return nil
+}
+
+// exportDump streams a dump of a database to out with the given compression, showing the
+// progress. It returns the size of the SQL and of what was written to out.
+func exportDump(cmd *cobra.Command, dockerClient *docker.Client, server docker.DatabaseServer, database string, out io.Writer, compression dbdump.Compression) (int64, int64, error) {
+	var sqlBytes, streamBytes dbdump.Counter
+	compressor, err := dbdump.Compress(streamBytes.Writer(out), compression)
+	if err != nil {
+		return 0, 0, errors.WrapError(errors.ErrorTypeInvalidArguments, "Failed to start the compression", err)
+	}
+
+	stop := showTransferProgress(func() string {
+		return display.RenderTransfer("Exporting", database, sqlBytes.Count(), 0)
+	})
+	err = dockerClient.DumpDatabase(cmd.Context(), server, database, sqlBytes.Writer(compressor))
+	if closeErr := compressor.Close(); err == nil {
+		err = closeErr
+	}
+	stop()
+	return sqlBytes.Count(), streamBytes.Count(), err
+}
+
+// importDump streams the plain or compressed dump read from in into a database, after
+// recreating it when drop is set, showing the progress against total, the size of in when
+// known. It returns the size of the SQL, of what was read from in and its compression.
+func importDump(cmd *cobra.Command, dockerClient *docker.Client, server docker.DatabaseServer, database string, in io.Reader, total int64, drop bool) (int64, int64, dbdump.Compression, error) {
+	var streamBytes, sqlBytes dbdump.Counter
+	reader, compression, err := dbdump.Decompress(streamBytes.Reader(in))
+	if err != nil {
+		return 0, 0, "", errors.WrapError(errors.ErrorTypeInvalidArguments, "Failed to read the dump", err)
+	}
+	defer reader.Close()
+
+	if drop {
+		if err := dockerClient.RecreateDatabase(cmd.Context(), server, database); err != nil {
+			return 0, 0, "", err
+		}
+		fmt.Fprintf(os.Stderr, "Dropped and recreated database '%s'.\n", database)
+	}
+
+	stop := showTransferProgress(func() string {
+		return display.RenderTransfer("Importing", database, streamBytes.Count(), total)
+	})
+	err = dockerClient.RestoreDatabase(cmd.Context(), server, database, sqlBytes.Reader(reader))
+	stop()
+	return sqlBytes.Count(), streamBytes.Count(), compression, err
+}
+
+// prepareDatabaseTransfer resolves the database an export or import works on, by default the
+// project's or the instance's, and finds the running container of its instance
+func prepareDatabaseTransfer(cmd *cobra.Command, requestedEngine, requestedInstance, requestedDatabase string) (*docker.Client, docker.DatabaseServer, string, error) {
+	globalConfig, err := config.LoadGlobalConfig()
+	if err != nil {
+		return nil, docker.DatabaseServer{}, "", fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg := loadProjectConfigIfInitialized()
+
+	instance, err := selectDatabaseInstance(globalConfig, projectCfg, requestedEngine, requestedInstance)
+	if err != nil {
+		return nil, docker.DatabaseServer{}, "", err
+	}
+	server := databaseServer(instance)
+
+	var database string
+	switch {
+	case requestedDatabase != "":
+		database = requestedDatabase
+	case isProjectDatabaseInstance(globalConfig, projectCfg, instance):
+		database = projectCfg.Database.Name
+	default:
+		database = server.Config.Database
+	}
+	if err := config.ValidateDatabaseIdentifier("database", database); err != nil {
+		return nil, docker.DatabaseServer{}, "", errors.NewInvalidArgumentsError(err.Error())
+	}
+
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
+	if err != nil {
+		return nil, docker.DatabaseServer{}, "", err
+	}
+	server.ContainerID, err = findDatabaseContainer(cmd.Context(), dockerClient, instance.Label(), instance.Name)
+	if err != nil {
+		dockerClient.Close()
+		return nil, docker.DatabaseServer{}, "", err
+	}
+	return dockerClient, server, database, nil
+}
+
+// showTransferProgress redraws the line render returns on stderr twice a second until the
+// returned stop is called. Nothing is drawn when stderr isn't a terminal.
+func showTransferProgress(render func() string) (stop func()) {
+	if !isTerminal(os.Stderr) {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				// Clear the progress line for the summary
+				fmt.Fprint(os.Stderr, "\r\033[K")
+				return
+			case <-ticker.C:
+				fmt.Fprint(os.Stderr, "\r\033[K"+strings.TrimSpace(render()))
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+// firstArg returns the first argument, or "" when there is none
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+// isTerminal reports whether file is a terminal rather than a pipe or a file
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
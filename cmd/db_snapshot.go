@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"time"
+
+	"phpier/internal/config"
+	"phpier/internal/dbdump"
+	"phpier/internal/display"
+	"phpier/internal/errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	dbSnapshotDatabase string
+	dbSnapshotForce    bool
+)
+
+// dbSnapshotCmd represents the db snapshot command
+var dbSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save databases as named snapshots and reset them to one",
+	Long: `Save a database as a named snapshot and restore it later, e.g. to run a migration again
+or reproduce a bug from a known state.
+
+Snapshots are gzip compressed dumps kept in ~/.phpier/snapshots, along with the engine and
+version of the server they were taken on, the database, its size and the project. They can
+only be restored on a server of the same engine and release series (MySQL 8.0, PostgreSQL 16).
+
+Examples:
+  phpier db snapshot save before-migration   # Snapshot the project's database
+  phpier db snapshot restore before-migration
+  phpier db snapshot list
+  phpier db snapshot delete before-migration`,
+}
+
+// dbSnapshotSaveCmd represents the db snapshot save command
+var dbSnapshotSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save a database as a named snapshot",
+	Long: `Save a database as a named snapshot: the project's own database by default, or the
+instance's default database outside a project. --database, --instance and --engine pick
+another one.
+
+An existing snapshot of the same name is only replaced with --force.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDbSnapshotSave,
+}
+
+// dbSnapshotRestoreCmd represents the db snapshot restore command
+var dbSnapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Reset a database to a snapshot",
+	Long: `Drop the database the snapshot was taken of, create it again and import the snapshot,
+so it is back in the state it was saved in. --database restores it into another database.
+
+The restore is refused when the server's engine or release series differs from the one the
+snapshot was taken on.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDbSnapshotRestore,
+}
+
+// dbSnapshotListCmd represents the db snapshot list command
+var dbSnapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the database snapshots",
+	Args:  cobra.NoArgs,
+	RunE:  runDbSnapshotList,
+}
+
+// dbSnapshotDeleteCmd represents the db snapshot delete command
+var dbSnapshotDeleteCmd = &cobra.Command{
+	Use:   "delete <name>...",
+	Short: "Delete database snapshots",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runDbSnapshotDelete,
+}
+
+func init() {
+	dbCmd.AddCommand(dbSnapshotCmd)
+	dbSnapshotCmd.AddCommand(dbSnapshotSaveCmd)
+	dbSnapshotCmd.AddCommand(dbSnapshotRestoreCmd)
+	dbSnapshotCmd.AddCommand(dbSnapshotListCmd)
+	dbSnapshotCmd.AddCommand(dbSnapshotDeleteCmd)
+
+	addDatabaseInstanceFlags(dbSnapshotSaveCmd)
+	dbSnapshotSaveCmd.Flags().StringVar(&dbSnapshotDatabase, "database", "", "Database to snapshot (default: the project's)")
+	dbSnapshotSaveCmd.Flags().BoolVarP(&dbSnapshotForce, "force", "f", false, "Replace an existing snapshot of the same name")
+	dbSnapshotRestoreCmd.Flags().StringVar(&dbEngine, "engine", "", "Database engine to restore on (default: the snapshot's)")
+	dbSnapshotRestoreCmd.Flags().StringVar(&dbInstance, "instance", "", "Database instance to restore on (default: the snapshot's)")
+	dbSnapshotRestoreCmd.Flags().StringVar(&dbSnapshotDatabase, "database", "", "Database to restore into (default: the snapshot's)")
+}
+
+func runDbSnapshotSave(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	store, err := snapshotStore(name)
+	if err != nil {
+		return err
+	}
+	if _, err := store.Get(name); err == nil && !dbSnapshotForce {
+		return errors.NewInvalidArgumentsError(fmt.Sprintf("snapshot '%s' already exists", name)).
+			WithSuggestion("Use --force to replace it, or pick another name")
+	}
+
+	dockerClient, server, database, err := prepareDatabaseTransfer(cmd, dbEngine, dbInstance, dbSnapshotDatabase)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	version, err := dockerClient.DatabaseVersion(cmd.Context(), server)
+	if err != nil {
+		return err
+	}
+	snapshot := dbdump.Snapshot{Name: name, Engine: server.Engine, Instance: server.Config.Name, Version: version, Database: database}
+	if projectCfg := loadProjectConfigIfInitialized(); projectCfg != nil {
+		snapshot.Project = projectCfg.Name
+	}
+
+	started := time.Now()
+	snapshot, err = store.Save(snapshot, func(w io.Writer) (int64, error) {
+		sqlBytes, _, err := exportDump(cmd, dockerClient, server, database, w, dbdump.Gzip)
+		return sqlBytes, err
+	})
+	if err != nil {
+		if errors.IsPhpierError(err) {
+			return err
+		}
+		return errors.WrapError(errors.ErrorTypeFileSystemError, fmt.Sprintf("Failed to save snapshot '%s'", name), err)
+	}
+
+	fmt.Println(display.RenderTransferSummary("Saved snapshot "+name+" of", database, snapshot.SQLSize, snapshot.Size,
+		true, time.Since(started), display.TableOptions{ColorOutput: !viper.GetBool("no-color")}))
+	return nil
+}
+
+func runDbSnapshotRestore(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	store, err := snapshotStore(name)
+	if err != nil {
+		return err
+	}
+	snapshot, err := store.Get(name)
+	if err != nil {
+		return snapshotError(name, err)
+	}
+
+	// By default the snapshot goes back to its instance, or another of its engine
+	engine, instance := dbEngine, dbInstance
+	if engine == "" && instance == "" {
+		engine = snapshot.Engine
+		if globalConfig, err := config.LoadGlobalConfig(); err == nil {
+			if _, ok := globalConfig.Services.Databases.Get(snapshot.Instance); ok {
+				instance = snapshot.Instance
+			}
+		}
+	}
+	database := dbSnapshotDatabase
+	if database == "" {
+		database = snapshot.Database
+	}
+	dockerClient, server, database, err := prepareDatabaseTransfer(cmd, engine, instance, database)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	version, err := dockerClient.DatabaseVersion(cmd.Context(), server)
+	if err != nil {
+		return err
+	}
+	if err := snapshot.CheckCompatible(server.Engine, version); err != nil {
+		return errors.NewPhpierError(errors.ErrorTypeInvalidArguments, fmt.Sprintf("Cannot restore snapshot '%s'", name)).
+			WithContext("reason", err.Error()).
+			WithSuggestion(fmt.Sprintf("Restore it on a %s %s server, or export and import the data by other means", snapshot.Engine, dbdump.MajorVersion(snapshot.Engine, snapshot.Version)))
+	}
+
+	file, err := store.Open(name)
+	if err != nil {
+		return snapshotError(name, err)
+	}
+	defer file.Close()
+
+	started := time.Now()
+	sqlBytes, streamBytes, _, err := importDump(cmd, dockerClient, server, database, file, snapshot.Size, true)
+	if err != nil {
+		return err
+	}
+	fmt.Println(display.RenderTransferSummary("Restored snapshot "+name+" to", database, sqlBytes, streamBytes,
+		true, time.Since(started), display.TableOptions{ColorOutput: !viper.GetBool("no-color")}))
+	return nil
+}
+
+func runDbSnapshotList(cmd *cobra.Command, args []string) error {
+	store, err := snapshotStore("")
+	if err != nil {
+		return err
+	}
+	snapshots, err := store.List()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to list the snapshots", err)
+	}
+	if len(snapshots) == 0 {
+		fmt.Println("No database snapshots found. Save one with 'phpier db snapshot save <name>'.")
+		return nil
+	}
+	fmt.Println(display.RenderSnapshots(snapshots, display.TableOptions{ColorOutput: !viper.GetBool("no-color")}))
+	return nil
+}
+
+func runDbSnapshotDelete(cmd *cobra.Command, args []string) error {
+	for _, name := range args {
+		store, err := snapshotStore(name)
+		if err != nil {
+			return err
+		}
+		if err := store.Delete(name); err != nil {
+			return snapshotError(name, err)
+		}
+		fmt.Printf("✗ Deleted snapshot '%s'.\n", name)
+	}
+	return nil
+}
+
+// snapshotStore returns the store of the database snapshots, checking the snapshot name
+// when one is given
+func snapshotStore(name string) (dbdump.SnapshotStore, error) {
+	if name != "" {
+		if err := dbdump.ValidateSnapshotName(name); err != nil {
+			return dbdump.SnapshotStore{}, errors.NewInvalidArgumentsError(err.Error())
+		}
+	}
+	dir, err := config.SnapshotsDir()
+	if err != nil {
+		return dbdump.SnapshotStore{}, errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to locate the snapshots", err)
+	}
+	return dbdump.SnapshotStore{Dir: dir}, nil
+}
+
+// snapshotError turns an error of the snapshot store into one that says what to do
+func snapshotError(name string, err error) error {
+	if stderrors.Is(err, dbdump.ErrSnapshotNotFound) {
+		return errors.NewPhpierError(errors.ErrorTypeFileNotFound, fmt.Sprintf("Snapshot '%s' not found", name)).
+			WithSuggestion("Run 'phpier db snapshot list' to see the snapshots")
+	}
+	return errors.WrapError(errors.ErrorTypeFileSystemError, fmt.Sprintf("Failed to read snapshot '%s'", name), err)
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"phpier/internal/config"
+	"phpier/internal/docker"
+	"phpier/internal/errors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const supervisorConfig = "/etc/supervisor/conf.d/supervisord.conf"
+
+var devFollow bool
+
+// devCmd represents the dev command
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Start the frontend dev server (Vite, webpack) in the app container",
+	Long: `Start the frontend dev server under supervisor in the app container.
+
+Enable it in the x-phpier block of .phpier.yml and rebuild with 'phpier build --regenerate':
+
+  x-phpier:
+    devserver:
+      enabled: true
+      port: 5173                          # port the dev server listens on
+      subdomain: vite                     # served on vite.<project>.<domain>
+      command: npm run dev -- --host 0.0.0.0
+      hmr_protocol: wss                   # or ws for pages only loaded over http
+
+The dev server runs as the phpier user, which owns the project files. Traefik routes
+vite.<project>.<domain> to it, including the HMR websocket, and allows the project's
+origins through CORS. VITE_HMR_HOST, VITE_HMR_PROTOCOL and VITE_HMR_CLIENT_PORT are
+set in the container for the Vite config.
+
+Examples:
+  phpier dev            # Start the dev server
+  phpier dev -f         # Start it and follow its output
+  phpier dev status     # Show whether it's running
+  phpier dev stop       # Stop it`,
+	RunE: runDevStart,
+}
+
+// devStopCmd represents the dev stop command
+var devStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the frontend dev server",
+	RunE:  runDevStop,
+}
+
+// devStatusCmd represents the dev status command
+var devStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the frontend dev server status",
+	RunE:  runDevStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(devCmd)
+	devCmd.AddCommand(devStopCmd)
+	devCmd.AddCommand(devStatusCmd)
+
+	devCmd.Flags().BoolVarP(&devFollow, "follow", "f", false, "Follow the dev server output after starting it")
+}
+
+func runDevStart(cmd *cobra.Command, args []string) error {
+	projectCfg, globalCfg, dockerClient, containerID, err := devServerContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	if devServerState(dockerClient, containerID) != "RUNNING" {
+		if _, err := supervisorctl(dockerClient, containerID, "start", "devserver"); err != nil {
+			return errors.WrapError(errors.ErrorTypeCommandFailed, "Failed to start the dev server", err).
+				WithSuggestion("Run 'phpier build --regenerate' and 'phpier up' after enabling devserver in .phpier.yml").
+				WithSuggestion("Check .phpier/logs/supervisor/devserver-error.log")
+		}
+	}
+
+	logrus.Infof("✅ Dev server running on http://%s", projectCfg.DevServerDomain(globalCfg.Traefik.Domain))
+
+	if !devFollow {
+		logrus.Infof("📝 Output: phpier dev -f, or .phpier/logs/supervisor/devserver.log")
+		return nil
+	}
+
+	_, err = dockerClient.ExecInteractive(cmd.Context(), &docker.ExecConfig{
+		Container:    containerID,
+		Command:      []string{"tail", "-n", "50", "-f", "/var/log/supervisor/devserver.log", "/var/log/supervisor/devserver-error.log"},
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if cmd.Context().Err() == context.Canceled {
+		// Ctrl-C is how following the output ends
+		return nil
+	}
+	return err
+}
+
+func runDevStop(cmd *cobra.Command, args []string) error {
+	_, _, dockerClient, containerID, err := devServerContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	if devServerState(dockerClient, containerID) == "RUNNING" {
+		if _, err := supervisorctl(dockerClient, containerID, "stop", "devserver"); err != nil {
+			return errors.WrapError(errors.ErrorTypeCommandFailed, "Failed to stop the dev server", err)
+		}
+	}
+
+	logrus.Infof("✅ Dev server stopped")
+	return nil
+}
+
+func runDevStatus(cmd *cobra.Command, args []string) error {
+	projectCfg, globalCfg, dockerClient, containerID, err := devServerContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	fmt.Printf("State:   %s\n", devServerState(dockerClient, containerID))
+	fmt.Printf("URL:     http://%s\n", projectCfg.DevServerDomain(globalCfg.Traefik.Domain))
+	fmt.Printf("Port:    %d (in the app container)\n", projectCfg.DevServerPort())
+	fmt.Printf("Command: %s\n", projectCfg.DevServerCommand())
+	return nil
+}
+
+// devServerContext loads the configs and finds the running app container of a project with a dev server
+func devServerContext(ctx context.Context) (*config.ProjectConfig, *config.GlobalConfig, *docker.Client, string, error) {
+	if !isProjectInitialized() {
+		return nil, nil, nil, "", errors.NewProjectNotInitializedError()
+	}
+
+	projectCfg, err := config.LoadProjectConfig()
+	if err != nil {
+		return nil, nil, nil, "", errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load project config", err)
+	}
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return nil, nil, nil, "", errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load global config", err)
+	}
+
+	if !projectCfg.DevServer.Enabled {
+		return nil, nil, nil, "", errors.NewInvalidArgumentsError("the dev server is not enabled for this project").
+			WithSuggestion("Set devserver.enabled: true in the x-phpier block of .phpier.yml, then run 'phpier build --regenerate' and 'phpier up'")
+	}
+
+	dockerClient, err := docker.NewClient(ctx, commandTimeout)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+
+	containerID, err := dockerClient.GetContainerID(projectCfg.Name, "app")
+	if err != nil {
+		dockerClient.Close()
+		return nil, nil, nil, "", fmt.Errorf("app container is not running for project '%s'\n\nTry running 'phpier up' to start the services", projectCfg.Name)
+	}
+
+	return projectCfg, globalCfg, dockerClient, containerID, nil
+}
+
+// devServerState returns the supervisor state of the dev server program (RUNNING, STOPPED, ...)
+func devServerState(dockerClient *docker.Client, containerID string) string {
+	// supervisorctl status exits non-zero for stopped programs; only the output matters here
+	output, err := dockerClient.ExecInContainerOutput(containerID, []string{"sh", "-c", "supervisorctl -c " + supervisorConfig + " status devserver || true"})
+	if err != nil {
+		return "UNKNOWN"
+	}
+	return parseSupervisorState(output)
+}
+
+// parseSupervisorState extracts the state from a supervisorctl status line
+func parseSupervisorState(output string) string {
+	fields := strings.Fields(output)
+	if len(fields) < 2 || strings.Contains(output, "no such process") {
+		return "UNKNOWN"
+	}
+	return fields[1]
+}
+
+// supervisorctl runs a supervisorctl command in the app container
+func supervisorctl(dockerClient *docker.Client, containerID string, args ...string) (string, error) {
+	command := append([]string{"supervisorctl", "-c", supervisorConfig}, args...)
+	return dockerClient.ExecInContainerOutput(containerID, command)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDevCommand(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.AddCommand(devCmd)
+
+	foundCmd, _, err := cmd.Find([]string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", foundCmd.Use)
+
+	for _, sub := range []string{"stop", "status"} {
+		foundCmd, _, err := cmd.Find([]string{"dev", sub})
+		assert.NoError(t, err)
+		assert.Equal(t, sub, foundCmd.Use)
+	}
+
+	flag := devCmd.Flags().Lookup("follow")
+	assert.NotNil(t, flag)
+	assert.Equal(t, "f", flag.Shorthand)
+}
+
+func TestParseSupervisorState(t *testing.T) {
+	assert.Equal(t, "RUNNING", parseSupervisorState("devserver                        RUNNING   pid 42, uptime 0:01:02\n"))
+	assert.Equal(t, "STOPPED", parseSupervisorState("devserver                        STOPPED   Not started\n"))
+	assert.Equal(t, "UNKNOWN", parseSupervisorState("devserver: ERROR (no such process)\n"))
+	assert.Equal(t, "UNKNOWN", parseSupervisorState(""))
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"syscall"
+	"time"
+
+	"phpier/internal/config"
+	"phpier/internal/dns"
+	"phpier/internal/errors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dnsPort     int
+	dnsIP       string
+	dnsUpstream string
+	dnsDomain   string
+	dnsListen   string
+)
+
+// dnsCmd represents the dns command
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Resolve project domains for custom TLDs",
+	Long: `Resolve *.<domain> for custom development domains such as .test.
+
+Only .localhost resolves to 127.0.0.1 automatically. For any other traefik.domain,
+phpier provides a small DNS responder that answers <domain> and *.<domain> with the
+loopback (or LAN) address and forwards every other name to an upstream resolver.
+
+Run it either:
+- as a host process:           phpier dns serve
+- as a global service container: set dns.enabled: true in ~/.phpier/config.yaml
+  and run 'phpier global up'
+
+Then point your system resolver at it for the domain only; 'phpier dns status'
+prints the exact steps for systemd-resolved, dnsmasq and macOS.
+
+Examples:
+  phpier dns serve                   # Serve on 127.0.0.1:<dns.port>
+  phpier dns serve --ip lan          # Answer with the LAN address (other devices)
+  phpier dns serve --port 5355       # Use another port
+  phpier dns status                  # Check the responder and system resolver`,
+}
+
+// dnsServeCmd represents the dns serve command
+var dnsServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the DNS responder in the foreground",
+	Long: `Run the DNS responder as a host process until interrupted.
+
+Queries for <domain> and *.<domain> are answered with dns.ip (127.0.0.1 by default,
+or "lan" for this machine's LAN address). Everything else is forwarded to
+dns.upstream, or to the first nameserver in /etc/resolv.conf.`,
+	RunE: runDNSServe,
+}
+
+// dnsStatusCmd represents the dns status command
+var dnsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check the DNS responder and system resolver configuration",
+	RunE:  runDNSStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(dnsCmd)
+	dnsCmd.AddCommand(dnsServeCmd)
+	dnsCmd.AddCommand(dnsStatusCmd)
+
+	dnsServeCmd.Flags().IntVar(&dnsPort, "port", 0, "Port to listen on (default: dns.port)")
+	dnsServeCmd.Flags().StringVar(&dnsIP, "ip", "", "Address to answer with: an IP or \"lan\" (default: dns.ip)")
+	dnsServeCmd.Flags().StringVar(&dnsUpstream, "upstream", "", "Upstream resolver host:port (default: dns.upstream or system resolver)")
+	dnsServeCmd.Flags().StringVar(&dnsDomain, "domain", "", "Domain to answer for (default: traefik.domain)")
+	dnsServeCmd.Flags().StringVar(&dnsListen, "listen", "", "Address to listen on (default: 127.0.0.1, or 0.0.0.0 with --ip lan)")
+}
+
+func runDNSServe(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load global config", err)
+	}
+
+	dnsCfg := globalCfg.DNS
+	if cmd.Flags().Changed("port") {
+		dnsCfg.Port = dnsPort
+	}
+	if cmd.Flags().Changed("ip") {
+		dnsCfg.IP = dnsIP
+	}
+	if cmd.Flags().Changed("upstream") {
+		dnsCfg.Upstream = dnsUpstream
+	}
+	domain := globalCfg.Traefik.Domain
+	if dnsDomain != "" {
+		domain = dnsDomain
+	}
+
+	ip, err := dns.AnswerIP(dnsCfg.IP)
+	if err != nil {
+		return errors.NewInvalidArgumentsError(err.Error())
+	}
+
+	upstream := dnsCfg.Upstream
+	if upstream == "" {
+		upstream = dns.SystemUpstream()
+	}
+
+	server := dns.NewServer(domain, ip, upstream)
+	host := "127.0.0.1"
+	if dnsCfg.IP == "lan" {
+		// Other devices on the network need to reach the responder too
+		host = "0.0.0.0"
+	}
+	if dnsListen != "" {
+		host = dnsListen
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(dnsCfg.Port))
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		server.Close()
+	}()
+
+	logrus.Infof("🌐 Answering *.%s with %s on %s (forwarding other names to %s)", domain, ip, addr, upstream)
+	logrus.Infof("📝 Press Ctrl+C to stop")
+
+	if err := server.ListenAndServe(addr); err != nil {
+		return errors.WrapError(errors.ErrorTypePortConflict, "DNS responder failed", err).
+			WithSuggestion(fmt.Sprintf("Check whether port %d is already in use, or pass --port", dnsCfg.Port))
+	}
+
+	logrus.Infof("✅ DNS responder stopped")
+	return nil
+}
+
+func runDNSStatus(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load global config", err)
+	}
+
+	domain := globalCfg.Traefik.Domain
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(globalCfg.DNS.Port))
+	probe := "phpier-check." + domain
+
+	mode := "host process (phpier dns serve)"
+	if globalCfg.DNS.Enabled {
+		mode = "global service container (phpier-dns)"
+	}
+
+	fmt.Printf("Domain:    *.%s\n", domain)
+	fmt.Printf("Answer:    %s\n", globalCfg.DNS.IP)
+	fmt.Printf("Listen:    %s\n", addr)
+	fmt.Printf("Mode:      %s\n\n", mode)
+
+	if domain == "localhost" {
+		fmt.Println("✓ *.localhost resolves to the loopback address without any DNS setup.")
+		return nil
+	}
+
+	if ips, err := dns.Query(addr, probe, 2*time.Second); err == nil && len(ips) > 0 {
+		fmt.Printf("✓ Responder          answering on %s (%s → %s)\n", addr, probe, ips[0])
+	} else {
+		fmt.Printf("✗ Responder          not answering on %s\n", addr)
+		fmt.Println("  Start it with 'phpier dns serve' or set dns.enabled: true and run 'phpier global up'.")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if ips, err := net.DefaultResolver.LookupHost(ctx, probe); err == nil && len(ips) > 0 {
+		fmt.Printf("✓ System resolver    resolves *.%s (%s → %s)\n", domain, probe, ips[0])
+		return nil
+	}
+
+	fmt.Printf("✗ System resolver    does not resolve *.%s yet\n\n", domain)
+	fmt.Print(dnsResolverInstructions(domain, globalCfg.DNS.Port))
+	return nil
+}
+
+// dnsResolverInstructions explains how to forward queries for domain to the responder on port
+func dnsResolverInstructions(domain string, port int) string {
+	systemd := fmt.Sprintf(`systemd-resolved (most Linux desktops):
+  sudo mkdir -p /etc/systemd/resolved.conf.d
+  printf '[Resolve]\nDNS=127.0.0.1:%d\nDomains=~%s\n' | sudo tee /etc/systemd/resolved.conf.d/phpier.conf
+  sudo systemctl restart systemd-resolved
+
+`, port, domain)
+
+	dnsmasq := fmt.Sprintf(`dnsmasq (or NetworkManager with dns=dnsmasq):
+  echo 'server=/%s/127.0.0.1#%d' | sudo tee /etc/dnsmasq.d/phpier.conf
+  sudo systemctl restart dnsmasq      # or: sudo systemctl reload NetworkManager
+
+`, domain, port)
+
+	macos := fmt.Sprintf(`macOS:
+  sudo mkdir -p /etc/resolver
+  printf 'nameserver 127.0.0.1\nport %d\n' | sudo tee /etc/resolver/%s
+
+`, port, domain)
+
+	switch runtime.GOOS {
+	case "darwin":
+		return "Forward *." + domain + " to the responder:\n\n" + macos
+	case "linux":
+		return "Forward *." + domain + " to the responder using one of:\n\n" + systemd + dnsmasq
+	default:
+		return "Forward *." + domain + " to the responder using one of:\n\n" + systemd + dnsmasq + macos
+	}
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSCommand(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.AddCommand(dnsCmd)
+
+	for _, sub := range []string{"serve", "status"} {
+		foundCmd, _, err := cmd.Find([]string{"dns", sub})
+		assert.NoError(t, err)
+		assert.Equal(t, sub, foundCmd.Use)
+	}
+
+	for _, flagName := range []string{"port", "ip", "upstream"} {
+		assert.NotNil(t, dnsServeCmd.Flags().Lookup(flagName), "Flag %s should exist", flagName)
+	}
+}
+
+func TestDNSResolverInstructions(t *testing.T) {
+	instructions := dnsResolverInstructions("test", 5354)
+	assert.Contains(t, instructions, "5354")
+	assert.Contains(t, instructions, "test")
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"os"
 	"phpier/internal/config"
 	"phpier/internal/docker"
@@ -96,13 +97,13 @@ func runDown(cmd *cobra.Command, args []string) error {
 	var composeManager *docker.ProjectComposeManager
 	if len(args) > 0 {
 		// Use specific project path
-		composeManager, err = docker.NewProjectComposeManagerWithPath(projectCfg, nil, projectPath)
+		composeManager, err = docker.NewProjectComposeManagerWithPath(cmd.Context(), projectCfg, nil, projectPath, commandTimeout)
 		if err != nil {
 			return errors.WrapError(errors.ErrorTypeDockerError, "Failed to create Docker client", err)
 		}
 	} else {
 		// Use current directory
-		composeManager, err = docker.NewProjectComposeManager(projectCfg, nil)
+		composeManager, err = docker.NewProjectComposeManager(cmd.Context(), projectCfg, nil, commandTimeout)
 		if err != nil {
 			return errors.WrapError(errors.ErrorTypeDockerError, "Failed to create Docker client", err)
 		}
@@ -123,7 +124,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 
 	// Handle global services if requested (support both --global and --stop-global flags)
 	if globalFlag || stopGlobal {
-		if err := handleGlobalServicesDown(); err != nil {
+		if err := handleGlobalServicesDown(cmd.Context()); err != nil {
 			return err
 		}
 	}
@@ -131,7 +132,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func handleGlobalServicesDown() error {
+func handleGlobalServicesDown(ctx context.Context) error {
 	// Load global config
 	globalCfg, err := config.LoadGlobalConfig()
 	if err != nil {
@@ -139,7 +140,7 @@ func handleGlobalServicesDown() error {
 	}
 
 	// Create Docker client to check for running projects
-	client, err := docker.NewClient()
+	client, err := docker.NewClient(ctx, commandTimeout)
 	if err != nil {
 		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to create Docker client", err)
 	}
@@ -158,7 +159,7 @@ func handleGlobalServicesDown() error {
 	}
 
 	// Create global compose manager
-	globalComposeManager, err := docker.NewGlobalComposeManager(globalCfg)
+	globalComposeManager, err := docker.NewGlobalComposeManager(ctx, globalCfg, commandTimeout)
 	if err != nil {
 		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to create Docker client for global services", err)
 	}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"phpier/internal/display"
+	"phpier/internal/docker"
+	"phpier/internal/errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	eventsProject string
+	eventsGlobal  bool
+	eventsSince   time.Duration
+	eventsJSON    bool
+)
+
+// eventsCmd represents the events command
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Stream lifecycle events of phpier containers",
+	Long: `Stream container events of all phpier projects and the global services as they happen.
+
+Each event shows the project, service and domain of the container: starts and stops,
+crashes with their exit code, healthcheck changes and OOM kills. Press Ctrl-C to stop.
+
+Examples:
+  phpier events                      # Watch every project and the global services
+  phpier events --project shop       # Only the shop project
+  phpier events --global             # Only global services (Traefik, databases, ...)
+  phpier events --since 1h           # Replay the last hour first
+  phpier events --json | jq .        # One JSON object per line`,
+	Args: cobra.NoArgs,
+	RunE: runEvents,
+}
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+
+	eventsCmd.Flags().StringVarP(&eventsProject, "project", "p", "", "Only show events of this project")
+	eventsCmd.Flags().BoolVar(&eventsGlobal, "global", false, "Only show events of global services")
+	eventsCmd.Flags().DurationVar(&eventsSince, "since", 0, "Replay events from this long ago before streaming, e.g. 30m")
+	eventsCmd.Flags().BoolVar(&eventsJSON, "json", false, "Output events as JSON lines")
+}
+
+func runEvents(cmd *cobra.Command, args []string) error {
+	if eventsGlobal && eventsProject != "" {
+		return errors.NewInvalidArgumentsError("--project and --global can't be combined")
+	}
+
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	options := docker.EventsOptions{Project: eventsProject}
+	if eventsGlobal {
+		options.Project = "phpier"
+	}
+	if eventsSince > 0 {
+		options.Since = time.Now().Add(-eventsSince)
+	}
+
+	events, errs := dockerClient.WatchEvents(cmd.Context(), options)
+
+	displayOptions := display.TableOptions{ColorOutput: !viper.GetBool("no-color")}
+	encoder := json.NewEncoder(os.Stdout)
+	if !eventsJSON {
+		fmt.Println(display.RenderEventsHeader(displayOptions))
+	}
+
+	for event := range events {
+		if eventsJSON {
+			if err := encoder.Encode(event); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Println(display.RenderEvent(event, displayOptions))
+	}
+
+	select {
+	case err := <-errs:
+		if cmd.Context().Err() == nil {
+			return errors.WrapError(errors.ErrorTypeDockerError, "Event stream ended", err).
+				WithSuggestion("Check that the container runtime is still running")
+		}
+	default:
+	}
+	return nil
+}
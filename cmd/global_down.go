@@ -33,7 +33,7 @@ func runGlobalDown(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create Docker Compose manager for the global stack
-	composeManager, err := docker.NewGlobalComposeManager(globalCfg)
+	composeManager, err := docker.NewGlobalComposeManager(cmd.Context(), globalCfg, commandTimeout)
 	if err != nil {
 		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to create Docker client for global stack", err)
 	}
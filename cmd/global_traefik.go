@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"phpier/internal/config"
+	"phpier/internal/docker"
+	"phpier/internal/errors"
+	"phpier/internal/generator"
+	"phpier/internal/templates"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var traefikReloadRestart bool
+
+// globalTraefikCmd represents the 'global traefik' command
+var globalTraefikCmd = &cobra.Command{
+	Use:   "traefik",
+	Short: "Manage the global Traefik proxy",
+}
+
+// globalTraefikReloadCmd represents the 'global traefik reload' command
+var globalTraefikReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Apply Traefik configuration changes",
+	Long: `Re-render Traefik's configuration from ~/.phpier/config.yaml.
+
+Dynamic settings (dashboard routers and auth, middlewares, certificates) are written to
+~/.phpier/traefik/dynamic and picked up by the running Traefik without a restart.
+
+Static settings (version, log level, access log, metrics, entrypoints and published
+ports) only apply when Traefik restarts. When they changed, phpier says so; pass
+--restart to recreate the traefik container right away.
+
+Examples:
+  phpier global traefik reload            # Apply dynamic changes
+  phpier global traefik reload --restart  # Also apply static changes`,
+	RunE: runGlobalTraefikReload,
+}
+
+func init() {
+	globalCmd.AddCommand(globalTraefikCmd)
+	globalTraefikCmd.AddCommand(globalTraefikReloadCmd)
+
+	globalTraefikReloadCmd.Flags().BoolVar(&traefikReloadRestart, "restart", false, "Recreate the traefik container when static settings changed")
+}
+
+func runGlobalTraefikReload(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load global config", err)
+	}
+
+	engine := templates.NewEngine()
+	staticChanged, err := globalStaticFilesChanged(engine, globalCfg)
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeTemplateError, "Failed to render Traefik configuration", err)
+	}
+
+	if !staticChanged || !traefikReloadRestart {
+		if err := generator.GenerateTraefikDynamicFiles(engine, globalCfg); err != nil {
+			return errors.WrapError(errors.ErrorTypeTemplateError, "Failed to generate Traefik dynamic configuration", err)
+		}
+		logrus.Infof("✅ Traefik dynamic configuration reloaded")
+		if staticChanged {
+			logrus.Warnf("⚠️  Static Traefik settings changed; run 'phpier global traefik reload --restart' to apply them")
+		}
+		return nil
+	}
+
+	if err := generator.GenerateGlobalFiles(engine, globalCfg); err != nil {
+		return errors.WrapError(errors.ErrorTypeTemplateError, "Failed to generate global files", err)
+	}
+
+	composeManager, err := docker.NewGlobalComposeManager(cmd.Context(), globalCfg, commandTimeout)
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to create Docker client for global stack", err)
+	}
+
+	logrus.Infof("🔄 Recreating Traefik...")
+	if err := composeManager.Recreate("traefik"); err != nil {
+		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to recreate Traefik", err)
+	}
+
+	logrus.Infof("✅ Traefik restarted with the new configuration")
+	return nil
+}
+
+// globalStaticFilesChanged reports whether the rendered docker-compose.yml or traefik.yml
+// differs from the files the global stack was started with
+func globalStaticFilesChanged(engine *templates.Engine, globalCfg *config.GlobalConfig) (bool, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, err
+	}
+
+	files, err := generator.GlobalStaticFiles(engine, globalCfg)
+	if err != nil {
+		return false, err
+	}
+
+	for name, rendered := range files {
+		current, err := os.ReadFile(filepath.Join(home, ".phpier", name))
+		if err != nil || string(current) != rendered {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobalTraefikReloadCommand(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.AddCommand(globalCmd)
+
+	foundCmd, _, err := cmd.Find([]string{"global", "traefik", "reload"})
+	assert.NoError(t, err)
+	assert.Equal(t, "reload", foundCmd.Use)
+
+	flag := globalTraefikReloadCmd.Flags().Lookup("restart")
+	assert.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+}
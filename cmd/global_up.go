@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"phpier/internal/config"
 	"phpier/internal/docker"
 	"phpier/internal/errors"
 	"phpier/internal/generator"
+	"phpier/internal/ports"
 	"phpier/internal/templates"
 
 	"github.com/sirupsen/logrus"
@@ -17,12 +24,30 @@ var globalUpCmd = &cobra.Command{
 	Short: "Start the global shared services stack",
 	Long: `Starts the persistent, global stack of services including Traefik, databases,
 and other tools. These services will run in the background and be available
-to all phpier projects.`,
+to all phpier projects.
+
+Every host port the stack publishes is checked first. When a port is taken, phpier
+names the process or container holding it and suggests a free port; with --auto-ports
+the free ports are written to ~/.phpier/config.yaml instead.
+
+With --wait phpier waits until every service reports healthy, showing each service's
+progress. If a service turns unhealthy, exits or isn't healthy within --timeout, its last
+log lines are shown.`,
 	RunE: runGlobalUp,
 }
 
+var (
+	globalUpAutoPorts bool
+	globalUpWait      bool
+	globalUpTimeout   time.Duration
+)
+
 func init() {
 	globalCmd.AddCommand(globalUpCmd)
+
+	globalUpCmd.Flags().BoolVar(&globalUpAutoPorts, "auto-ports", false, "Move services to free ports when their configured ports are taken")
+	globalUpCmd.Flags().BoolVar(&globalUpWait, "wait", false, "Wait until services are healthy")
+	globalUpCmd.Flags().DurationVar(&globalUpTimeout, "timeout", defaultWaitTimeout, "How long --wait waits for the services")
 }
 
 func runGlobalUp(cmd *cobra.Command, args []string) error {
@@ -34,6 +59,10 @@ func runGlobalUp(cmd *cobra.Command, args []string) error {
 		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load global config", err)
 	}
 
+	if err := checkGlobalPorts(cmd.Context(), globalCfg, globalUpAutoPorts); err != nil {
+		return err
+	}
+
 	// Create directories
 	if err := generator.CreateGlobalDirectories(); err != nil {
 		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to create global directories", err)
@@ -48,7 +77,7 @@ func runGlobalUp(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create Docker Compose manager for the global stack
-	composeManager, err := docker.NewGlobalComposeManager(globalCfg)
+	composeManager, err := docker.NewGlobalComposeManager(cmd.Context(), globalCfg, commandTimeout)
 	if err != nil {
 		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to create Docker client for global stack", err)
 	}
@@ -58,6 +87,109 @@ func runGlobalUp(cmd *cobra.Command, args []string) error {
 		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to start global services", err)
 	}
 
+	if globalUpWait {
+		if err := waitForServices(cmd.Context(), "phpier", "global", globalUpTimeout); err != nil {
+			return err
+		}
+	}
+
 	logrus.Infof("✅ Global services started successfully!")
 	return nil
 }
+
+// portConflict is a binding whose host port is held by something outside the global stack
+type portConflict struct {
+	binding ports.Binding
+	holder  string
+	free    int
+}
+
+// checkGlobalPorts makes sure every host port the global stack publishes can be bound.
+// With autoPorts, taken ports are replaced by free ones and the config is saved.
+func checkGlobalPorts(ctx context.Context, globalCfg *config.GlobalConfig, autoPorts bool) error {
+	bindings := ports.GlobalBindings(globalCfg)
+
+	if duplicates := ports.Duplicates(bindings); len(duplicates) > 0 {
+		// Report the lowest port, so the error is the same on every run
+		var duplicated []int
+		for port := range duplicates {
+			duplicated = append(duplicated, port)
+		}
+		sort.Ints(duplicated)
+		return errors.NewPortConflictError(duplicated[0], duplicates[duplicated[0]]).
+			WithSuggestion("Give each service its own port in ~/.phpier/config.yaml")
+	}
+
+	// Docker is only used to tell containers apart from host processes
+	dockerClient, err := docker.NewClient(ctx, commandTimeout)
+	if err == nil {
+		defer dockerClient.Close()
+	} else {
+		dockerClient = nil
+	}
+
+	taken := make(map[int]bool)
+	for _, b := range bindings {
+		taken[b.Port] = true
+	}
+
+	var conflicts []portConflict
+	checked := make(map[string]bool)
+	for _, b := range bindings {
+		if checked[b.Key] || ports.Available(b) {
+			continue
+		}
+
+		holder := ""
+		if dockerClient != nil {
+			name, project, err := dockerClient.ContainerPublishingPort(b.Port)
+			if err == nil && project == "phpier" {
+				// Already published by the running global stack; compose recreates it as needed
+				continue
+			}
+			if name != "" {
+				holder = fmt.Sprintf("container %s", name)
+			}
+		}
+		if holder == "" {
+			holder = ports.ProcessHolding(b.Port, b.Protocol)
+		}
+		if holder == "" {
+			holder = "another process"
+		}
+
+		free, err := ports.FindFree(b, taken)
+		if err != nil {
+			return errors.WrapError(errors.ErrorTypePortConflict, fmt.Sprintf("Port %d for %s is taken", b.Port, b.Service), err)
+		}
+		taken[free] = true
+		checked[b.Key] = true
+		conflicts = append(conflicts, portConflict{binding: b, holder: holder, free: free})
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	if autoPorts {
+		for _, c := range conflicts {
+			c.binding.Assign(globalCfg, c.free)
+			logrus.Infof("🔀 %s: port %d is used by %s, using %d (%s)", c.binding.Service, c.binding.Port, c.holder, c.free, c.binding.Key)
+		}
+		if err := config.SaveGlobalConfig(globalCfg); err != nil {
+			return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to save global config", err)
+		}
+		return nil
+	}
+
+	var suggestions []string
+	for _, c := range conflicts {
+		logrus.Errorf("❌ Port %d (%s) for %s is used by %s", c.binding.Port, c.binding.Key, c.binding.Service, c.holder)
+		suggestions = append(suggestions, fmt.Sprintf("%s: %d", c.binding.Key, c.free))
+	}
+
+	first := conflicts[0]
+	return errors.NewPortConflictError(first.binding.Port, []string{first.binding.Service, first.holder}).
+		WithSuggestion("Free ports: " + strings.Join(suggestions, ", ")).
+		WithSuggestion("Run 'phpier global up --auto-ports' to write free ports to ~/.phpier/config.yaml")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"phpier/internal/config"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalUpCommand(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.AddCommand(globalCmd)
+
+	foundCmd, _, err := cmd.Find([]string{"global", "up"})
+	assert.NoError(t, err)
+	assert.Equal(t, "up", foundCmd.Use)
+
+	flag := globalUpCmd.Flags().Lookup("auto-ports")
+	assert.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+
+	flag = globalUpCmd.Flags().Lookup("timeout")
+	require.NotNil(t, flag)
+	assert.Equal(t, "2m0s", flag.DefValue)
+	assert.NotNil(t, globalUpCmd.Flags().Lookup("wait"))
+}
+
+// freePort returns a port nothing listens on right now
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "0.0.0.0:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestCheckGlobalPorts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PATH", "") // keep docker and lsof out of the picture
+
+	globalCfg, err := config.LoadGlobalConfig()
+	require.NoError(t, err)
+	globalCfg.Traefik.Port = freePort(t)
+	globalCfg.Traefik.SSLPort = freePort(t)
+	globalCfg.Traefik.DashboardPort = freePort(t)
+	globalCfg.Services.Databases = nil
+	globalCfg.Services.Cache.Redis.Enabled = false
+	globalCfg.Services.Tools.Mailpit.SMTPPort = freePort(t)
+
+	held, err := net.Listen("tcp", "0.0.0.0:0")
+	require.NoError(t, err)
+	defer held.Close()
+	heldPort := held.Addr().(*net.TCPAddr).Port
+	globalCfg.Services.Tools.Mailpit.UIPort = heldPort
+
+	assert.Error(t, checkGlobalPorts(context.Background(), globalCfg, false))
+	assert.Equal(t, heldPort, globalCfg.Services.Tools.Mailpit.UIPort, "the config is unchanged without --auto-ports")
+
+	require.NoError(t, checkGlobalPorts(context.Background(), globalCfg, true))
+	assert.NotEqual(t, heldPort, globalCfg.Services.Tools.Mailpit.UIPort)
+
+	saved, err := config.LoadGlobalConfig()
+	require.NoError(t, err)
+	assert.Equal(t, globalCfg.Services.Tools.Mailpit.UIPort, saved.Services.Tools.Mailpit.UIPort, "free ports are written to the config")
+}
+
+func TestCheckGlobalPorts_DuplicateConfig(t *testing.T) {
+	globalCfg := &config.GlobalConfig{}
+	globalCfg.Traefik = config.TraefikConfig{Port: 80, SSLPort: 443, Dashboard: true, DashboardPort: 8080}
+	globalCfg.Services.Databases = config.DatabasesConfig{{Name: "mysql", Engine: "mysql", Enabled: true, Port: 8080}}
+
+	assert.Error(t, checkGlobalPorts(context.Background(), globalCfg, true))
+}
@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"phpier/internal/config"
+	"phpier/internal/errors"
+	"phpier/internal/generator"
+	"phpier/internal/hosts"
+	"phpier/internal/routes"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	hostsFile   string
+	hostsIP     string
+	hostsDryRun bool
+	hostsFrom   string
+)
+
+// hostsCmd represents the hosts command
+var hostsCmd = &cobra.Command{
+	Use:   "hosts",
+	Short: "Manage project domains in the hosts file",
+	Long: `Manage a phpier block in the hosts file so project domains resolve without a DNS server.
+
+The block is clearly delimited and lists every discovered project's domains plus the
+global tool hosts (phpier-traefik, phpier-mailpit, phpier-adminer). Entries outside
+the block are never touched. Every write keeps a backup next to the hosts file
+(<hosts-file>.phpier.bak) and replaces the file atomically. Administrator access is
+requested through sudo only when the hosts file isn't writable.
+
+Examples:
+  phpier hosts sync                      # Add or update the phpier block
+  phpier hosts sync --dry-run            # Show the block without writing
+  phpier hosts status                    # Check whether the block is up to date
+  phpier hosts clean                     # Remove the phpier block
+  phpier hosts sync --hosts-file ./hosts # Work on another file`,
+}
+
+// hostsSyncCmd represents the hosts sync command
+var hostsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Write project and global tool domains to the hosts file",
+	RunE:  runHostsSync,
+}
+
+// hostsCleanCmd represents the hosts clean command
+var hostsCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove the phpier block from the hosts file",
+	RunE:  runHostsClean,
+}
+
+// hostsStatusCmd represents the hosts status command
+var hostsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the hosts file is in sync with discovered projects",
+	RunE:  runHostsStatus,
+}
+
+// hostsApplyCmd writes prepared content to the hosts file; it is what runs under sudo
+var hostsApplyCmd = &cobra.Command{
+	Use:    "apply",
+	Short:  "Write prepared content to the hosts file (used internally for elevation)",
+	Hidden: true,
+	RunE:   runHostsApply,
+}
+
+func init() {
+	rootCmd.AddCommand(hostsCmd)
+	hostsCmd.AddCommand(hostsSyncCmd)
+	hostsCmd.AddCommand(hostsCleanCmd)
+	hostsCmd.AddCommand(hostsStatusCmd)
+	hostsCmd.AddCommand(hostsApplyCmd)
+
+	hostsCmd.PersistentFlags().StringVar(&hostsFile, "hosts-file", hosts.DefaultPath(), "Hosts file to manage")
+
+	hostsSyncCmd.Flags().StringVar(&hostsIP, "ip", "127.0.0.1", "Address the domains point to")
+	hostsSyncCmd.Flags().BoolVar(&hostsDryRun, "dry-run", false, "Print the phpier block without writing it")
+	hostsCleanCmd.Flags().BoolVar(&hostsDryRun, "dry-run", false, "Show what would be removed without writing")
+	hostsApplyCmd.Flags().StringVar(&hostsFrom, "from", "", "File holding the new hosts file content")
+}
+
+func runHostsSync(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load global config", err)
+	}
+
+	current, err := readHostsFile()
+	if err != nil {
+		return err
+	}
+
+	block := hosts.RenderBlock(expectedHostsEntries(globalCfg, hostsIP))
+	if hostsDryRun {
+		fmt.Print(block)
+		return nil
+	}
+
+	updated := hosts.ReplaceBlock(current, block)
+	if updated == current {
+		logrus.Infof("✅ %s is already in sync", hostsFile)
+		return nil
+	}
+
+	if err := writeHostsFile(updated); err != nil {
+		return err
+	}
+
+	logrus.Infof("✅ Updated %s (%d host names, backup in %s)", hostsFile, len(hosts.ManagedHosts(updated)), hostsFile+hosts.BackupSuffix)
+	return nil
+}
+
+func runHostsClean(cmd *cobra.Command, args []string) error {
+	current, err := readHostsFile()
+	if err != nil {
+		return err
+	}
+
+	if !hosts.HasBlock(current) {
+		logrus.Infof("✅ No phpier block in %s", hostsFile)
+		return nil
+	}
+
+	if hostsDryRun {
+		for _, host := range hosts.ManagedHosts(current) {
+			fmt.Printf("Would remove %s\n", host)
+		}
+		return nil
+	}
+
+	if err := writeHostsFile(hosts.RemoveBlock(current)); err != nil {
+		return err
+	}
+
+	logrus.Infof("✅ Removed the phpier block from %s", hostsFile)
+	return nil
+}
+
+func runHostsStatus(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load global config", err)
+	}
+
+	current, err := readHostsFile()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Hosts file: %s\n", hostsFile)
+	if !hosts.HasBlock(current) {
+		fmt.Println("Status:     no phpier block")
+		fmt.Println("\nRun 'phpier hosts sync' to add project domains.")
+		return nil
+	}
+
+	expected := hosts.ManagedHosts(hosts.RenderBlock(expectedHostsEntries(globalCfg, hostsIP)))
+	managed := hosts.ManagedHosts(current)
+	missing, stale := diffHostNames(expected, managed)
+
+	if len(missing) == 0 && len(stale) == 0 {
+		fmt.Printf("Status:     in sync (%d host names)\n", len(managed))
+		return nil
+	}
+
+	fmt.Println("Status:     out of sync")
+	for _, host := range missing {
+		fmt.Printf("  + %s\n", host)
+	}
+	for _, host := range stale {
+		fmt.Printf("  - %s\n", host)
+	}
+	fmt.Println("\nRun 'phpier hosts sync' to update the hosts file.")
+	return nil
+}
+
+func runHostsApply(cmd *cobra.Command, args []string) error {
+	if hostsFrom == "" {
+		return errors.NewInvalidArgumentsError("--from is required")
+	}
+
+	content, err := os.ReadFile(hostsFrom)
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeFileNotFound, "Failed to read prepared hosts content", err)
+	}
+
+	if err := hosts.WriteAtomic(hostsFile, string(content)); err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to write hosts file", err)
+	}
+	return nil
+}
+
+// expectedHostsEntries returns the global tool hosts and every discovered project's domains
+func expectedHostsEntries(globalCfg *config.GlobalConfig, ip string) []hosts.Entry {
+	domain := globalCfg.Traefik.Domain
+	entries := []hosts.Entry{{
+		IP:      ip,
+		Comment: "phpier global services",
+		Hosts:   globalCfg.ToolHosts(),
+	}}
+
+	for _, project := range config.DiscoverAllProjects() {
+		projectCfg := config.CreateProjectConfig(project.Name, "", "")
+		if project.Path != "" {
+			if loaded, err := config.LoadProjectConfigFromPath(project.Path); err == nil {
+				projectCfg = loaded
+			}
+		}
+
+		names := projectCfg.Domains(domain)
+		if projectCfg.DevServer.Enabled {
+			names = append(names, projectCfg.DevServerDomain(domain))
+		}
+		entries = append(entries, hosts.Entry{
+			IP:      ip,
+			Comment: project.Name,
+			Hosts:   names,
+		})
+	}
+
+	// Routes to upstreams outside phpier ('phpier route add')
+	if dir, err := generator.TraefikDynamicDir(); err == nil {
+		if list, err := routes.List(dir); err == nil {
+			for _, route := range list {
+				entries = append(entries, hosts.Entry{IP: ip, Comment: "route " + route.Name, Hosts: route.Hosts})
+			}
+		}
+	}
+
+	return entries
+}
+
+// diffHostNames returns host names expected but not managed, and managed but no longer expected
+func diffHostNames(expected, managed []string) ([]string, []string) {
+	inManaged := make(map[string]bool)
+	for _, host := range managed {
+		inManaged[host] = true
+	}
+	inExpected := make(map[string]bool)
+	for _, host := range expected {
+		inExpected[host] = true
+	}
+
+	var missing, stale []string
+	for _, host := range expected {
+		if !inManaged[host] {
+			missing = append(missing, host)
+		}
+	}
+	for _, host := range managed {
+		if !inExpected[host] {
+			stale = append(stale, host)
+		}
+	}
+	return missing, stale
+}
+
+func readHostsFile() (string, error) {
+	content, err := os.ReadFile(hostsFile)
+	if err != nil {
+		return "", errors.WrapError(errors.ErrorTypeFileNotFound, "Failed to read hosts file", err)
+	}
+	return string(content), nil
+}
+
+// writeHostsFile writes content to the hosts file, re-running itself through sudo only when needed
+func writeHostsFile(content string) error {
+	if hosts.Writable(hostsFile) {
+		if err := hosts.WriteAtomic(hostsFile, content); err != nil {
+			return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to write hosts file", err)
+		}
+		return nil
+	}
+
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		return errors.NewFilePermissionError(hostsFile, "write").
+			WithSuggestion("Run the command from an elevated (administrator) shell")
+	}
+
+	prepared, err := os.CreateTemp("", "phpier-hosts-*")
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to prepare hosts content", err)
+	}
+	defer os.Remove(prepared.Name())
+	if _, err := prepared.WriteString(content); err != nil {
+		prepared.Close()
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to prepare hosts content", err)
+	}
+	prepared.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to locate phpier executable", err)
+	}
+
+	logrus.Infof("🔐 Administrator access is needed to update %s", hostsFile)
+	sudo := exec.Command("sudo", executable, "hosts", "apply", "--hosts-file", hostsFile, "--from", prepared.Name())
+	sudo.Stdin = os.Stdin
+	sudo.Stdout = os.Stdout
+	sudo.Stderr = os.Stderr
+	if err := sudo.Run(); err != nil {
+		return errors.WrapError(errors.ErrorTypeFilePermission, "Failed to update hosts file with sudo", err)
+	}
+	return nil
+}
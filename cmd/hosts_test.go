@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostsCommand(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.AddCommand(hostsCmd)
+
+	for _, sub := range []string{"sync", "clean", "status"} {
+		foundCmd, _, err := cmd.Find([]string{"hosts", sub})
+		assert.NoError(t, err)
+		assert.Equal(t, sub, foundCmd.Use)
+	}
+
+	assert.NotNil(t, hostsCmd.PersistentFlags().Lookup("hosts-file"))
+	assert.NotNil(t, hostsSyncCmd.Flags().Lookup("dry-run"))
+	assert.True(t, hostsApplyCmd.Hidden, "apply is only used for sudo elevation")
+}
+
+func TestDiffHostNames(t *testing.T) {
+	missing, stale := diffHostNames(
+		[]string{"blog.test", "shop.test"},
+		[]string{"old.test", "shop.test"},
+	)
+	assert.Equal(t, []string{"blog.test"}, missing)
+	assert.Equal(t, []string{"old.test"}, stale)
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"phpier/internal/config"
+	"phpier/internal/docker"
+	"phpier/internal/errors"
+	"phpier/internal/generator"
+	"phpier/internal/templates"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	imagesNoCache  bool
+	imagesRebuild  bool
+	imagesPruneAll bool
+	imagesDryRun   bool
+)
+
+// imagesCmd represents the images command
+var imagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "Manage shared phpier base images",
+	Long: `Manage the shared phpier-base images that project images are built on.
+
+Each base image contains the system packages, PHP extensions, Composer and Node.js
+for one PHP version. Its tag is phpier-base:<php>-<hash>, where the hash covers the
+rendered base Dockerfile, so projects with the same setup reuse the same base image
+instead of compiling extensions again.
+
+Examples:
+  phpier images build-base            # Build the base image for the current project
+  phpier images build-base --no-cache # Rebuild the base image from scratch
+  phpier images list                  # List base images and the projects using them
+  phpier images prune                 # Remove base images no project uses
+  phpier images prune --all           # Remove all base images`,
+}
+
+// imagesBuildBaseCmd represents the images build-base command
+var imagesBuildBaseCmd = &cobra.Command{
+	Use:   "build-base",
+	Short: "Build the shared base image for the current project",
+	Long: `Prepare and build the shared base image for the current project's PHP version,
+extensions and tools. An existing base image with the same hash is reused unless
+--rebuild or --no-cache is given.`,
+	RunE: runImagesBuildBase,
+}
+
+// imagesListCmd represents the images list command
+var imagesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List shared base images",
+	RunE:  runImagesList,
+}
+
+// imagesPruneCmd represents the images prune command
+var imagesPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove shared base images that no project uses",
+	Long: `Remove shared base images that are not referenced by any discovered project's
+.phpier/Dockerfile.php, together with their build contexts in ~/.phpier/images.`,
+	RunE: runImagesPrune,
+}
+
+func init() {
+	rootCmd.AddCommand(imagesCmd)
+	imagesCmd.AddCommand(imagesBuildBaseCmd)
+	imagesCmd.AddCommand(imagesListCmd)
+	imagesCmd.AddCommand(imagesPruneCmd)
+
+	imagesBuildBaseCmd.Flags().BoolVar(&imagesNoCache, "no-cache", false, "Do not use cache when building the base image")
+	imagesBuildBaseCmd.Flags().BoolVar(&imagesRebuild, "rebuild", false, "Rebuild the base image even if it already exists")
+
+	imagesPruneCmd.Flags().BoolVar(&imagesPruneAll, "all", false, "Remove all base images, including ones in use")
+	imagesPruneCmd.Flags().BoolVar(&imagesDryRun, "dry-run", false, "Show what would be removed without removing")
+}
+
+func runImagesBuildBase(cmd *cobra.Command, args []string) error {
+	if !isProjectInitialized() {
+		return errors.NewProjectNotInitializedError()
+	}
+
+	projectCfg, err := config.LoadProjectConfig()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load project config", err)
+	}
+
+	hooks, err := generator.LoadDockerfileHooks(".phpier/Dockerfile.d")
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to load Dockerfile snippets", err)
+	}
+
+	base, err := generator.PrepareBaseImage(templates.NewEngine(), projectCfg, hooks)
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeTemplateError, "Failed to prepare base image", err)
+	}
+
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	if dockerClient.ImageExists(base.Tag) && !imagesRebuild && !imagesNoCache {
+		logrus.Infof("✅ Base image %s is up to date", base.Tag)
+	} else {
+		if err := dockerClient.BuildBaseImage(base.Tag, base.ContextDir, docker.ProjectBuildArgs(projectCfg), imagesNoCache); err != nil {
+			return errors.NewBuildFailedError(base.Tag, err)
+		}
+		logrus.Infof("✅ Base image %s built successfully!", base.Tag)
+	}
+
+	if current, err := docker.BaseImageFromDockerfile(".phpier/Dockerfile.php"); err == nil && current != base.Tag {
+		logrus.Infof("📝 The project Dockerfile does not use this base yet. Run 'phpier build --regenerate' to switch to it.")
+	}
+
+	return nil
+}
+
+func runImagesList(cmd *cobra.Command, args []string) error {
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	images, err := dockerClient.ListBaseImages()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to list base images", err)
+	}
+
+	if len(images) == 0 {
+		fmt.Println("No shared base images found.")
+		fmt.Println("Run 'phpier build' or 'phpier images build-base' in a project to create one.")
+		return nil
+	}
+
+	usage := baseImageUsage()
+
+	fmt.Printf("%-32s %-6s %-10s %-16s %s\n", "IMAGE", "PHP", "SIZE", "CREATED", "USED BY")
+	for _, image := range images {
+		usedBy := "-"
+		if projects := usage[image.Reference()]; len(projects) > 0 {
+			usedBy = strings.Join(projects, ", ")
+		}
+		fmt.Printf("%-32s %-6s %-10s %-16s %s\n", image.Reference(), image.PHP, image.Size, image.Created, usedBy)
+	}
+
+	return nil
+}
+
+func runImagesPrune(cmd *cobra.Command, args []string) error {
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	images, err := dockerClient.ListBaseImages()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to list base images", err)
+	}
+
+	usage := baseImageUsage()
+	removed := 0
+
+	for _, image := range images {
+		ref := image.Reference()
+		if projects := usage[ref]; len(projects) > 0 && !imagesPruneAll {
+			logrus.Debugf("Keeping %s (used by %s)", ref, strings.Join(projects, ", "))
+			continue
+		}
+
+		if imagesDryRun {
+			fmt.Printf("Would remove %s (%s)\n", ref, image.Size)
+			removed++
+			continue
+		}
+
+		if err := dockerClient.RemoveImage(ref); err != nil {
+			logrus.Warnf("Failed to remove %s: %v", ref, err)
+			continue
+		}
+		if contextDir, err := docker.BaseImageContextDir(ref); err == nil {
+			os.RemoveAll(contextDir)
+		}
+		fmt.Printf("✓ Removed %s (%s)\n", ref, image.Size)
+		removed++
+	}
+
+	if removed == 0 {
+		fmt.Println("No unused base images to remove.")
+	}
+
+	return nil
+}
+
+// baseImageUsage maps base image references to the names of discovered projects building on them
+func baseImageUsage() map[string][]string {
+	usage := make(map[string][]string)
+
+	for _, project := range config.DiscoverAllProjects() {
+		if project.Path == "" {
+			continue
+		}
+
+		ref, err := docker.BaseImageFromDockerfile(filepath.Join(project.Path, ".phpier", "Dockerfile.php"))
+		if err != nil || ref == "" {
+			continue
+		}
+		usage[ref] = append(usage[ref], project.Name)
+	}
+
+	for ref := range usage {
+		sort.Strings(usage[ref])
+	}
+
+	return usage
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImagesCommand(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.AddCommand(imagesCmd)
+
+	for _, sub := range []string{"build-base", "list", "prune"} {
+		foundCmd, _, err := cmd.Find([]string{"images", sub})
+		assert.NoError(t, err)
+		assert.Equal(t, sub, foundCmd.Use)
+	}
+}
+
+func TestImagesCommandFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      *cobra.Command
+		flagName string
+	}{
+		{"build-base no-cache flag", imagesBuildBaseCmd, "no-cache"},
+		{"build-base rebuild flag", imagesBuildBaseCmd, "rebuild"},
+		{"prune all flag", imagesPruneCmd, "all"},
+		{"prune dry-run flag", imagesPruneCmd, "dry-run"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := tt.cmd.Flags().Lookup(tt.flagName)
+			assert.NotNil(t, flag, "Flag %s should exist", tt.flagName)
+			assert.Equal(t, "bool", flag.Value.Type())
+		})
+	}
+}
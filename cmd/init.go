@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"phpier/internal/config"
+	"phpier/internal/docker"
 	"phpier/internal/errors"
 	"phpier/internal/generator"
 	"phpier/internal/templates"
@@ -12,8 +13,11 @@ import (
 )
 
 var (
-	phpVersion  string
-	projectName string
+	phpVersion     string
+	projectName    string
+	initDBEngine   string
+	initDBInstance string
+	initNoDB       bool
 )
 
 // initCmd represents the init command
@@ -26,10 +30,14 @@ This command will:
 - Create a .phpier.yml file for project-specific settings (PHP version).
 - Generate a Dockerfile for the PHP container.
 - Generate a docker-compose.yml to run the app container and connect it to the global services network.
+- Create a database and user named after the project on a shared database server, and
+  pass their credentials to the app as DB_* variables (skip it with --no-db).
 
 Example:
   phpier init 8.3
-  phpier init 7.4 --project-name=my-legacy-app`,
+  phpier init 7.4 --project-name=my-legacy-app
+  phpier init 8.3 --db-engine postgresql
+  phpier init 7.4 --db-instance legacy`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInit,
 }
@@ -40,6 +48,9 @@ func init() {
 	// Flags
 	initCmd.Flags().StringVarP(&phpVersion, "php-version", "p", "8.3", "PHP version to use")
 	initCmd.Flags().StringVar(&projectName, "project-name", "", "Project name (defaults to current directory name)")
+	initCmd.Flags().StringVar(&initDBEngine, "db-engine", "", "Database server of the project's database: mysql, postgresql or mariadb (default: the first enabled)")
+	initCmd.Flags().StringVar(&initDBInstance, "db-instance", "", "Database instance of the project's database, e.g. legacy (default: the first enabled of the engine)")
+	initCmd.Flags().BoolVar(&initNoDB, "no-db", false, "Don't create a database for the project")
 
 	// Bind flags to viper
 	viper.BindPFlag("php.version", initCmd.Flags().Lookup("php-version"))
@@ -75,6 +86,19 @@ func runInit(cmd *cobra.Command, args []string) error {
 	// Create project configuration from CLI arguments
 	projectCfg := config.CreateProjectConfig(projectName, phpVersion, "")
 
+	var dbInstance config.DatabaseServiceConfig
+	if initNoDB || initDBEngine == "" && initDBInstance == "" && len(globalCfg.GetEnabledDatabases()) == 0 {
+		logrus.Infof("Database: none")
+	} else {
+		if dbInstance, err = selectDatabaseInstance(globalCfg, nil, initDBEngine, initDBInstance); err != nil {
+			return err
+		}
+		if projectCfg.Database, err = config.NewProjectDatabase(projectCfg.Name, dbInstance); err != nil {
+			return err
+		}
+		logrus.Infof("Database: %s on %s", projectCfg.Database.Name, dbInstance.Label())
+	}
+
 	// Create template engine
 	engine := templates.NewEngine()
 
@@ -89,12 +113,12 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 
 	// Generate .phpier.yml in project root (Docker Compose file)
-	dockerCompose, err := engine.RenderProjectDockerCompose(projectCfg, globalCfg)
-	if err != nil {
-		return errors.WrapError(errors.ErrorTypeTemplateError, "Failed to render .phpier.yml", err)
+	if err := generator.GenerateProjectCompose(engine, projectCfg, globalCfg); err != nil {
+		return errors.WrapError(errors.ErrorTypeTemplateError, "Failed to generate .phpier.yml", err)
 	}
-	if err := generator.WriteFile(".phpier.yml", dockerCompose); err != nil {
-		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to write .phpier.yml", err)
+
+	if projectCfg.Database.Enabled() {
+		createInitDatabase(cmd, dbInstance, projectCfg.Database)
 	}
 
 	logrus.Infof("✅ phpier project initialized successfully!")
@@ -106,6 +130,22 @@ func runInit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// createInitDatabase creates the project's database on its server. The server may not be running
+// yet on a first init, so failing only leaves a note on how to create it later.
+func createInitDatabase(cmd *cobra.Command, instance config.DatabaseServiceConfig, db config.ProjectDatabase) {
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
+	if err == nil {
+		defer dockerClient.Close()
+		err = dockerClient.CreateDatabase(cmd.Context(), databaseServer(instance), db)
+	}
+	if err != nil {
+		logrus.Warnf("⚠️  Database '%s' was not created yet: %v", db.Name, err)
+		logrus.Warnf("   Run 'phpier db create' once 'phpier global up' has started %s", instance.Label())
+		return
+	}
+	logrus.Infof("🗄️  Created database '%s' and user '%s' on %s ('phpier db credentials' shows them)", db.Name, db.Username, instance.Label())
+}
+
 // getDefaultExtensions returns appropriate extensions for each PHP version
 // This is used by the template generator for Dockerfile creation
 func getDefaultExtensions(phpVersion string) []string {
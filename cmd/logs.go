@@ -1,31 +1,52 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
 	"phpier/internal/config"
+	"phpier/internal/display"
 	"phpier/internal/docker"
 	"phpier/internal/errors"
+	"phpier/internal/logfiles"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var (
-	follow bool
-	tail   int
-	since  string
+	follow         bool
+	tail           int
+	since          string
+	logsAll        bool
+	logsWithGlobal bool
+	logsGrep       string
+	logsLevel      string
+	logsJSON       bool
+	logsFile       string
 )
 
 // logsCmd represents the logs command
 var logsCmd = &cobra.Command{
-	Use:   "logs [service]",
+	Use:   "logs [service...]",
 	Short: "View logs from project containers",
 	Long: `View logs from Docker containers for the current project's services.
 
 This command will:
 - Display logs from all project containers when no service is specified
-- Show logs from a specific service when service name is provided
+- Show logs from specific services when service names are provided
+- Merge the logs of every phpier project (--all) and the global services (--with-global)
+- Prefix each line with its project/service and the time it was written
 - Support real-time log following with --follow flag
 - Allow limiting output with --tail and --since flags
+- Filter lines by pattern (--grep) and severity (--level)
+- Show the nginx, PHP and supervisor log files under .phpier/logs (--file), with
+  PHP errors grouped together with their stack traces
 
 Available services depend on your project configuration but typically include:
 - app (PHP/Nginx container)
@@ -39,7 +60,14 @@ Examples:
   phpier logs database           # Show logs from database container
   phpier logs -f                 # Follow/tail logs in real-time
   phpier logs --tail 100         # Show last 100 lines
-  phpier logs --since "2023-01-01T00:00:00Z"  # Show logs since timestamp`,
+  phpier logs --since "2023-01-01T00:00:00Z"  # Show logs since timestamp
+  phpier logs -f --with-global   # Follow the project together with Traefik, databases, ...
+  phpier logs --all --level error --since 1h  # Errors of every project in the last hour
+  phpier logs --grep "SQLSTATE"  # Only lines matching a regular expression
+  phpier logs --json | jq .      # One JSON object per line
+  phpier logs --file nginx-error -f  # Follow the nginx error log
+  phpier logs --file php-errors --tail 20  # Last PHP errors with their stack traces
+  phpier logs errors --since 1h  # Distinct PHP errors of the last hour with counts`,
 	RunE: runLogs,
 }
 
@@ -49,41 +77,220 @@ func init() {
 	// Flags
 	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow log output in real-time")
 	logsCmd.Flags().IntVar(&tail, "tail", 0, "Number of lines to show from the end of the logs")
-	logsCmd.Flags().StringVar(&since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37Z)")
+	logsCmd.Flags().StringVar(&since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37Z) or relative time (e.g. 30m)")
+	logsCmd.Flags().BoolVarP(&logsAll, "all", "a", false, "Show logs of every phpier project")
+	logsCmd.Flags().BoolVar(&logsWithGlobal, "with-global", false, "Include logs of the global services")
+	logsCmd.Flags().StringVar(&logsGrep, "grep", "", "Only show lines matching this regular expression")
+	logsCmd.Flags().StringVar(&logsLevel, "level", "", "Only show lines of this level or worse (debug, info, notice, warning, error, critical)")
+	logsCmd.Flags().BoolVar(&logsJSON, "json", false, "Output log lines as JSON")
+	logsCmd.Flags().StringVar(&logsFile, "file", "", "Show a log file instead of container output ("+strings.Join(logfiles.Names(), ", ")+")")
 }
 
 func runLogs(cmd *cobra.Command, args []string) error {
-	if !isProjectInitialized() {
-		return errors.NewProjectNotInitializedError()
+	filter, err := logsFilter()
+	if err != nil {
+		return err
+	}
+	if logsFile != "" {
+		return runLogFile(cmd, args, filter)
 	}
 
-	// Load project configuration
-	projectCfg, err := config.LoadProjectConfig()
-	if err != nil {
-		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load project config", err)
+	options := docker.LogsOptions{
+		AllProjects: logsAll,
+		Services:    args,
+		Follow:      follow,
+		Tail:        tail,
+		Since:       since,
+		Filter:      filter,
 	}
 
-	// Create Docker Compose manager
-	composeManager, err := docker.NewProjectComposeManager(projectCfg, nil)
+	// Without --all the logs are those of the current project
+	if !logsAll {
+		if !isProjectInitialized() {
+			return errors.NewProjectNotInitializedError()
+		}
+
+		projectCfg, err := config.LoadProjectConfig()
+		if err != nil {
+			return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load project config", err)
+		}
+		options.Projects = append(options.Projects, projectCfg.Name)
+	}
+	if logsWithGlobal {
+		options.Projects = append(options.Projects, "phpier")
+	}
+
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
 	if err != nil {
 		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to create Docker client", err)
 	}
+	defer dockerClient.Close()
 
-	// Determine service to show logs for
-	var service string
-	if len(args) > 0 {
-		service = args[0]
+	containers, err := dockerClient.LogContainers(cmd.Context(), options)
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to list containers", err)
+	}
+	if len(containers) == 0 {
+		return errors.NewPhpierError(errors.ErrorTypeContainerNotFound, "No containers found to show logs for").
+			WithSuggestion("Start the project with 'phpier up', or check the service names with 'phpier services'")
 	}
 
-	// Show logs
-	logrus.Infof("📝 Showing logs for project '%s'...", projectCfg.Name)
-	if service != "" {
-		logrus.Infof("🔍 Filtering logs for service: %s", service)
+	if !logsJSON {
+		logrus.Infof("📝 Showing logs for %s...", logsSubject(options))
+		if len(args) > 0 {
+			logrus.Infof("🔍 Filtering logs for service: %s", strings.Join(args, ", "))
+		}
 	}
 
-	if err := composeManager.Logs(service, follow, tail, since); err != nil {
-		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to retrieve logs", err)
+	lines, errs := dockerClient.StreamLogs(cmd.Context(), containers, options)
+
+	displayOptions := display.TableOptions{ColorOutput: !viper.GetBool("no-color")}
+	width := display.LogPrefixWidth(containers)
+	encoder := json.NewEncoder(os.Stdout)
+	for line := range lines {
+		if logsJSON {
+			if err := encoder.Encode(line); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Println(display.RenderLogLine(line, width, displayOptions))
+	}
+
+	select {
+	case err := <-errs:
+		if cmd.Context().Err() == nil {
+			return errors.WrapError(errors.ErrorTypeDockerError, "Failed to retrieve logs", err)
+		}
+	default:
 	}
+	return nil
+}
 
+// logsSubject describes the stacks whose logs are shown, e.g. "project 'shop' and global services"
+func logsSubject(options docker.LogsOptions) string {
+	subject := "all projects"
+	if !options.AllProjects {
+		subject = fmt.Sprintf("project '%s'", options.Projects[0])
+	}
+	if logsWithGlobal {
+		subject += " and global services"
+	}
+	return subject
+}
+
+// logsFilter builds the line filter from --grep and --level
+func logsFilter() (docker.LogFilter, error) {
+	var filter docker.LogFilter
+	if logsGrep != "" {
+		pattern, err := regexp.Compile(logsGrep)
+		if err != nil {
+			return filter, errors.NewInvalidArgumentsError(fmt.Sprintf("invalid --grep pattern: %v", err))
+		}
+		filter.Pattern = pattern
+	}
+	if logsLevel != "" {
+		level := strings.ToLower(logsLevel)
+		if level == "warn" {
+			level = "warning"
+		}
+		if !docker.ValidLogLevel(level) {
+			return filter, errors.NewInvalidArgumentsError(fmt.Sprintf("invalid --level %q: use debug, info, notice, warning, error or critical", logsLevel))
+		}
+		filter.MinLevel = level
+	}
+	return filter, nil
+}
+
+// logFileLine is a line of a log file as printed by --json
+type logFileLine struct {
+	Time    *time.Time `json:"time,omitempty"`
+	File    string     `json:"file"`
+	Level   string     `json:"level,omitempty"`
+	Message string     `json:"message"`
+}
+
+// runLogFile shows one of the log files under .phpier/logs, following it on the host with
+// --follow. PHP errors are grouped with their stack traces.
+func runLogFile(cmd *cobra.Command, args []string, filter docker.LogFilter) error {
+	if logsAll || logsWithGlobal || len(args) > 0 {
+		return errors.NewInvalidArgumentsError("--file can't be combined with services, --all or --with-global")
+	}
+	file, ok := logfiles.Lookup(logsFile)
+	if !ok {
+		return errors.NewInvalidArgumentsError(fmt.Sprintf("unknown log file %q: use one of %s", logsFile, strings.Join(logfiles.Names(), ", ")))
+	}
+	if !isProjectInitialized() {
+		return errors.NewProjectNotInitializedError()
+	}
+
+	sinceFilter := &logfiles.SinceFilter{}
+	if since != "" {
+		t, err := logfiles.ParseSince(since, time.Now())
+		if err != nil {
+			return errors.NewInvalidArgumentsError(err.Error())
+		}
+		sinceFilter.Since = t
+	}
+
+	path := file.HostPath(".")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return errors.NewFileNotFoundError(path).
+			WithSuggestion("The app container creates it when it first writes to it; projects created before phpier logged there need 'phpier build --regenerate' and 'phpier up'")
+	}
+
+	displayOptions := display.TableOptions{ColorOutput: !viper.GetBool("no-color")}
+	encoder := json.NewEncoder(os.Stdout)
+
+	var parser logfiles.PHPErrorParser
+	printPHPError := func(entry *logfiles.PHPError) {
+		if entry == nil {
+			return
+		}
+		headline := strings.TrimSpace(entry.Level + ": " + entry.Message + " " + entry.Location())
+		if !filter.Match(docker.LogLine{Message: headline, Level: entry.Severity()}) {
+			return
+		}
+		if logsJSON {
+			encoder.Encode(entry)
+			return
+		}
+		fmt.Println(display.RenderPHPError(*entry, displayOptions))
+	}
+
+	tailOptions := logfiles.TailOptions{Lines: tail, Follow: follow}
+	if file.Name == "php-errors" {
+		// An entry is complete once the next one starts; while following, don't hold back the
+		// last one until then
+		tailOptions.Idle = func() { printPHPError(parser.Flush()) }
+	}
+
+	err := logfiles.Tail(cmd.Context(), path, tailOptions, func(line string) {
+		if !sinceFilter.Match(line) {
+			return
+		}
+		if file.Name == "php-errors" {
+			printPHPError(parser.Add(line))
+			return
+		}
+
+		level := docker.DetectLevel(line)
+		if !filter.Match(docker.LogLine{Message: line, Level: level}) {
+			return
+		}
+		if logsJSON {
+			entry := logFileLine{File: file.Name, Level: level, Message: line}
+			if t, ok := logfiles.LineTime(line); ok {
+				entry.Time = &t
+			}
+			encoder.Encode(entry)
+			return
+		}
+		fmt.Println(display.RenderLogFileLine(line, displayOptions))
+	})
+	printPHPError(parser.Flush())
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, fmt.Sprintf("Failed to read %s", path), err)
+	}
 	return nil
 }
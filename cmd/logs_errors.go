@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"phpier/internal/display"
+	"phpier/internal/errors"
+	"phpier/internal/logfiles"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	logsErrorsSince string
+	logsErrorsJSON  bool
+)
+
+// logsErrorsCmd represents the 'logs errors' command
+var logsErrorsCmd = &cobra.Command{
+	Use:   "errors",
+	Short: "Summarize the PHP errors of the project",
+	Long: `Read the PHP error log (.phpier/logs/php/errors.log) and list each distinct error,
+warning and notice once, with how often it occurred and when it was last seen.
+
+Errors are the same when their level, message and location match; the most frequent
+come first.
+
+Examples:
+  phpier logs errors              # All PHP errors in the log
+  phpier logs errors --since 1h   # Errors of the last hour
+  phpier logs errors --json       # The summary as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runLogsErrors,
+}
+
+func init() {
+	logsCmd.AddCommand(logsErrorsCmd)
+
+	logsErrorsCmd.Flags().StringVar(&logsErrorsSince, "since", "", "Only count errors since a time (e.g. 2024-05-01T10:00:00Z) or this long ago (e.g. 1h)")
+	logsErrorsCmd.Flags().BoolVar(&logsErrorsJSON, "json", false, "Output the summary as JSON")
+}
+
+func runLogsErrors(cmd *cobra.Command, args []string) error {
+	if !isProjectInitialized() {
+		return errors.NewProjectNotInitializedError()
+	}
+
+	sinceFilter := &logfiles.SinceFilter{}
+	if logsErrorsSince != "" {
+		t, err := logfiles.ParseSince(logsErrorsSince, time.Now())
+		if err != nil {
+			return errors.NewInvalidArgumentsError(err.Error())
+		}
+		sinceFilter.Since = t
+	}
+
+	file, _ := logfiles.Lookup("php-errors")
+	path := file.HostPath(".")
+
+	var lines []string
+	err := logfiles.Tail(cmd.Context(), path, logfiles.TailOptions{}, func(line string) {
+		if sinceFilter.Match(line) {
+			lines = append(lines, line)
+		}
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, fmt.Sprintf("Failed to read %s", path), err)
+	}
+
+	summaries := logfiles.SummarizePHPErrors(logfiles.ParsePHPErrors(lines))
+	if logsErrorsJSON {
+		if summaries == nil {
+			summaries = []logfiles.PHPErrorSummary{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(summaries)
+	}
+
+	fmt.Println(display.RenderPHPErrorSummary(summaries, display.TableOptions{ColorOutput: !viper.GetBool("no-color")}))
+	return nil
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"phpier/internal/config"
+	"phpier/internal/display"
+	"phpier/internal/docker"
+	"phpier/internal/errors"
+	"phpier/internal/logfiles"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	logsPruneAll    bool
+	logsPruneDryRun bool
+	logsPruneForce  bool
+)
+
+// logsPruneCmd represents the 'logs prune' command
+var logsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Report and reclaim the disk space used by project logs",
+	Long: `Remove the rotated copies of the project's log files under .phpier/logs, then report
+how much space was reclaimed and how large the runtime's logs of the project's containers are.
+
+The current log files are still being written to by nginx, PHP and supervisor. Truncating
+them loses what they hold, so prune asks first; --force truncates them without asking.
+Log files the host user can't write (created by root in the container) are truncated
+from inside the app container when it is running.
+
+The runtime's container logs (docker logs) are only reported, never pruned. They are capped
+by the 'logging' settings in ~/.phpier/config.yaml; containers created before they were set
+are listed as uncapped until they are recreated.
+
+Examples:
+  phpier logs prune             # Prune the logs of the current project
+  phpier logs prune --force     # Also truncate the current log files without asking
+  phpier logs prune --all       # Prune the logs of every phpier project
+  phpier logs prune --dry-run   # Only report what would be reclaimed`,
+	Args: cobra.NoArgs,
+	RunE: runLogsPrune,
+}
+
+func init() {
+	logsCmd.AddCommand(logsPruneCmd)
+
+	logsPruneCmd.Flags().BoolVarP(&logsPruneAll, "all", "a", false, "Prune the logs of every phpier project")
+	logsPruneCmd.Flags().BoolVar(&logsPruneDryRun, "dry-run", false, "Only report what would be reclaimed")
+	logsPruneCmd.Flags().BoolVarP(&logsPruneForce, "force", "f", false, "Truncate the current log files without asking")
+}
+
+// logsPruneTarget is a project whose logs are pruned
+type logsPruneTarget struct {
+	name string
+	dir  string
+}
+
+func runLogsPrune(cmd *cobra.Command, args []string) error {
+	var targets []logsPruneTarget
+	var dockerClient *docker.Client
+
+	if logsPruneAll {
+		client, err := docker.NewClient(cmd.Context(), commandTimeout)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		dockerClient = client
+
+		projects, err := client.GetAllPhpierProjects()
+		if err != nil {
+			return errors.WrapError(errors.ErrorTypeDockerError, "Failed to list phpier projects", err)
+		}
+		for _, project := range projects {
+			if project.Path == "" {
+				logrus.Debugf("Skipping %s: its directory is unknown", project.Name)
+				continue
+			}
+			targets = append(targets, logsPruneTarget{name: project.Name, dir: project.Path})
+		}
+	} else {
+		if !isProjectInitialized() {
+			return errors.NewProjectNotInitializedError()
+		}
+		projectCfg, err := config.LoadProjectConfig()
+		if err != nil {
+			return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load project config", err)
+		}
+		targets = append(targets, logsPruneTarget{name: projectCfg.Name, dir: "."})
+
+		// The log files can be pruned without the runtime, so only the container logs need it
+		if client, err := docker.NewClient(cmd.Context(), commandTimeout); err != nil {
+			logrus.Debugf("Not reporting container logs: %v", err)
+		} else {
+			defer client.Close()
+			dockerClient = client
+		}
+	}
+
+	// A dry run reports everything --force would reclaim
+	options := logfiles.PruneOptions{DryRun: logsPruneDryRun, Truncate: logsPruneDryRun || logsPruneForce}
+	if len(targets) > 0 && !options.Truncate {
+		fmt.Print("Also truncate the current log files? nginx, PHP and supervisor are still writing to them. [y/N] ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		options.Truncate = answer == "y" || answer == "yes"
+	}
+
+	var usages []display.LogUsage
+	for _, target := range targets {
+		result, err := logfiles.Prune(target.dir, options)
+		if err != nil {
+			return errors.WrapError(errors.ErrorTypeFileSystemError, fmt.Sprintf("Failed to prune the logs of %s", target.name), err)
+		}
+		usage := display.LogUsage{Project: target.name, Files: result}
+
+		if dockerClient != nil {
+			var reclaimed int64
+			usage.Files.Skipped, reclaimed = truncateInContainer(cmd, dockerClient, target, usage.Files.Skipped)
+			usage.Files.Reclaimed += reclaimed
+
+			containers, err := dockerClient.ContainerLogUsage(cmd.Context(), target.name)
+			if err != nil {
+				logrus.Debugf("Failed to get the container logs of %s: %v", target.name, err)
+			}
+			usage.Containers = containers
+		}
+		usages = append(usages, usage)
+	}
+
+	if len(usages) == 0 {
+		fmt.Println("No phpier projects found")
+		return nil
+	}
+	fmt.Println(display.RenderLogUsage(usages, logsPruneDryRun, display.TableOptions{ColorOutput: !viper.GetBool("no-color")}))
+	return nil
+}
+
+// truncateInContainer truncates the log files the host user couldn't from inside the project's
+// app container, as root. It returns those it still couldn't truncate and the bytes reclaimed.
+func truncateInContainer(cmd *cobra.Command, dockerClient *docker.Client, target logsPruneTarget, skipped []string) ([]string, int64) {
+	if len(skipped) == 0 {
+		return nil, 0
+	}
+	container := target.name + "-app"
+	if running, err := dockerClient.IsContainerRunning(cmd.Context(), container); err != nil || !running {
+		return skipped, 0
+	}
+
+	var remaining []string
+	var reclaimed int64
+	for _, rel := range skipped {
+		var size int64
+		if info, err := os.Stat(filepath.Join(target.dir, logfiles.Dir, filepath.FromSlash(rel))); err == nil {
+			size = info.Size()
+		}
+		// Rotated copies are removed; current logs are truncated since they're still open
+		script := `case "$1" in *.log) : > "$1" ;; *) rm -f "$1" ;; esac`
+		if _, err := dockerClient.ExecInContainerOutput(container, []string{"sh", "-c", script, "sh", logfiles.ContainerPath(rel)}); err != nil {
+			logrus.Debugf("Failed to truncate %s in %s: %v", rel, container, err)
+			remaining = append(remaining, rel)
+			continue
+		}
+		reclaimed += size
+	}
+	return remaining, reclaimed
+}
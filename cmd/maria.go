@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
@@ -28,7 +27,8 @@ The command automatically connects using the configured database credentials.
 
 Examples:
   phpier maria                      # Open interactive MariaDB shell
-  phpier maria -e "SHOW TABLES"    # Execute single query`,
+  phpier maria -e "SHOW TABLES"    # Execute single query
+  phpier maria --instance legacy   # Connect to the instance named legacy`,
 	RunE: runMaria,
 }
 
@@ -42,12 +42,13 @@ This is an alias for the 'maria' command.
 
 Examples:
   phpier mariadb                    # Open interactive MariaDB shell
-  phpier mariadb -e "SHOW TABLES"  # Execute single query`,
+  phpier mariadb -e "SHOW TABLES"  # Execute single query
+  phpier mariadb --instance legacy  # Connect to the instance named legacy`,
 	RunE: runMaria,
 }
 
 func runMaria(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx := cmd.Context()
 
 	// Load global configuration to get database settings
 	globalConfig, err := config.LoadGlobalConfig()
@@ -55,45 +56,23 @@ func runMaria(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load global config: %w", err)
 	}
 
-	// Check if MariaDB is enabled
-	if !globalConfig.IsDatabaseEnabled("mariadb") {
-		return fmt.Errorf("MariaDB is not enabled\n\nRun 'phpier global db enable mariadb' to enable MariaDB")
+	mariaConfig, err := shellDatabaseInstance(globalConfig, "mariadb", dbInstance)
+	if err != nil {
+		return err
 	}
 
 	// Create Docker client
-	dockerClient, err := docker.NewClient()
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
 	if err != nil {
 		return err
 	}
 	defer dockerClient.Close()
 
-	logrus.Debugf("Looking for MariaDB container")
-
-	// Get container ID for the MariaDB service
-	containerID, err := dockerClient.GetContainerID("phpier", "mariadb")
-	if err != nil {
-		// Check if it's a container not found error vs other errors
-		if strings.Contains(err.Error(), "Container not found") {
-			return fmt.Errorf("MariaDB container is not running\n\nTry running 'phpier global up' to start the global services")
-		}
-		return fmt.Errorf("failed to find MariaDB container: %w\n\nMake sure Docker is running and try 'phpier global up' to start the services", err)
-	}
-
-	logrus.Debugf("Found MariaDB container ID: %s", containerID)
-
-	// Check if container is running
-	isRunning, err := dockerClient.IsContainerRunningByID(ctx, containerID)
+	containerID, err := findDatabaseContainer(ctx, dockerClient, mariaConfig.Label(), mariaConfig.Name)
 	if err != nil {
-		return fmt.Errorf("failed to check MariaDB container status: %w", err)
-	}
-
-	if !isRunning {
-		return fmt.Errorf("MariaDB container is not running\n\nTry running 'phpier global up' to start the global services")
+		return err
 	}
 
-	// Get MariaDB configuration
-	mariaConfig := globalConfig.Services.Databases.MariaDB
-
 	// Prepare MariaDB command
 	var mariaCommand []string
 	if len(args) > 0 {
@@ -151,4 +130,6 @@ func runMaria(cmd *cobra.Command, args []string) error {
 func init() {
 	rootCmd.AddCommand(mariaCmd)
 	rootCmd.AddCommand(mariadbCmd)
+	mariaCmd.Flags().StringVar(&dbInstance, "instance", "", "Database instance to connect to (default: the project's, or the first MariaDB one)")
+	mariadbCmd.Flags().StringVar(&dbInstance, "instance", "", "Database instance to connect to (default: the project's, or the first MariaDB one)")
 }
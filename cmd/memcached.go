@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"os"
 
 	"phpier/internal/docker"
@@ -26,10 +25,10 @@ var memcachedCmd = &cobra.Command{
 }
 
 func runMemcached(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx := cmd.Context()
 
 	// Create Docker client
-	dockerClient, err := docker.NewClient()
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
 	if err != nil {
 		return err
 	}
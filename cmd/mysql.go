@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
@@ -28,12 +27,13 @@ The command automatically connects using the configured database credentials.
 
 Examples:
   phpier mysql                    # Open interactive MySQL shell
-  phpier mysql -e "SHOW TABLES"  # Execute single query`,
+  phpier mysql -e "SHOW TABLES"  # Execute single query
+  phpier mysql --instance legacy  # Connect to the instance named legacy`,
 	RunE: runMySQL,
 }
 
 func runMySQL(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx := cmd.Context()
 
 	// Load global configuration to get database settings
 	globalConfig, err := config.LoadGlobalConfig()
@@ -41,45 +41,23 @@ func runMySQL(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load global config: %w", err)
 	}
 
-	// Check if MySQL is enabled
-	if !globalConfig.IsDatabaseEnabled("mysql") {
-		return fmt.Errorf("MySQL is not enabled\n\nRun 'phpier global db enable mysql' to enable MySQL")
+	mysqlConfig, err := shellDatabaseInstance(globalConfig, "mysql", dbInstance)
+	if err != nil {
+		return err
 	}
 
 	// Create Docker client
-	dockerClient, err := docker.NewClient()
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
 	if err != nil {
 		return err
 	}
 	defer dockerClient.Close()
 
-	logrus.Debugf("Looking for MySQL container")
-
-	// Get container ID for the MySQL service
-	containerID, err := dockerClient.GetContainerID("phpier", "mysql")
+	containerID, err := findDatabaseContainer(ctx, dockerClient, mysqlConfig.Label(), mysqlConfig.Name)
 	if err != nil {
-		// Check if it's a container not found error vs other errors
-		if strings.Contains(err.Error(), "Container not found") {
-			return fmt.Errorf("MySQL container is not running\n\nTry running 'phpier global up' to start the global services")
-		}
-		return fmt.Errorf("failed to find MySQL container: %w\n\nMake sure Docker is running and try 'phpier global up' to start the services", err)
-	}
-
-	logrus.Debugf("Found MySQL container ID: %s", containerID)
-
-	// Check if container is running
-	isRunning, err := dockerClient.IsContainerRunningByID(ctx, containerID)
-	if err != nil {
-		return fmt.Errorf("failed to check MySQL container status: %w", err)
-	}
-
-	if !isRunning {
-		return fmt.Errorf("MySQL container is not running\n\nTry running 'phpier global up' to start the global services")
+		return err
 	}
 
-	// Get MySQL configuration
-	mysqlConfig := globalConfig.Services.Databases.MySQL
-
 	// Prepare MySQL command
 	var mysqlCommand []string
 	if len(args) > 0 {
@@ -121,4 +99,5 @@ func runMySQL(cmd *cobra.Command, args []string) error {
 
 func init() {
 	rootCmd.AddCommand(mysqlCmd)
+	mysqlCmd.Flags().StringVar(&dbInstance, "instance", "", "Database instance to connect to (default: the project's, or the first MySQL one)")
 }
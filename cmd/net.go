@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"phpier/internal/config"
+	"phpier/internal/docker"
+	"phpier/internal/errors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// netCmd represents the net command
+var netCmd = &cobra.Command{
+	Use:   "net",
+	Short: "Inspect the shared phpier network",
+	Long: `Inspect the network shared by the global services and all projects.
+
+Every project's app container joins the shared network with stable aliases:
+<project>.internal and its public domains. Other projects can call it by those
+names directly, e.g. http://api.internal from the shop project's PHP code.
+Aliases reach the app's nginx on port 80 without passing through Traefik, so
+use plain HTTP for these calls.`,
+}
+
+// netInspectCmd represents the net inspect command
+var netInspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Show which container answers to which name on the shared network",
+	Long: `Show the alias map of the shared network.
+
+Running containers are read from Docker. Discovered projects that aren't running
+are listed with the aliases they will get on 'phpier up'.`,
+	RunE: runNetInspect,
+}
+
+func init() {
+	rootCmd.AddCommand(netCmd)
+	netCmd.AddCommand(netInspectCmd)
+}
+
+func runNetInspect(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load global config", err)
+	}
+
+	var endpoints []docker.NetworkEndpoint
+	if dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout); err != nil {
+		logrus.Debugf("Docker unavailable: %v", err)
+		logrus.Infof("⚠️  Docker is not reachable; showing the aliases configured for discovered projects")
+	} else {
+		defer dockerClient.Close()
+		if endpoints, err = dockerClient.NetworkEndpoints(globalCfg.DockerNetwork()); err != nil {
+			logrus.Debugf("Failed to inspect network %s: %v", globalCfg.DockerNetwork(), err)
+			logrus.Infof("⚠️  Network %s not found; run 'phpier global up' to create it", globalCfg.DockerNetwork())
+			endpoints = nil
+		}
+	}
+
+	endpoints = mergeConfiguredAliases(endpoints, config.DiscoverAllProjects(), globalCfg)
+	if len(endpoints) == 0 {
+		fmt.Println("No containers or projects found.")
+		return nil
+	}
+
+	fmt.Printf("Network: %s\n\n", globalCfg.DockerNetwork())
+	printNetworkEndpoints(endpoints)
+	return nil
+}
+
+// mergeConfiguredAliases adds the aliases of discovered projects that have no running container
+// on the network, and sorts the result by project and container
+func mergeConfiguredAliases(endpoints []docker.NetworkEndpoint, projects []config.ProjectInfo, globalCfg *config.GlobalConfig) []docker.NetworkEndpoint {
+	attached := make(map[string]bool)
+	for _, endpoint := range endpoints {
+		attached[endpoint.Project] = true
+	}
+
+	for _, project := range projects {
+		if attached[project.Name] {
+			continue
+		}
+		projectCfg := config.CreateProjectConfig(project.Name, "", "")
+		if project.Path != "" {
+			if loaded, err := config.LoadProjectConfigFromPath(project.Path); err == nil {
+				projectCfg = loaded
+			}
+		}
+		endpoints = append(endpoints, docker.NetworkEndpoint{
+			Project: projectCfg.Name,
+			Aliases: projectCfg.NetworkAliases(globalCfg.Traefik.Domain),
+		})
+		attached[project.Name] = true
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].Project != endpoints[j].Project {
+			return endpoints[i].Project < endpoints[j].Project
+		}
+		return endpoints[i].Container < endpoints[j].Container
+	})
+	return endpoints
+}
+
+func printNetworkEndpoints(endpoints []docker.NetworkEndpoint) {
+	fmt.Printf("%-20s %-32s %s\n", "PROJECT", "CONTAINER", "ALIASES")
+	for _, endpoint := range endpoints {
+		project, container, aliases := endpoint.Project, endpoint.Container, "-"
+		if project == "" {
+			project = "-"
+		}
+		if container == "" {
+			container = "(not running)"
+		}
+		if len(endpoint.Aliases) > 0 {
+			aliases = strings.Join(endpoint.Aliases, ", ")
+		}
+		fmt.Printf("%-20s %-32s %s\n", project, container, aliases)
+	}
+}
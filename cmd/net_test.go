@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+
+	"phpier/internal/config"
+	"phpier/internal/docker"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetCommand(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.AddCommand(netCmd)
+
+	foundCmd, _, err := cmd.Find([]string{"net", "inspect"})
+	assert.NoError(t, err)
+	assert.Equal(t, "inspect", foundCmd.Use)
+}
+
+func TestMergeConfiguredAliases(t *testing.T) {
+	globalCfg := &config.GlobalConfig{Traefik: config.TraefikConfig{Domain: "test"}}
+	running := []docker.NetworkEndpoint{
+		{Container: "shop-app-1", Project: "shop", Aliases: []string{"shop.internal", "shop.test", "app"}},
+		{Container: "phpier-mysql-1", Project: "phpier", Aliases: []string{"mysql"}},
+	}
+	projects := []config.ProjectInfo{{Name: "shop"}, {Name: "api"}}
+
+	endpoints := mergeConfiguredAliases(running, projects, globalCfg)
+	require.Len(t, endpoints, 3)
+	assert.Equal(t, docker.NetworkEndpoint{Project: "api", Aliases: []string{"api.internal", "api.test"}}, endpoints[0])
+	assert.Equal(t, "phpier", endpoints[1].Project)
+	assert.Equal(t, "shop-app-1", endpoints[2].Container)
+}
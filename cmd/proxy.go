@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 
@@ -34,10 +33,10 @@ Examples:
 }
 
 func runProxy(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx := cmd.Context()
 
 	// Create Docker client
-	dockerClient, err := docker.NewClient()
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
 	if err != nil {
 		return err
 	}
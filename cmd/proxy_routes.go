@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"phpier/internal/config"
+	"phpier/internal/errors"
+	"phpier/internal/generator"
+	"phpier/internal/routes"
+	"phpier/internal/traefik"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var proxyRoutesAll bool
+
+// proxyRoutesCmd represents the proxy-routes command
+var proxyRoutesCmd = &cobra.Command{
+	Use:   "proxy-routes",
+	Short: "Show Traefik routers and their middleware chains",
+	Long: `Show each Traefik router with its rule, entrypoints and middleware chain.
+
+Routers are read from the running Traefik API. When Traefik isn't running, the
+routers and chains phpier generates for discovered projects are shown instead.
+
+Project middlewares are configured in the middlewares section of x-phpier in
+.phpier.yml and applied with 'phpier build --regenerate' and 'phpier up'.
+
+Examples:
+  phpier proxy-routes          # Routers for projects and global services
+  phpier proxy-routes --all    # Include Traefik's internal routers`,
+	RunE: runProxyRoutes,
+}
+
+func init() {
+	rootCmd.AddCommand(proxyRoutesCmd)
+
+	proxyRoutesCmd.Flags().BoolVar(&proxyRoutesAll, "all", false, "Include Traefik's internal routers")
+}
+
+func runProxyRoutes(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load global config", err)
+	}
+
+	routers, err := traefikAPIClient(globalCfg).HTTPRouters(cmd.Context())
+	if err != nil {
+		logrus.Debugf("Traefik API unavailable: %v", err)
+		logrus.Infof("⚠️  Traefik is not reachable; showing the routers phpier generates for discovered projects")
+		routers = expectedProjectRouters(globalCfg)
+	}
+
+	if !proxyRoutesAll {
+		routers = filterInternalRouters(routers)
+	}
+
+	if len(routers) == 0 {
+		fmt.Println("No routers found.")
+		return nil
+	}
+
+	printProxyRoutes(routers)
+	return nil
+}
+
+// traefikAPIClient returns a client for the Traefik API on the dashboard port, using the first
+// dashboard_auth user whose password is stored in plain text
+func traefikAPIClient(globalCfg *config.GlobalConfig) *traefik.Client {
+	client := traefik.NewClient(fmt.Sprintf("http://127.0.0.1:%d", globalCfg.Traefik.DashboardPort))
+	users := make([]string, 0, len(globalCfg.Traefik.DashboardAuth))
+	for user := range globalCfg.Traefik.DashboardAuth {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	for _, user := range users {
+		if password := globalCfg.Traefik.DashboardAuth[user]; !config.IsPasswordHash(password) {
+			client.Username, client.Password = user, password
+			break
+		}
+	}
+	return client
+}
+
+// expectedProjectRouters returns the routers defined by the labels of every discovered project
+func expectedProjectRouters(globalCfg *config.GlobalConfig) []traefik.Router {
+	var routers []traefik.Router
+	for _, project := range config.DiscoverAllProjects() {
+		projectCfg := config.CreateProjectConfig(project.Name, "", "")
+		if project.Path != "" {
+			if loaded, err := config.LoadProjectConfigFromPath(project.Path); err == nil {
+				projectCfg = loaded
+			}
+		}
+		routers = append(routers, projectRouters(projectCfg, globalCfg)...)
+	}
+
+	if dir, err := generator.TraefikDynamicDir(); err == nil {
+		if list, err := routes.List(dir); err == nil {
+			var redirect []string
+			if globalCfg.Traefik.HTTPSRedirect {
+				redirect = []string{"redirect-to-https@file"}
+			}
+			for _, route := range list {
+				routers = append(routers,
+					traefik.Router{Name: route.RouterName() + "@file", Rule: route.HostRule(), EntryPoints: []string{"web"}, Middlewares: redirect, Service: route.RouterName()},
+					traefik.Router{Name: route.RouterName() + "-secure@file", Rule: route.HostRule(), EntryPoints: []string{"websecure"}, Service: route.RouterName()},
+				)
+			}
+		}
+	}
+	return routers
+}
+
+// projectRouters mirrors the router labels rendered into a project's .phpier.yml
+func projectRouters(projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig) []traefik.Router {
+	var chain []string
+	for _, name := range projectCfg.MiddlewareChain() {
+		if !strings.Contains(name, "@") {
+			name += "@docker"
+		}
+		chain = append(chain, name)
+	}
+
+	rule := projectHostRule(projectCfg, globalCfg)
+	web := chain
+	if globalCfg.Traefik.HTTPSRedirect {
+		web = []string{"redirect-to-https@file"}
+	}
+
+	routers := []traefik.Router{
+		{Name: projectCfg.Name + "@docker", Rule: rule, EntryPoints: []string{"web"}, Middlewares: web, Service: projectCfg.Name},
+		{Name: projectCfg.Name + "-secure@docker", Rule: rule, EntryPoints: []string{"websecure"}, Middlewares: chain, Service: projectCfg.Name},
+	}
+
+	if projectCfg.DevServer.Enabled {
+		devRule := fmt.Sprintf("Host(`%s`)", projectCfg.DevServerDomain(globalCfg.Traefik.Domain))
+		cors := []string{projectCfg.Name + "-devserver-cors@docker"}
+		service := projectCfg.Name + "-devserver"
+		routers = append(routers,
+			traefik.Router{Name: service + "@docker", Rule: devRule, EntryPoints: []string{"web"}, Middlewares: cors, Service: service},
+			traefik.Router{Name: service + "-secure@docker", Rule: devRule, EntryPoints: []string{"websecure"}, Middlewares: cors, Service: service},
+		)
+	}
+
+	return routers
+}
+
+func projectHostRule(projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig) string {
+	var rules []string
+	for _, domain := range projectCfg.Domains(globalCfg.Traefik.Domain) {
+		rules = append(rules, fmt.Sprintf("Host(`%s`)", domain))
+	}
+	return strings.Join(rules, " || ")
+}
+
+func filterInternalRouters(routers []traefik.Router) []traefik.Router {
+	var result []traefik.Router
+	for _, router := range routers {
+		if strings.HasSuffix(router.Name, "@internal") {
+			continue
+		}
+		result = append(result, router)
+	}
+	return result
+}
+
+func printProxyRoutes(routers []traefik.Router) {
+	fmt.Printf("%-28s %-40s %-14s %s\n", "ROUTER", "RULE", "ENTRYPOINTS", "MIDDLEWARES")
+	for _, router := range routers {
+		middlewares := "-"
+		if len(router.Middlewares) > 0 {
+			middlewares = strings.Join(router.Middlewares, " → ")
+		}
+		fmt.Printf("%-28s %-40s %-14s %s\n", router.Name, router.Rule, strings.Join(router.EntryPoints, ","), middlewares)
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"testing"
+
+	"phpier/internal/config"
+	"phpier/internal/traefik"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyRoutesCommand(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.AddCommand(proxyRoutesCmd)
+
+	foundCmd, _, err := cmd.Find([]string{"proxy-routes"})
+	assert.NoError(t, err)
+	assert.Equal(t, "proxy-routes", foundCmd.Use)
+
+	flag := proxyRoutesCmd.Flags().Lookup("all")
+	assert.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+}
+
+func TestProjectRouters(t *testing.T) {
+	projectCfg := config.CreateProjectConfig("shop", "8.3", "lts")
+	projectCfg.Middlewares = config.MiddlewaresConfig{
+		BasicAuth: config.BasicAuthUsers{"admin": "secret"},
+		Use:       []string{"cors"},
+	}
+	globalCfg := &config.GlobalConfig{Traefik: config.TraefikConfig{Domain: "test"}}
+
+	routers := projectRouters(projectCfg, globalCfg)
+	require.Len(t, routers, 2)
+	assert.Equal(t, "shop@docker", routers[0].Name)
+	assert.Equal(t, "Host(`shop.test`)", routers[0].Rule)
+	assert.Equal(t, []string{"shop-auth@docker", "cors@file"}, routers[0].Middlewares)
+	assert.Equal(t, []string{"websecure"}, routers[1].EntryPoints)
+
+	globalCfg.Traefik.HTTPSRedirect = true
+	routers = projectRouters(projectCfg, globalCfg)
+	assert.Equal(t, []string{"redirect-to-https@file"}, routers[0].Middlewares, "plain HTTP only redirects")
+	assert.Equal(t, []string{"shop-auth@docker", "cors@file"}, routers[1].Middlewares)
+}
+
+func TestProjectRouters_DevServer(t *testing.T) {
+	projectCfg := config.CreateProjectConfig("shop", "8.3", "lts")
+	projectCfg.DevServer.Enabled = true
+	globalCfg := &config.GlobalConfig{Traefik: config.TraefikConfig{Domain: "test"}}
+
+	routers := projectRouters(projectCfg, globalCfg)
+	require.Len(t, routers, 4)
+	assert.Equal(t, "shop-devserver@docker", routers[2].Name)
+	assert.Equal(t, "Host(`vite.shop.test`)", routers[2].Rule)
+	assert.Equal(t, []string{"shop-devserver-cors@docker"}, routers[3].Middlewares)
+}
+
+func TestFilterInternalRouters(t *testing.T) {
+	routers := filterInternalRouters([]traefik.Router{{Name: "shop@docker"}, {Name: "dashboard@internal"}})
+	assert.Len(t, routers, 1)
+	assert.Equal(t, "shop@docker", routers[0].Name)
+}
@@ -1,9 +1,7 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
-	"strings"
 
 	"phpier/internal/config"
 	"phpier/internal/docker"
@@ -28,7 +26,8 @@ The command automatically connects using the configured database credentials.
 
 Examples:
   phpier psql                           # Open interactive psql shell
-  phpier psql -c "SELECT version();"    # Execute single query`,
+  phpier psql -c "SELECT version();"    # Execute single query
+  phpier psql --instance pg16           # Connect to the instance named pg16`,
 	RunE: runPSQL,
 }
 
@@ -42,12 +41,13 @@ This is an alias for the 'psql' command.
 
 Examples:
   phpier postgres                       # Open interactive psql shell
-  phpier postgres -c "SELECT version();" # Execute single query`,
+  phpier postgres -c "SELECT version();" # Execute single query
+  phpier postgres --instance pg16        # Connect to the instance named pg16`,
 	RunE: runPSQL,
 }
 
 func runPSQL(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx := cmd.Context()
 
 	// Load global configuration to get database settings
 	globalConfig, err := config.LoadGlobalConfig()
@@ -55,45 +55,23 @@ func runPSQL(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load global config: %w", err)
 	}
 
-	// Check if PostgreSQL is enabled
-	if !globalConfig.IsDatabaseEnabled("postgresql") {
-		return fmt.Errorf("PostgreSQL is not enabled\n\nRun 'phpier global db enable postgresql' to enable PostgreSQL")
+	pgConfig, err := shellDatabaseInstance(globalConfig, "postgresql", dbInstance)
+	if err != nil {
+		return err
 	}
 
 	// Create Docker client
-	dockerClient, err := docker.NewClient()
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
 	if err != nil {
 		return err
 	}
 	defer dockerClient.Close()
 
-	logrus.Debugf("Looking for PostgreSQL container")
-
-	// Get container ID for the PostgreSQL service
-	containerID, err := dockerClient.GetContainerID("phpier", "postgres")
+	containerID, err := findDatabaseContainer(ctx, dockerClient, pgConfig.Label(), pgConfig.Name)
 	if err != nil {
-		// Check if it's a container not found error vs other errors
-		if strings.Contains(err.Error(), "Container not found") {
-			return fmt.Errorf("PostgreSQL container is not running\n\nTry running 'phpier global up' to start the global services")
-		}
-		return fmt.Errorf("failed to find PostgreSQL container: %w\n\nMake sure Docker is running and try 'phpier global up' to start the services", err)
-	}
-
-	logrus.Debugf("Found PostgreSQL container ID: %s", containerID)
-
-	// Check if container is running
-	isRunning, err := dockerClient.IsContainerRunningByID(ctx, containerID)
-	if err != nil {
-		return fmt.Errorf("failed to check PostgreSQL container status: %w", err)
-	}
-
-	if !isRunning {
-		return fmt.Errorf("PostgreSQL container is not running\n\nTry running 'phpier global up' to start the global services")
+		return err
 	}
 
-	// Get PostgreSQL configuration
-	pgConfig := globalConfig.Services.Databases.PostgreSQL
-
 	// Prepare PostgreSQL command
 	var psqlCommand []string
 	if len(args) > 0 {
@@ -136,4 +114,6 @@ func runPSQL(cmd *cobra.Command, args []string) error {
 func init() {
 	rootCmd.AddCommand(psqlCmd)
 	rootCmd.AddCommand(postgresCmd)
+	psqlCmd.Flags().StringVar(&dbInstance, "instance", "", "Database instance to connect to (default: the project's, or the first PostgreSQL one)")
+	postgresCmd.Flags().StringVar(&dbInstance, "instance", "", "Database instance to connect to (default: the project's, or the first PostgreSQL one)")
 }
@@ -87,7 +87,7 @@ func runReload(cmd *cobra.Command, args []string) error {
 
 	// Check and start global services if needed (unless --skip-global flag is used)
 	if !reloadSkipGlobal {
-		if err := ensureGlobalServicesRunning(globalCfg); err != nil {
+		if err := ensureGlobalServicesRunning(cmd.Context(), globalCfg); err != nil {
 			return errors.WrapError(errors.ErrorTypeDockerError, "Failed to ensure global services are running", err)
 		}
 	} else {
@@ -95,7 +95,7 @@ func runReload(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create Docker Compose manager
-	composeManager, err := docker.NewProjectComposeManager(projectCfg, globalCfg)
+	composeManager, err := docker.NewProjectComposeManager(cmd.Context(), projectCfg, globalCfg, commandTimeout)
 	if err != nil {
 		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to create Docker client", err)
 	}
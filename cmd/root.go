@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"os"
+	"os/signal"
 	"os/user"
+	"syscall"
+	"time"
 
 	"phpier/internal/errors"
 
@@ -12,8 +16,9 @@ import (
 )
 
 var (
-	cfgFile string
-	verbose bool
+	cfgFile        string
+	verbose        bool
+	commandTimeout time.Duration
 
 	// Version information
 	buildVersion = "v1.0.7"
@@ -42,7 +47,15 @@ Features:
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	// Ctrl-C and SIGTERM cancel the command context, which interrupts running compose processes
+	// and engine requests. A second signal terminates phpier right away.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
 		// Get colored output preference
 		colored := !viper.GetBool("no-color")
@@ -61,6 +74,7 @@ func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.phpier.yml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().DurationVar(&commandTimeout, "command-timeout", 0, "time limit for container up, down and build operations, e.g. 30m (default from timeouts in ~/.phpier/config.yaml)")
 
 	// Bind flags to viper
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"phpier/internal/config"
+	"phpier/internal/errors"
+	"phpier/internal/generator"
+	"phpier/internal/routes"
+	"phpier/internal/templates"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	routeHosts []string
+	routeTo    string
+)
+
+// routeCmd represents the route command
+var routeCmd = &cobra.Command{
+	Use:   "route",
+	Short: "Route domains to services running outside phpier",
+	Long: `Route domains through Traefik to any HTTP upstream, such as a Go API or Node
+service running on the host.
+
+Each route is written to ~/.phpier/traefik/dynamic/route-<name>.yml, which Traefik
+picks up without a restart. Routes get the same domain scheme and HTTPS certificates
+as PHP projects; the certificate is ~/.phpier/certs/route-<name>.pem, separate from
+project certificates. From inside Traefik, the host is reachable as host.docker.internal.
+
+Examples:
+  phpier route add api --to http://host.docker.internal:8081
+  phpier route add docs --host docs.localhost --host help.localhost --to http://host.docker.internal:3000
+  phpier route list
+  phpier route remove api`,
+}
+
+// routeAddCmd represents the route add command
+var routeAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add or update a route",
+	Long: `Add or update a route to an upstream URL.
+
+Without --host, the route is served on <name>.<domain>.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRouteAdd,
+}
+
+// routeListCmd represents the route list command
+var routeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List routes",
+	RunE:  runRouteList,
+}
+
+// routeRemoveCmd represents the route remove command
+var routeRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a route",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRouteRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(routeCmd)
+	routeCmd.AddCommand(routeAddCmd)
+	routeCmd.AddCommand(routeListCmd)
+	routeCmd.AddCommand(routeRemoveCmd)
+
+	routeAddCmd.Flags().StringSliceVar(&routeHosts, "host", nil, "Host name to route (repeatable; default: <name>.<domain>)")
+	routeAddCmd.Flags().StringVar(&routeTo, "to", "", "Upstream URL, e.g. http://host.docker.internal:8081")
+	routeAddCmd.MarkFlagRequired("to")
+}
+
+func runRouteAdd(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load global config", err)
+	}
+
+	route := &routes.Route{
+		Name:  args[0],
+		Hosts: routeHosts,
+		URL:   routeTo,
+	}
+	if len(route.Hosts) == 0 {
+		route.Hosts = []string{route.Name + "." + globalCfg.Traefik.Domain}
+	}
+	if err := route.Validate(); err != nil {
+		return errors.NewInvalidArgumentsError(err.Error())
+	}
+
+	if err := generator.GenerateRoute(templates.NewEngine(), route, globalCfg); err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to write route", err)
+	}
+
+	for _, host := range route.Hosts {
+		logrus.Infof("✅ http://%s and https://%s → %s", host, host, route.URL)
+	}
+	if strings.Contains(route.URL, "localhost") || strings.Contains(route.URL, "127.0.0.1") {
+		logrus.Warnf("⚠️  Inside Traefik, localhost is the Traefik container; use host.docker.internal to reach the host")
+	}
+	return nil
+}
+
+func runRouteList(cmd *cobra.Command, args []string) error {
+	dir, err := generator.TraefikDynamicDir()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to locate Traefik configuration", err)
+	}
+
+	list, err := routes.List(dir)
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to read routes", err)
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No routes. Add one with 'phpier route add <name> --to <url>'.")
+		return nil
+	}
+
+	fmt.Printf("%-20s %-40s %s\n", "NAME", "HOSTS", "UPSTREAM")
+	for _, route := range list {
+		fmt.Printf("%-20s %-40s %s\n", route.Name, strings.Join(route.Hosts, ","), route.URL)
+	}
+	return nil
+}
+
+func runRouteRemove(cmd *cobra.Command, args []string) error {
+	dir, err := generator.TraefikDynamicDir()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to locate Traefik configuration", err)
+	}
+
+	name := args[0]
+	if !routes.Exists(dir, name) {
+		return errors.NewInvalidArgumentsError(fmt.Sprintf("route %q does not exist", name)).
+			WithSuggestion("Run 'phpier route list' to see the configured routes")
+	}
+
+	if err := generator.RemoveRoute(name); err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to remove route", err)
+	}
+
+	logrus.Infof("✅ Removed route %s", name)
+	return nil
+}
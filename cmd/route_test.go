@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteCommand(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.AddCommand(routeCmd)
+
+	for name, use := range map[string]string{"add": "add <name>", "list": "list", "remove": "remove <name>"} {
+		foundCmd, _, err := cmd.Find([]string{"route", name})
+		assert.NoError(t, err)
+		assert.Equal(t, use, foundCmd.Use)
+	}
+
+	hostFlag := routeAddCmd.Flags().Lookup("host")
+	assert.NotNil(t, hostFlag)
+	assert.Equal(t, "stringSlice", hostFlag.Value.Type())
+
+	toFlag := routeAddCmd.Flags().Lookup("to")
+	assert.NotNil(t, toFlag)
+	assert.Equal(t, "string", toFlag.Value.Type())
+}
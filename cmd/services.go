@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -74,13 +73,13 @@ func init() {
 
 func runServices(cmd *cobra.Command, args []string) error {
 	// Create Docker client
-	dockerClient, err := docker.NewClient()
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
 	if err != nil {
 		return err
 	}
 	defer dockerClient.Close()
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 
 	// Prepare filter
 	filter := &docker.ServicesFilter{}
@@ -145,6 +144,18 @@ func filterServicesByType(services []docker.ServiceInfo, serviceType string) []d
 	return filtered
 }
 
+// databaseServiceNames returns the service names of the database engines and of the
+// configured database instances
+func databaseServiceNames() []string {
+	names := []string{"mysql", "postgres", "mariadb", "postgresql"}
+	if globalConfig, err := config.LoadGlobalConfig(); err == nil {
+		for _, instance := range globalConfig.Services.Databases {
+			names = append(names, instance.Name)
+		}
+	}
+	return names
+}
+
 // matchesServiceType checks if a service matches the specified type
 func matchesServiceType(service docker.ServiceInfo, serviceType string) bool {
 	serviceName := strings.ToLower(service.Service)
@@ -155,8 +166,7 @@ func matchesServiceType(service docker.ServiceInfo, serviceType string) bool {
 		return serviceName == "app" || strings.Contains(containerName, "-app-")
 
 	case "db", "database":
-		dbServices := []string{"mysql", "postgres", "mariadb", "postgresql"}
-		for _, db := range dbServices {
+		for _, db := range databaseServiceNames() {
 			if serviceName == db || strings.Contains(containerName, "-"+db+"-") {
 				return true
 			}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -42,7 +41,7 @@ Examples:
 }
 
 func runSh(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx := cmd.Context()
 
 	// Load project configuration
 	projectConfig, err := config.LoadProjectConfig()
@@ -51,7 +50,7 @@ func runSh(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create Docker client
-	dockerClient, err := docker.NewClient()
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
 	if err != nil {
 		return err
 	}
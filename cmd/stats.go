@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"phpier/internal/config"
+	"phpier/internal/display"
+	"phpier/internal/docker"
+	"phpier/internal/errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	statsAll      bool
+	statsStopped  bool
+	statsProject  string
+	statsNoStream bool
+	statsJSON     bool
+	statsInterval time.Duration
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show live CPU, memory, network and disk usage of phpier containers",
+	Long: `Show the resource usage of running phpier containers and refresh it until Ctrl-C.
+
+Inside a project only its containers are shown; --all shows every project and the global
+services, grouped into one table each. Outside a project every container is shown.
+
+The memory limit column shows the limit set under 'resources' in .phpier.yml or
+~/.phpier/config.yaml, or the host memory for containers without one.
+
+Examples:
+  phpier stats                  # Live view of the current project's containers
+  phpier stats --all            # Every project and the global services
+  phpier stats --stopped        # Include stopped containers
+  phpier stats --project shop   # Only the shop project
+  phpier stats --no-stream      # Print one sample and exit
+  phpier stats --json           # One sample as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().BoolVarP(&statsAll, "all", "a", false, "Show every project and the global services, not just the current project")
+	statsCmd.Flags().BoolVar(&statsStopped, "stopped", false, "Include containers that aren't running")
+	statsCmd.Flags().StringVarP(&statsProject, "project", "p", "", "Only show containers of this project")
+	statsCmd.MarkFlagsMutuallyExclusive("all", "project")
+	statsCmd.Flags().BoolVar(&statsNoStream, "no-stream", false, "Print one sample instead of a live view")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Print one sample as JSON")
+	statsCmd.Flags().DurationVar(&statsInterval, "interval", 2*time.Second, "Time between refreshes of the live view")
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	dockerClient, err := docker.NewClient(cmd.Context(), commandTimeout)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	options := docker.StatsOptions{Project: statsProject, All: statsStopped}
+	// Without --all or --project the stats are those of the current project, when there is one
+	if options.Project == "" && !statsAll && isProjectInitialized() {
+		projectCfg, err := config.LoadProjectConfig()
+		if err != nil {
+			return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load project config", err)
+		}
+		options.Project = projectCfg.Name
+	}
+	displayOptions := display.TableOptions{ShowHeaders: true, ColorOutput: !viper.GetBool("no-color")}
+
+	for {
+		stats, err := dockerClient.GetPhpierStats(cmd.Context(), options)
+		if cmd.Context().Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return errors.WrapError(errors.ErrorTypeDockerError, "Failed to get container stats", err)
+		}
+
+		if statsJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(stats)
+		}
+		if statsNoStream {
+			fmt.Println(display.RenderStatsTable(stats, displayOptions))
+			return nil
+		}
+
+		// Redraw in place: cursor home, then clear the screen
+		fmt.Print("\033[H\033[2J")
+		fmt.Println(display.RenderStatsTable(stats, displayOptions))
+
+		select {
+		case <-cmd.Context().Done():
+			return nil
+		case <-time.After(statsInterval):
+		}
+	}
+}
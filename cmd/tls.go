@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"phpier/internal/certs"
+	"phpier/internal/config"
+	"phpier/internal/errors"
+	"phpier/internal/generator"
+	"phpier/internal/templates"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var tlsTrustApply bool
+
+// tlsCmd represents the tls command
+var tlsCmd = &cobra.Command{
+	Use:   "tls",
+	Short: "Manage local HTTPS certificates",
+	Long: `Manage the local certificate authority (CA) phpier uses to serve projects over HTTPS.
+
+phpier creates its own root CA in ~/.phpier/ca the first time global services are
+generated and issues certificates from it:
+- a default wildcard certificate for *.<domain> (global tools and unknown hosts)
+- a certificate per project for <project>.<domain> and *.<project>.<domain>
+
+Certificates are written to ~/.phpier/certs and registered with Traefik through
+~/.phpier/traefik/dynamic, so every project is reachable on https://<project>.<domain>.
+Trust the CA once with 'phpier tls trust --apply' to avoid browser warnings.
+
+Examples:
+  phpier tls trust          # Print the commands that trust the phpier CA
+  phpier tls trust --apply  # Run them (uses sudo)
+  phpier tls status         # Show the CA and issued certificates
+  phpier tls issue          # (Re)issue the certificate for the current project`,
+}
+
+// tlsTrustCmd represents the tls trust command
+var tlsTrustCmd = &cobra.Command{
+	Use:   "trust",
+	Short: "Print the steps that install the phpier root CA into the system trust store",
+	Long: `Print the commands that install the phpier root CA into the operating system trust
+store, so browsers and tools accept certificates issued for local projects.
+
+Installing into the system trust store requires administrator privileges. With --apply
+phpier runs the commands itself, using sudo only for those that need it. Firefox keeps its
+own trust store and needs the CA imported separately (Settings → Privacy & Security →
+Certificates → View Certificates → Import).`,
+	RunE: runTLSTrust,
+}
+
+// tlsStatusCmd represents the tls status command
+var tlsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the phpier CA and issued certificates",
+	RunE:  runTLSStatus,
+}
+
+// tlsIssueCmd represents the tls issue command
+var tlsIssueCmd = &cobra.Command{
+	Use:   "issue",
+	Short: "Issue certificates for the current project and the global domain",
+	Long: `Issue (or renew) the certificate for the current project and the default wildcard
+certificate. Certificates are normally issued automatically by 'phpier up' and
+'phpier global up'; this command is useful after changing the global domain.`,
+	RunE: runTLSIssue,
+}
+
+func init() {
+	rootCmd.AddCommand(tlsCmd)
+	tlsCmd.AddCommand(tlsTrustCmd)
+	tlsCmd.AddCommand(tlsStatusCmd)
+	tlsCmd.AddCommand(tlsIssueCmd)
+
+	tlsTrustCmd.Flags().BoolVar(&tlsTrustApply, "apply", false, "Run the commands that trust the CA instead of printing them")
+}
+
+func runTLSTrust(cmd *cobra.Command, args []string) error {
+	caDir, err := certs.DefaultCADir()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to locate CA directory", err)
+	}
+
+	if _, err := certs.EnsureCA(caDir); err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to create local CA", err)
+	}
+
+	caCert := certs.CACertPath(caDir)
+	steps := trustCommands(caCert)
+	if len(steps) == 0 {
+		fmt.Printf("Automatic trust is not supported on %s.\n", runtime.GOOS)
+		fmt.Printf("Import %s into your system or browser trust store manually.\n", caCert)
+		return nil
+	}
+
+	if !tlsTrustApply {
+		fmt.Printf("Run the following to trust the phpier CA (%s):\n\n", caCert)
+		for _, step := range steps {
+			fmt.Printf("  %s\n", strings.Join(step, " "))
+		}
+		fmt.Println("\nOr run 'phpier tls trust --apply' to have phpier run them.")
+		return nil
+	}
+
+	logrus.Infof("🔐 Installing phpier CA into the system trust store...")
+	for _, step := range steps {
+		logrus.Debugf("Executing: %s", strings.Join(step, " "))
+		execCmd := exec.Command(step[0], step[1:]...)
+		execCmd.Stdout = os.Stdout
+		execCmd.Stderr = os.Stderr
+		execCmd.Stdin = os.Stdin
+		if err := execCmd.Run(); err != nil {
+			return errors.WrapError(errors.ErrorTypeCommandFailed, "Failed to install CA", err).
+				WithSuggestion("Run 'phpier tls trust' and execute the commands it prints manually")
+		}
+	}
+
+	logrus.Infof("✅ phpier CA trusted. Restart your browser to pick it up.")
+	logrus.Infof("📝 Firefox uses its own trust store: import %s there if needed.", caCert)
+	return nil
+}
+
+// trustCommands returns the commands that install caCert into the OS trust store
+func trustCommands(caCert string) [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{
+			withSudo("security", "add-trusted-cert", "-d", "-r", "trustRoot", "-k", "/Library/Keychains/System.keychain", caCert),
+		}
+	case "linux":
+		// Debian/Ubuntu style
+		if _, err := os.Stat("/usr/local/share/ca-certificates"); err == nil {
+			return [][]string{
+				withSudo("cp", caCert, "/usr/local/share/ca-certificates/phpier-rootCA.crt"),
+				withSudo("update-ca-certificates"),
+			}
+		}
+		// Fedora/RHEL style
+		if _, err := os.Stat("/etc/pki/ca-trust/source/anchors"); err == nil {
+			return [][]string{
+				withSudo("cp", caCert, "/etc/pki/ca-trust/source/anchors/phpier-rootCA.pem"),
+				withSudo("update-ca-trust", "extract"),
+			}
+		}
+		// Arch style
+		if _, err := exec.LookPath("trust"); err == nil {
+			return [][]string{
+				withSudo("trust", "anchor", "--store", caCert),
+			}
+		}
+	case "windows":
+		return [][]string{
+			{"certutil", "-addstore", "-f", "ROOT", caCert},
+		}
+	}
+	return nil
+}
+
+// withSudo prefixes a command with sudo unless phpier already runs as root
+func withSudo(command ...string) []string {
+	if os.Geteuid() == 0 {
+		return command
+	}
+	return append([]string{"sudo"}, command...)
+}
+
+func runTLSStatus(cmd *cobra.Command, args []string) error {
+	caDir, err := certs.DefaultCADir()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to locate CA directory", err)
+	}
+	certsDir, err := certs.DefaultCertsDir()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to locate certificates directory", err)
+	}
+
+	ca, err := certs.LoadCA(caDir)
+	if err != nil {
+		fmt.Println("No phpier CA found yet.")
+		fmt.Println("Run 'phpier global up' or 'phpier tls issue' to create it.")
+		return nil
+	}
+
+	fmt.Printf("CA:      %s\n", certs.CACertPath(caDir))
+	fmt.Printf("Expires: %s\n\n", ca.Cert.NotAfter.Format("2006-01-02"))
+
+	files, _ := filepath.Glob(filepath.Join(certsDir, "*.pem"))
+	sort.Strings(files)
+
+	fmt.Printf("%-20s %-12s %s\n", "CERTIFICATE", "EXPIRES", "HOSTS")
+	for _, file := range files {
+		if strings.HasSuffix(file, "-key.pem") {
+			continue
+		}
+		cert, err := certs.ReadCertificate(file)
+		if err != nil {
+			logrus.Debugf("Skipping %s: %v", file, err)
+			continue
+		}
+
+		expires := cert.NotAfter.Format("2006-01-02")
+		if time.Now().After(cert.NotAfter) {
+			expires += " (expired)"
+		}
+
+		hosts := append([]string{}, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			hosts = append(hosts, ip.String())
+		}
+
+		name := strings.TrimSuffix(filepath.Base(file), ".pem")
+		fmt.Printf("%-20s %-12s %s\n", name, expires, strings.Join(hosts, ", "))
+	}
+
+	return nil
+}
+
+func runTLSIssue(cmd *cobra.Command, args []string) error {
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load global config", err)
+	}
+
+	engine := templates.NewEngine()
+	if err := generator.GenerateGlobalCertificate(engine, globalCfg); err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to issue default certificate", err)
+	}
+	logrus.Infof("✅ Default certificate issued for %s and the global tools", globalCfg.Traefik.Domain)
+
+	if !isProjectInitialized() {
+		return nil
+	}
+
+	projectCfg, err := config.LoadProjectConfig()
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeConfigNotFound, "Failed to load project config", err)
+	}
+	if err := generator.GenerateProjectCertificate(engine, projectCfg, globalCfg); err != nil {
+		return errors.WrapError(errors.ErrorTypeFileSystemError, "Failed to issue project certificate", err)
+	}
+	logrus.Infof("✅ Certificate issued for %s.%s", projectCfg.Name, globalCfg.Traefik.Domain)
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSCommand(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.AddCommand(tlsCmd)
+
+	for _, sub := range []string{"trust", "status", "issue"} {
+		foundCmd, _, err := cmd.Find([]string{"tls", sub})
+		assert.NoError(t, err)
+		assert.Equal(t, sub, foundCmd.Use)
+	}
+
+	flag := tlsTrustCmd.Flags().Lookup("apply")
+	assert.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+}
+
+func TestTrustCommandsReferenceCA(t *testing.T) {
+	for _, step := range trustCommands("/tmp/rootCA.pem") {
+		assert.NotEmpty(t, step)
+	}
+}
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"os"
+	"time"
+
 	"phpier/internal/config"
 	"phpier/internal/docker"
 	"phpier/internal/errors"
@@ -16,8 +19,14 @@ var (
 	detached   bool
 	build      bool
 	skipGlobal bool
+	upWait     bool
+	upTimeout  time.Duration
 )
 
+// defaultWaitTimeout is how long --wait gives services to become healthy by default; a
+// database initialising its data directory for the first time takes most of it
+const defaultWaitTimeout = 2 * time.Minute
+
 // upCmd represents the up command
 var upCmd = &cobra.Command{
 	Use:   "up [app]",
@@ -29,11 +38,17 @@ This command will:
 - Ensure the global services network is available.
 - Optionally start a specific project by name from any directory.
 
+With --wait the containers start in the background and phpier waits until the global
+services and the app report healthy, showing each service's progress. If a service turns
+unhealthy, exits or isn't healthy within --timeout, its last log lines are shown.
+
 Examples:
   phpier up                 # Start app container in the foreground
   phpier up -d              # Start app container in the background (detached)
   phpier up --build         # Rebuild the app container image before starting
   phpier up --skip-global   # Start only project services, skip global service check
+  phpier up --wait          # Start in the background and wait until all services are healthy
+  phpier up --wait --timeout 5m
   phpier up myapp           # Start 'myapp' project from any directory
   phpier up myapp -d        # Start 'myapp' project in the background`,
 	RunE: runUp,
@@ -46,6 +61,8 @@ func init() {
 	upCmd.Flags().BoolVarP(&detached, "detach", "d", false, "Run services in the background")
 	upCmd.Flags().BoolVar(&build, "build", false, "Build images before starting services")
 	upCmd.Flags().BoolVar(&skipGlobal, "skip-global", false, "Skip automatic global service startup check")
+	upCmd.Flags().BoolVar(&upWait, "wait", false, "Start in the background and wait until services are healthy")
+	upCmd.Flags().DurationVar(&upTimeout, "timeout", defaultWaitTimeout, "How long --wait waits for each stack's services")
 }
 
 func runUp(cmd *cobra.Command, args []string) error {
@@ -104,24 +121,34 @@ func runUp(cmd *cobra.Command, args []string) error {
 
 	// Check and start global services if needed (unless --skip-global flag is used)
 	if !skipGlobal {
-		if err := ensureGlobalServicesRunning(globalCfg); err != nil {
+		if err := ensureGlobalServicesRunning(cmd.Context(), globalCfg); err != nil {
 			return errors.WrapError(errors.ErrorTypeDockerError, "Failed to ensure global services are running", err)
 		}
+		if upWait {
+			if err := waitForServices(cmd.Context(), "phpier", "global", upTimeout); err != nil {
+				return err
+			}
+		}
 	} else {
 		logrus.Infof("⏭️  Skipping global service startup check (--skip-global flag used)")
 	}
 
+	// Issue (or renew) the project's HTTPS certificate; Traefik picks it up from its dynamic config
+	if err := generator.GenerateProjectCertificate(templates.NewEngine(), projectCfg, globalCfg); err != nil {
+		logrus.Warnf("Failed to issue HTTPS certificate: %v", err)
+	}
+
 	// Create Docker Compose manager
 	var composeManager *docker.ProjectComposeManager
 	if len(args) > 0 {
 		// Use specific project path
-		composeManager, err = docker.NewProjectComposeManagerWithPath(projectCfg, globalCfg, projectPath)
+		composeManager, err = docker.NewProjectComposeManagerWithPath(cmd.Context(), projectCfg, globalCfg, projectPath, commandTimeout)
 		if err != nil {
 			return errors.WrapError(errors.ErrorTypeDockerError, "Failed to create Docker client", err)
 		}
 	} else {
 		// Use current directory
-		composeManager, err = docker.NewProjectComposeManager(projectCfg, globalCfg)
+		composeManager, err = docker.NewProjectComposeManager(cmd.Context(), projectCfg, globalCfg, commandTimeout)
 		if err != nil {
 			return errors.WrapError(errors.ErrorTypeDockerError, "Failed to create Docker client", err)
 		}
@@ -135,14 +162,20 @@ func runUp(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Start services
+	// Start services; waiting needs them in the background
 	logrus.Infof("🚀 Starting project container...")
-	if err := composeManager.Up(detached); err != nil {
+	if err := composeManager.Up(detached || upWait); err != nil {
 		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to start project container", err)
 	}
 
+	if upWait {
+		if err := waitForServices(cmd.Context(), projectCfg.Name, "project", upTimeout); err != nil {
+			return err
+		}
+	}
+
 	logrus.Infof("✅ Project services started successfully!")
-	if detached {
+	if detached || upWait {
 		logrus.Infof("📝 Services are running in the background. Use 'phpier down' to stop them.")
 	}
 
@@ -150,9 +183,9 @@ func runUp(cmd *cobra.Command, args []string) error {
 }
 
 // ensureGlobalServicesRunning checks if global services are running and starts them if needed
-func ensureGlobalServicesRunning(globalCfg *config.GlobalConfig) error {
+func ensureGlobalServicesRunning(ctx context.Context, globalCfg *config.GlobalConfig) error {
 	// Create global compose manager
-	globalManager, err := docker.NewGlobalComposeManager(globalCfg)
+	globalManager, err := docker.NewGlobalComposeManager(ctx, globalCfg, commandTimeout)
 	if err != nil {
 		return err
 	}
@@ -187,6 +220,28 @@ func ensureGlobalServicesRunning(globalCfg *config.GlobalConfig) error {
 	return nil
 }
 
+// waitForServices waits until the containers of a compose project are healthy, logging the
+// progress of each service
+func waitForServices(ctx context.Context, project, label string, timeout time.Duration) error {
+	dockerClient, err := docker.NewClient(ctx, commandTimeout)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	logrus.Infof("⏳ Waiting for %s services to become healthy...", label)
+	return dockerClient.WaitHealthy(ctx, project, timeout, func(service docker.ServiceHealth) {
+		switch {
+		case service.Ready():
+			logrus.Infof("   ✅ %s: %s", service.Service, service.Status())
+		case service.Failed():
+			logrus.Errorf("   ❌ %s: %s", service.Service, service.Status())
+		default:
+			logrus.Infof("   ⏳ %s: %s", service.Service, service.Status())
+		}
+	})
+}
+
 // isProjectInitialized checks if the current directory has been initialized as a phpier project
 func isProjectInitialized() bool {
 	if _, err := os.Stat(".phpier.yml"); os.IsNotExist(err) {
@@ -0,0 +1,299 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// CACertFile is the file name of the phpier root CA certificate
+	CACertFile = "rootCA.pem"
+	// CAKeyFile is the file name of the phpier root CA private key
+	CAKeyFile = "rootCA-key.pem"
+
+	caValidity   = 10 * 365 * 24 * time.Hour
+	leafValidity = 825 * 24 * time.Hour // Upper bound browsers accept for leaf certificates
+	renewBefore  = 30 * 24 * time.Hour
+)
+
+// CA is the phpier-managed local certificate authority
+type CA struct {
+	Dir  string
+	Cert *x509.Certificate
+	Key  *ecdsa.PrivateKey
+}
+
+// Certificate describes an issued certificate on disk
+type Certificate struct {
+	Name     string
+	CertFile string
+	KeyFile  string
+	Hosts    []string
+}
+
+// DefaultCADir returns the directory holding the root CA (~/.phpier/ca).
+// It is kept apart from the issued certificates so the CA key is never mounted into containers.
+func DefaultCADir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".phpier", "ca"), nil
+}
+
+// DefaultCertsDir returns the directory holding issued certificates (~/.phpier/certs)
+func DefaultCertsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".phpier", "certs"), nil
+}
+
+// CACertPath returns the path of the root CA certificate in dir
+func CACertPath(dir string) string {
+	return filepath.Join(dir, CACertFile)
+}
+
+// EnsureCA loads the root CA from dir, creating a new one if it doesn't exist yet
+func EnsureCA(dir string) (*CA, error) {
+	certPath := filepath.Join(dir, CACertFile)
+	keyPath := filepath.Join(dir, CAKeyFile)
+
+	if _, err := os.Stat(certPath); err == nil {
+		return LoadCA(dir)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization:       []string{"phpier development CA"},
+			OrganizationalUnit: []string{hostname},
+			CommonName:         "phpier local root CA",
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+
+	if err := writePEM(certPath, "CERTIFICATE", der, 0644); err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CA key: %w", err)
+	}
+	if err := writePEM(keyPath, "EC PRIVATE KEY", keyDER, 0600); err != nil {
+		return nil, err
+	}
+
+	return LoadCA(dir)
+}
+
+// LoadCA loads an existing root CA from dir
+func LoadCA(dir string) (*CA, error) {
+	cert, err := ReadCertificate(filepath.Join(dir, CACertFile))
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := os.ReadFile(filepath.Join(dir, CAKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA key: %w", err)
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("invalid CA key in %s", dir)
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA key: %w", err)
+	}
+
+	return &CA{Dir: dir, Cert: cert, Key: key}, nil
+}
+
+// Issue returns a certificate for hosts stored as <name>.pem and <name>-key.pem in dir.
+// An existing certificate is reused while it covers the same hosts and isn't close to expiring.
+func (ca *CA) Issue(dir, name string, hosts []string) (*Certificate, error) {
+	hosts = normalizeHosts(hosts)
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("no hosts given for certificate %s", name)
+	}
+
+	issued := &Certificate{
+		Name:     name,
+		CertFile: filepath.Join(dir, name+".pem"),
+		KeyFile:  filepath.Join(dir, name+"-key.pem"),
+		Hosts:    hosts,
+	}
+
+	if existing, err := ReadCertificate(issued.CertFile); err == nil && ca.isCurrent(existing, hosts) {
+		return issued, nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key for %s: %w", name, err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"phpier development certificate"},
+			CommonName:   hosts[0],
+		},
+		NotBefore:   time.Now().Add(-time.Hour),
+		NotAfter:    time.Now().Add(leafValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, &key.PublicKey, ca.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate for %s: %w", name, err)
+	}
+
+	if err := writePEM(issued.CertFile, "CERTIFICATE", der, 0644); err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode key for %s: %w", name, err)
+	}
+	if err := writePEM(issued.KeyFile, "EC PRIVATE KEY", keyDER, 0600); err != nil {
+		return nil, err
+	}
+
+	return issued, nil
+}
+
+// isCurrent checks whether cert was signed by this CA, covers hosts and is not about to expire
+func (ca *CA) isCurrent(cert *x509.Certificate, hosts []string) bool {
+	if time.Until(cert.NotAfter) < renewBefore {
+		return false
+	}
+	if err := cert.CheckSignatureFrom(ca.Cert); err != nil {
+		return false
+	}
+
+	var existing []string
+	existing = append(existing, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		existing = append(existing, ip.String())
+	}
+	wanted := append([]string(nil), hosts...)
+	sort.Strings(existing)
+	sort.Strings(wanted)
+
+	if len(existing) != len(wanted) {
+		return false
+	}
+	for i := range wanted {
+		if existing[i] != wanted[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeHosts sorts and de-duplicates hosts, keeping the first given host first for the CN
+func normalizeHosts(hosts []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, host := range hosts {
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		result = append(result, host)
+	}
+	if len(result) > 1 {
+		rest := result[1:]
+		sort.Strings(rest)
+	}
+	return result
+}
+
+// ReadCertificate parses the PEM encoded certificate stored at path
+func ReadCertificate(path string) (*x509.Certificate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate %s: %w", path, err)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("invalid certificate in %s", path)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate %s: %w", path, err)
+	}
+	return cert, nil
+}
+
+func writePEM(path, blockType string, der []byte, mode os.FileMode) error {
+	content := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, content, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return serial, nil
+}
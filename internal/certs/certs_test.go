@@ -0,0 +1,57 @@
+package certs
+
+import (
+	"crypto/x509"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureCA_CreatesAndReloads(t *testing.T) {
+	dir := t.TempDir()
+
+	ca, err := EnsureCA(dir)
+	require.NoError(t, err)
+	assert.True(t, ca.Cert.IsCA)
+
+	info, err := os.Stat(dir + "/" + CAKeyFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "CA key must not be world readable")
+
+	reloaded, err := EnsureCA(dir)
+	require.NoError(t, err)
+	assert.Equal(t, ca.Cert.SerialNumber, reloaded.Cert.SerialNumber, "existing CA is reused")
+}
+
+func TestCA_Issue(t *testing.T) {
+	ca, err := EnsureCA(t.TempDir())
+	require.NoError(t, err)
+	certsDir := t.TempDir()
+
+	issued, err := ca.Issue(certsDir, "shop", []string{"shop.localhost", "*.shop.localhost"})
+	require.NoError(t, err)
+
+	cert, err := ReadCertificate(issued.CertFile)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	for _, host := range []string{"shop.localhost", "vite.shop.localhost"} {
+		_, err := cert.Verify(x509.VerifyOptions{DNSName: host, Roots: roots})
+		assert.NoError(t, err, "certificate should be valid for %s", host)
+	}
+
+	again, err := ca.Issue(certsDir, "shop", []string{"*.shop.localhost", "shop.localhost"})
+	require.NoError(t, err)
+	reissued, err := ReadCertificate(again.CertFile)
+	require.NoError(t, err)
+	assert.Equal(t, cert.SerialNumber, reissued.SerialNumber, "certificate covering the same hosts is reused")
+
+	_, err = ca.Issue(certsDir, "shop", []string{"shop.test"})
+	require.NoError(t, err)
+	changed, err := ReadCertificate(issued.CertFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shop.test"}, changed.DNSNames, "certificate is reissued when hosts change")
+}
@@ -1,29 +1,91 @@
 package config
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+	"phpier/internal/dockerapi"
 	"phpier/internal/errors"
+	"phpier/internal/runtime"
 )
 
 // ProjectConfig represents the project-specific configuration
 type ProjectConfig struct {
-	Name string    `mapstructure:"name"`
-	PHP  string    `mapstructure:"php"`
-	Node string    `mapstructure:"node"`
-	App  AppConfig `mapstructure:"app"`
+	Name        string            `mapstructure:"name" yaml:"name"`
+	PHP         string            `mapstructure:"php" yaml:"php"`
+	Node        string            `mapstructure:"node" yaml:"node"`
+	App         AppConfig         `mapstructure:"app" yaml:"app"`
+	Build       BuildConfig       `mapstructure:"build" yaml:"build,omitempty"`
+	Middlewares MiddlewaresConfig `mapstructure:"middlewares" yaml:"middlewares,omitempty"`
+	DevServer   DevServerConfig   `mapstructure:"devserver" yaml:"devserver,omitempty"`
+	Resources   ResourcesConfig   `mapstructure:"resources" yaml:"resources,omitempty"`
+	Database    ProjectDatabase   `mapstructure:"database" yaml:"database,omitempty"`
+}
+
+// projectConfigFile is the .phpier.yml wrapper; project settings live in the
+// x-phpier extension block, which Docker Compose ignores
+type projectConfigFile struct {
+	Phpier *ProjectConfig `yaml:"x-phpier"`
 }
 
 // GlobalConfig represents the global configuration (~/.phpier/config.yaml)
 type GlobalConfig struct {
 	Services ServicesConfig `mapstructure:"services"`
 	Traefik  TraefikConfig  `mapstructure:"traefik"`
+	DNS      DNSConfig      `mapstructure:"dns"`
 	Network  string         `mapstructure:"network"`
+	Runtime  string         `mapstructure:"runtime"` // auto, docker, podman or nerdctl
+	Timeouts TimeoutsConfig `mapstructure:"timeouts"`
+	Logging  LoggingConfig  `mapstructure:"logging"`
+}
+
+// TimeoutsConfig limits how long container operations may run; 0 means no limit
+type TimeoutsConfig struct {
+	API   time.Duration `mapstructure:"api"`   // Each engine query (list, inspect, exec output) and the runtime check
+	Up    time.Duration `mapstructure:"up"`    // compose up, including the image build of a first start
+	Down  time.Duration `mapstructure:"down"`  // compose down
+	Build time.Duration `mapstructure:"build"` // Image builds and pulls
+}
+
+// LoggingConfig caps the logs of phpier containers and the log files under .phpier/logs.
+// An empty MaxSize leaves logs unbounded.
+type LoggingConfig struct {
+	MaxSize  string `mapstructure:"max_size"`  // Size a log is rotated at, e.g. 10m
+	MaxFiles int    `mapstructure:"max_files"` // Logs kept per container or file, including the current one
+}
+
+// MaxBytes returns MaxSize in bytes, or 0 when logs are unbounded
+func (l LoggingConfig) MaxBytes() int64 {
+	bytes, _ := parseMemory(l.MaxSize)
+	return int64(bytes)
+}
+
+// Validate checks that MaxSize is a size and at least one file is kept
+func (l LoggingConfig) Validate() error {
+	if l.MaxSize == "" {
+		return nil
+	}
+	if _, ok := parseMemory(l.MaxSize); !ok {
+		return fmt.Errorf("invalid logging.max_size %q: use a size such as 10m or 1g", l.MaxSize)
+	}
+	if l.MaxFiles < 1 {
+		return fmt.Errorf("invalid logging.max_files %d: at least the current log is kept", l.MaxFiles)
+	}
+	return nil
 }
 
 // DockerConfig contains Docker-related configuration for the project
@@ -38,14 +100,462 @@ type PHPConfig struct {
 
 // AppConfig contains application container configuration
 type AppConfig struct {
-	Volumes     []string `mapstructure:"volumes"`     // Volume mappings (default: ["./:/var/www/html"])
-	Environment []string `mapstructure:"environment"` // Environment variables (optional)
+	Volumes     []string `mapstructure:"volumes" yaml:"volumes"`                   // Volume mappings (default: ["./:/var/www/html"])
+	Environment []string `mapstructure:"environment" yaml:"environment,omitempty"` // Environment variables (optional)
+}
+
+// BuildConfig contains image build customizations for the app container
+type BuildConfig struct {
+	Args           map[string]string `mapstructure:"args" yaml:"args,omitempty"`                       // Extra --build-arg values
+	Secrets        map[string]string `mapstructure:"secrets" yaml:"secrets,omitempty"`                 // Build secret id -> host file
+	CACertificates []string          `mapstructure:"ca_certificates" yaml:"ca_certificates,omitempty"` // Host CA certificates to trust
+}
+
+// BuildProxyVars are the host proxy settings passed to image builds when they are set
+var BuildProxyVars = []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy"}
+
+// ComposeArgs returns the build arguments for the app service in .phpier.yml: the host proxy
+// settings, which Compose takes from the environment when they are set, then Args sorted by
+// name. $ is escaped so Compose doesn't interpolate the values.
+func (b BuildConfig) ComposeArgs() []string {
+	var args []string
+	for _, name := range BuildProxyVars {
+		if _, ok := b.Args[name]; !ok {
+			args = append(args, name)
+		}
+	}
+
+	names := make([]string, 0, len(b.Args))
+	for name := range b.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, name+"="+strings.ReplaceAll(b.Args[name], "$", "$$"))
+	}
+	return args
+}
+
+// DevServerConfig routes a frontend dev server (Vite, webpack) running in the app container
+type DevServerConfig struct {
+	Enabled   bool   `mapstructure:"enabled" yaml:"enabled"`
+	Port      int    `mapstructure:"port" yaml:"port,omitempty"`           // Port the dev server listens on (default 5173)
+	Subdomain string `mapstructure:"subdomain" yaml:"subdomain,omitempty"` // Served on <subdomain>.<project>.<domain> (default vite)
+	Command   string `mapstructure:"command" yaml:"command,omitempty"`     // Command supervisor runs (default: npm run dev -- --host 0.0.0.0)
+	// HMRProtocol is the websocket protocol the browser uses for hot module replacement: wss
+	// (default, through Traefik's https entrypoint) or ws for pages only loaded over http
+	HMRProtocol string `mapstructure:"hmr_protocol" yaml:"hmr_protocol,omitempty"`
+}
+
+// Validate checks the dev server settings
+func (d DevServerConfig) Validate() error {
+	if d.HMRProtocol != "" && d.HMRProtocol != "ws" && d.HMRProtocol != "wss" {
+		return fmt.Errorf("invalid devserver.hmr_protocol %q (use ws or wss)", d.HMRProtocol)
+	}
+	return nil
+}
+
+// ProjectDatabase is the project's own database and user on one of the shared database servers
+type ProjectDatabase struct {
+	Engine   string `mapstructure:"engine" yaml:"engine"`               // mysql, postgresql or mariadb
+	Instance string `mapstructure:"instance" yaml:"instance,omitempty"` // Database instance (default: the engine's first)
+	Name     string `mapstructure:"name" yaml:"name"`
+	Username string `mapstructure:"username" yaml:"username"`
+	Password string `mapstructure:"password" yaml:"password"`
+}
+
+// databaseIdentifierPattern matches the database and user names phpier creates; they are
+// quoted in SQL, but are kept to names every engine accepts unquoted too
+var databaseIdentifierPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,31}$`)
+
+// ValidateDatabaseIdentifier checks that name can be used as a database or user name
+func ValidateDatabaseIdentifier(kind, name string) error {
+	if !databaseIdentifierPattern.MatchString(name) {
+		return fmt.Errorf("invalid %s name %q: use up to 32 lowercase letters, digits and underscores, not starting with a digit", kind, name)
+	}
+	return nil
+}
+
+// DatabaseIdentifier turns a project name into a database and user name, e.g. my-shop.v2
+// becomes my_shop_v2
+func DatabaseIdentifier(name string) string {
+	identifier := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '_'
+		}
+	}, name)
+	if identifier == "" || identifier[0] >= '0' && identifier[0] <= '9' {
+		identifier = "db_" + identifier
+	}
+	if len(identifier) > 32 {
+		identifier = identifier[:32]
+	}
+	return identifier
+}
+
+// NewProjectDatabase returns a database and user named after the project on a database
+// instance, with a random password
+func NewProjectDatabase(projectName string, instance DatabaseServiceConfig) (ProjectDatabase, error) {
+	password, err := GenerateDatabasePassword()
+	if err != nil {
+		return ProjectDatabase{}, err
+	}
+	name := DatabaseIdentifier(projectName)
+	return ProjectDatabase{Engine: instance.Engine, Instance: instance.Name, Name: name, Username: name, Password: password}, nil
+}
+
+// GenerateDatabasePassword returns a random password that needs no quoting in SQL or shells
+func GenerateDatabasePassword() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate a password: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Enabled reports whether the project has a database of its own
+func (d ProjectDatabase) Enabled() bool {
+	return d.Engine != ""
+}
+
+// Host returns the name the app container reaches the database server by
+func (d ProjectDatabase) Host() string {
+	if d.Instance != "" {
+		return d.Instance
+	}
+	return DatabaseServiceName(d.Engine)
+}
+
+// Server returns the instance the database is on: the one it names or, for databases created
+// before named instances, the engine's
+func (d ProjectDatabase) Server() string {
+	if d.Instance != "" {
+		return d.Instance
+	}
+	return d.Engine
+}
+
+// Port returns the port the database server listens on in its container
+func (d ProjectDatabase) Port() int {
+	if d.Engine == "postgresql" {
+		return 5432
+	}
+	return 3306
+}
+
+// Environment returns the DB_* variables that point the app at its database; the names are
+// the ones Laravel and Symfony's DATABASE_URL conventions expect
+func (d ProjectDatabase) Environment() []string {
+	connection, scheme := "mysql", "mysql"
+	if d.Engine == "postgresql" {
+		connection, scheme = "pgsql", "postgresql"
+	}
+	return []string{
+		"DB_CONNECTION=" + connection,
+		"DB_HOST=" + d.Host(),
+		"DB_PORT=" + strconv.Itoa(d.Port()),
+		"DB_DATABASE=" + d.Name,
+		"DB_USERNAME=" + d.Username,
+		"DB_PASSWORD=" + d.Password,
+		fmt.Sprintf("DATABASE_URL=%s://%s:%s@%s:%d/%s", scheme, d.Username, d.Password, d.Host(), d.Port(), d.Name),
+	}
+}
+
+// DatabaseEnvironment returns the variables of the project's database the app environment
+// doesn't set itself
+func (p *ProjectConfig) DatabaseEnvironment() []string {
+	if !p.Database.Enabled() {
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, env := range p.App.Environment {
+		name, _, _ := strings.Cut(env, "=")
+		set[name] = true
+	}
+	var environment []string
+	for _, env := range p.Database.Environment() {
+		if name, _, _ := strings.Cut(env, "="); !set[name] {
+			environment = append(environment, env)
+		}
+	}
+	return environment
+}
+
+// DatabaseServiceName returns the name of the default instance of a database engine, which
+// is its compose service and phpier-<service> container
+func DatabaseServiceName(engine string) string {
+	if engine == "postgresql" {
+		return "postgres"
+	}
+	return engine
+}
+
+// Dev server defaults
+const (
+	DefaultDevServerPort      = 5173
+	DefaultDevServerSubdomain = "vite"
+	DefaultDevServerCommand   = "npm run dev -- --host 0.0.0.0"
+)
+
+// DevServerPort returns the port the dev server listens on in the app container
+func (p *ProjectConfig) DevServerPort() int {
+	if p.DevServer.Port > 0 {
+		return p.DevServer.Port
+	}
+	return DefaultDevServerPort
+}
+
+// DevServerCommand returns the command that starts the dev server
+func (p *ProjectConfig) DevServerCommand() string {
+	if p.DevServer.Command != "" {
+		return p.DevServer.Command
+	}
+	return DefaultDevServerCommand
+}
+
+// DevServerDomain returns the host name the dev server is routed on
+func (p *ProjectConfig) DevServerDomain(domain string) string {
+	subdomain := p.DevServer.Subdomain
+	if subdomain == "" {
+		subdomain = DefaultDevServerSubdomain
+	}
+	return subdomain + "." + p.Name + "." + domain
+}
+
+// DevServerOrigins returns the project origins allowed to load assets from the dev server
+func (p *ProjectConfig) DevServerOrigins(traefik TraefikConfig) []string {
+	var origins []string
+	for _, domain := range p.Domains(traefik.Domain) {
+		origins = append(origins, origin("http", domain, traefik.Port, 80), origin("https", domain, traefik.SSLPort, 443))
+	}
+	return origins
+}
+
+// DevServerHMRProtocol returns the websocket protocol the browser uses for hot module replacement.
+// Projects are served over https, where a plain ws connection is blocked as mixed content, so
+// it is wss unless devserver.hmr_protocol asks for ws. Traefik redirecting to https overrides ws.
+func (p *ProjectConfig) DevServerHMRProtocol(traefik TraefikConfig) string {
+	if p.DevServer.HMRProtocol == "ws" && !traefik.HTTPSRedirect {
+		return "ws"
+	}
+	return "wss"
+}
+
+// DevServerHMRPort returns the host port the browser connects to for hot module replacement
+func (p *ProjectConfig) DevServerHMRPort(traefik TraefikConfig) int {
+	if p.DevServerHMRProtocol(traefik) == "wss" {
+		return traefik.SSLPort
+	}
+	return traefik.Port
+}
+
+func origin(scheme, host string, port, defaultPort int) string {
+	if port == defaultPort || port == 0 {
+		return scheme + "://" + host
+	}
+	return fmt.Sprintf("%s://%s:%d", scheme, host, port)
+}
+
+// ResourcesConfig limits the CPU, memory and processes a container may use. Empty values
+// leave the runtime's defaults, which don't limit anything.
+type ResourcesConfig struct {
+	CPUs         string               `mapstructure:"cpus" yaml:"cpus,omitempty"`     // CPU limit, e.g. "1.5"
+	Memory       string               `mapstructure:"memory" yaml:"memory,omitempty"` // Memory limit, e.g. 512m or 2g
+	PIDs         int                  `mapstructure:"pids" yaml:"pids,omitempty"`     // Process limit
+	Reservations ResourceReservations `mapstructure:"reservations" yaml:"reservations,omitempty"`
+}
+
+// ResourceReservations are the CPU and memory a container is guaranteed when the host is busy
+type ResourceReservations struct {
+	CPUs   string `mapstructure:"cpus" yaml:"cpus,omitempty"`
+	Memory string `mapstructure:"memory" yaml:"memory,omitempty"`
+}
+
+var memoryPattern = regexp.MustCompile(`^(?i)(\d+(?:\.\d+)?)([kmg]?)b?$`)
+
+// parseMemory converts a compose memory value such as 512m or 1.5g to bytes
+func parseMemory(value string) (float64, bool) {
+	match := memoryPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, false
+	}
+	bytes, _ := strconv.ParseFloat(match[1], 64)
+	switch strings.ToLower(match[2]) {
+	case "k":
+		bytes *= 1 << 10
+	case "m":
+		bytes *= 1 << 20
+	case "g":
+		bytes *= 1 << 30
+	}
+	return bytes, bytes > 0
+}
+
+// parseCPUs converts a compose cpus value to a number of CPUs
+func parseCPUs(value string) (float64, bool) {
+	cpus, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return cpus, err == nil && cpus > 0
+}
+
+// Validate checks the values compose would reject; field names the settings in error messages
+func (r ResourcesConfig) Validate(field string) error {
+	cpus, cpusOK := parseCPUs(r.CPUs)
+	if r.CPUs != "" && !cpusOK {
+		return fmt.Errorf("invalid %s.cpus %q: use a number of CPUs such as 0.5 or 2", field, r.CPUs)
+	}
+	memory, memoryOK := parseMemory(r.Memory)
+	if r.Memory != "" && !memoryOK {
+		return fmt.Errorf("invalid %s.memory %q: use a size such as 512m or 2g", field, r.Memory)
+	}
+	if r.PIDs < 0 {
+		return fmt.Errorf("invalid %s.pids %d: must not be negative", field, r.PIDs)
+	}
+
+	reservedCPUs, ok := parseCPUs(r.Reservations.CPUs)
+	if r.Reservations.CPUs != "" && !ok {
+		return fmt.Errorf("invalid %s.reservations.cpus %q: use a number of CPUs such as 0.5 or 2", field, r.Reservations.CPUs)
+	}
+	if ok && cpusOK && reservedCPUs > cpus {
+		return fmt.Errorf("%s.reservations.cpus (%s) exceeds %s.cpus (%s)", field, r.Reservations.CPUs, field, r.CPUs)
+	}
+	reservedMemory, ok := parseMemory(r.Reservations.Memory)
+	if r.Reservations.Memory != "" && !ok {
+		return fmt.Errorf("invalid %s.reservations.memory %q: use a size such as 512m or 2g", field, r.Reservations.Memory)
+	}
+	if ok && memoryOK && reservedMemory > memory {
+		return fmt.Errorf("%s.reservations.memory (%s) exceeds %s.memory (%s)", field, r.Reservations.Memory, field, r.Memory)
+	}
+	return nil
+}
+
+// MiddlewaresConfig attaches Traefik middlewares to the project's routers.
+// They are chained in field order: IP allowlist, basic auth, rate limit, built-ins, headers.
+type MiddlewaresConfig struct {
+	IPAllowList []string          `mapstructure:"ip_allowlist" yaml:"ip_allowlist,omitempty"` // Allowed client IPs or CIDR ranges
+	BasicAuth   BasicAuthUsers    `mapstructure:"basic_auth" yaml:"basic_auth,omitempty"`     // User -> password; phpier stores bcrypt hashes
+	RateLimit   *RateLimitConfig  `mapstructure:"rate_limit" yaml:"rate_limit,omitempty"`     // Per-project request rate limit
+	Use         []string          `mapstructure:"use" yaml:"use,omitempty"`                   // Built-in middlewares (see BuiltinMiddlewares)
+	Headers     map[string]string `mapstructure:"headers" yaml:"headers,omitempty"`           // Custom response headers
+}
+
+// BasicAuthUsers maps basic auth user names to passwords or password hashes.
+// In .phpier.yml the values are written with $ doubled, so Docker Compose doesn't
+// try to interpolate the hashes.
+type BasicAuthUsers map[string]string
+
+// MarshalYAML escapes $ in the values for Docker Compose
+func (u BasicAuthUsers) MarshalYAML() (interface{}, error) {
+	escaped := make(map[string]string, len(u))
+	for user, password := range u {
+		escaped[user] = strings.ReplaceAll(password, "$", "$$")
+	}
+	return escaped, nil
+}
+
+// UnmarshalYAML reverses the escaping applied by MarshalYAML
+func (u *BasicAuthUsers) UnmarshalYAML(value *yaml.Node) error {
+	var raw map[string]string
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	*u = make(BasicAuthUsers, len(raw))
+	for user, password := range raw {
+		(*u)[user] = strings.ReplaceAll(password, "$$", "$")
+	}
+	return nil
+}
+
+// Hash replaces plaintext passwords with bcrypt hashes.
+// Values that already are htpasswd hashes (bcrypt, APR1 MD5 or SHA1) are kept as they are.
+func (u BasicAuthUsers) Hash() error {
+	for user, password := range u {
+		if strings.Contains(user, ":") {
+			return fmt.Errorf("invalid basic auth user %q: user names can't contain ':'", user)
+		}
+		if IsPasswordHash(password) {
+			continue
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return fmt.Errorf("failed to hash password for basic auth user %q: %w", user, err)
+		}
+		u[user] = string(hash)
+	}
+	return nil
+}
+
+// IsPasswordHash reports whether value is an htpasswd hash rather than a plaintext password
+func IsPasswordHash(value string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$", "$apr1$", "{SHA}"} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimitConfig limits the average requests per second with an allowed burst
+type RateLimitConfig struct {
+	Average int `mapstructure:"average" yaml:"average"`
+	Burst   int `mapstructure:"burst" yaml:"burst"`
+}
+
+// BuiltinMiddlewares lists the middlewares defined in the global Traefik dynamic configuration
+var BuiltinMiddlewares = []string{"security-headers", "dev-headers", "rate-limit", "cors"}
+
+// MiddlewareChain returns the Traefik middleware names applied to the project's routers, in order
+func (p *ProjectConfig) MiddlewareChain() []string {
+	var chain []string
+	m := p.Middlewares
+
+	if len(m.IPAllowList) > 0 {
+		chain = append(chain, p.Name+"-ipallowlist")
+	}
+	if len(m.BasicAuth) > 0 {
+		chain = append(chain, p.Name+"-auth")
+	}
+	if m.RateLimit != nil {
+		chain = append(chain, p.Name+"-ratelimit")
+	}
+	for _, name := range m.Use {
+		chain = append(chain, name+"@file")
+	}
+	if len(m.Headers) > 0 {
+		chain = append(chain, p.Name+"-headers")
+	}
+
+	return chain
+}
+
+// ValidateMiddlewares checks that referenced built-in middlewares exist
+func (p *ProjectConfig) ValidateMiddlewares() error {
+	for _, name := range p.Middlewares.Use {
+		known := false
+		for _, builtin := range BuiltinMiddlewares {
+			if name == builtin {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown middleware %q in middlewares.use (available: %s)", name, strings.Join(BuiltinMiddlewares, ", "))
+		}
+	}
+	if rl := p.Middlewares.RateLimit; rl != nil && rl.Average <= 0 {
+		return fmt.Errorf("middlewares.rate_limit.average must be greater than zero")
+	}
+	return nil
 }
 
 // ServicesConfig contains global service configurations
 type ServicesConfig struct {
 	Database  DatabaseConfig  `mapstructure:"database"`  // Legacy single database config
-	Databases DatabasesConfig `mapstructure:"databases"` // New multi-database config
+	Databases DatabasesConfig `mapstructure:"databases"` // Named database instances
 	Cache     CacheConfig     `mapstructure:"cache"`
 	Tools     ToolsConfig     `mapstructure:"tools"`
 }
@@ -57,21 +567,21 @@ type DatabaseConfig struct {
 	Port    int    `mapstructure:"port"`
 }
 
-// DatabasesConfig contains multiple database service configurations
-type DatabasesConfig struct {
-	MySQL      DatabaseServiceConfig `mapstructure:"mysql"`
-	PostgreSQL DatabaseServiceConfig `mapstructure:"postgresql"`
-	MariaDB    DatabaseServiceConfig `mapstructure:"mariadb"`
-}
+// DatabasesConfig lists the named database instances of the global stack. Each runs in its
+// own phpier-<name> container, so several versions of an engine can run side by side.
+type DatabasesConfig []DatabaseServiceConfig
 
-// DatabaseServiceConfig contains individual database service configuration
+// DatabaseServiceConfig is a named database instance
 type DatabaseServiceConfig struct {
-	Enabled  bool   `mapstructure:"enabled"`
-	Version  string `mapstructure:"version"`
-	Port     int    `mapstructure:"port"`
-	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
-	Database string `mapstructure:"database"`
+	Name      string          `mapstructure:"name"`   // Compose service and host name on the shared network
+	Engine    string          `mapstructure:"engine"` // mysql, postgresql or mariadb
+	Enabled   bool            `mapstructure:"enabled"`
+	Version   string          `mapstructure:"version"`
+	Port      int             `mapstructure:"port"`
+	Username  string          `mapstructure:"username"`
+	Password  string          `mapstructure:"password"`
+	Database  string          `mapstructure:"database"`
+	Resources ResourcesConfig `mapstructure:"resources"`
 }
 
 // CacheConfig contains global cache service configuration
@@ -82,14 +592,18 @@ type CacheConfig struct {
 
 // CacheServiceConfig contains individual cache service config
 type CacheServiceConfig struct {
-	Enabled bool `mapstructure:"enabled"`
-	Port    int  `mapstructure:"port"`
+	Enabled   bool            `mapstructure:"enabled"`
+	Port      int             `mapstructure:"port"`
+	Resources ResourcesConfig `mapstructure:"resources"`
 }
 
 // MailpitConfig contains Mailpit configuration
 type MailpitConfig struct {
-	Enabled bool `mapstructure:"enabled"`
-	Port    int  `mapstructure:"port"`
+	Enabled   bool            `mapstructure:"enabled"`
+	Port      int             `mapstructure:"port"`      // Container SMTP port (legacy; not published)
+	SMTPPort  int             `mapstructure:"smtp_port"` // Host port for SMTP
+	UIPort    int             `mapstructure:"ui_port"`   // Host port for the web UI
+	Resources ResourcesConfig `mapstructure:"resources"`
 }
 
 // ToolsConfig contains global development tools configuration
@@ -101,9 +615,92 @@ type ToolsConfig struct {
 
 // TraefikConfig contains global Traefik configuration
 type TraefikConfig struct {
-	Domain  string `mapstructure:"domain"`
-	Port    int    `mapstructure:"port"`
-	SSLPort int    `mapstructure:"ssl_port"`
+	Version       string          `mapstructure:"version"` // Image tag, e.g. v2.10 or v3.1
+	Domain        string          `mapstructure:"domain"`
+	Port          int             `mapstructure:"port"`
+	SSLPort       int             `mapstructure:"ssl_port"`
+	Dashboard     bool            `mapstructure:"dashboard"`      // Serve the dashboard and API
+	DashboardPort int             `mapstructure:"dashboard_port"` // Host port of the Traefik dashboard and API
+	DashboardAuth BasicAuthUsers  `mapstructure:"dashboard_auth"` // User -> password or htpasswd hash protecting the dashboard
+	HTTPSRedirect bool            `mapstructure:"https_redirect"`
+	TCPMode       string          `mapstructure:"tcp_mode"` // How databases and Redis are reached from the host: direct or proxy
+	LogLevel      string          `mapstructure:"log_level"`
+	AccessLog     AccessLogConfig `mapstructure:"access_log"`
+	Metrics       bool            `mapstructure:"metrics"` // Expose Prometheus metrics on the dashboard port
+	Resources     ResourcesConfig `mapstructure:"resources"`
+}
+
+// AccessLogConfig contains the Traefik access log configuration
+type AccessLogConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Format  string `mapstructure:"format"` // common or json
+	Path    string `mapstructure:"path"`   // Host file to write to; empty writes to the container output
+}
+
+// TraefikLogLevels contains the log levels Traefik accepts
+var TraefikLogLevels = []string{"DEBUG", "INFO", "WARN", "ERROR", "FATAL", "PANIC"}
+
+// MajorVersion returns the Traefik major version of the configured image tag
+func (t TraefikConfig) MajorVersion() int {
+	version := strings.TrimPrefix(t.Version, "v")
+	if strings.HasPrefix(version, "3") {
+		return 3
+	}
+	return 2
+}
+
+// V3 reports whether the configured Traefik image is v3, which renamed some labels
+func (t TraefikConfig) V3() bool {
+	return t.MajorVersion() == 3
+}
+
+// ExposesAPIPort reports whether the dashboard port is published, for the dashboard or for metrics
+func (t TraefikConfig) ExposesAPIPort() bool {
+	return t.Dashboard || t.Metrics
+}
+
+// Validate checks the Traefik settings that would otherwise only fail when Traefik starts
+func (t TraefikConfig) Validate() error {
+	version := strings.TrimPrefix(t.Version, "v")
+	if !strings.HasPrefix(version, "2") && !strings.HasPrefix(version, "3") {
+		return fmt.Errorf("unsupported traefik.version %q: use a v2 or v3 image tag", t.Version)
+	}
+
+	validLevel := false
+	for _, level := range TraefikLogLevels {
+		if strings.EqualFold(t.LogLevel, level) {
+			validLevel = true
+			break
+		}
+	}
+	if !validLevel {
+		return fmt.Errorf("invalid traefik.log_level %q (available: %s)", t.LogLevel, strings.Join(TraefikLogLevels, ", "))
+	}
+
+	if t.AccessLog.Format != "common" && t.AccessLog.Format != "json" {
+		return fmt.Errorf("invalid traefik.access_log.format %q: use common or json", t.AccessLog.Format)
+	}
+	return nil
+}
+
+// DNSConfig contains the built-in DNS responder configuration
+type DNSConfig struct {
+	Enabled   bool            `mapstructure:"enabled"`  // Run the responder as a global service container
+	Port      int             `mapstructure:"port"`     // Host port the responder listens on (UDP)
+	IP        string          `mapstructure:"ip"`       // Address returned for *.<domain>: an IP or "lan"
+	Upstream  string          `mapstructure:"upstream"` // Resolver for other names (host:port); empty uses the system resolver
+	Resources ResourcesConfig `mapstructure:"resources"`
+}
+
+// TCP modes for reaching databases and Redis from the host
+const (
+	TCPModeDirect = "direct" // Each service publishes its own host port
+	TCPModeProxy  = "proxy"  // Traefik publishes the ports and routes to healthy services over TCP entrypoints
+)
+
+// TCPProxy reports whether databases and Redis are reached through Traefik TCP entrypoints
+func (t TraefikConfig) TCPProxy() bool {
+	return t.TCPMode == TCPModeProxy
 }
 
 // PHPVersions contains supported PHP versions
@@ -112,27 +709,94 @@ var PHPVersions = []string{"5.6", "7.2", "7.3", "7.4", "8.0", "8.1", "8.2", "8.3
 // DatabaseTypes contains supported database types
 var DatabaseTypes = []string{"mysql", "postgresql", "mariadb"}
 
-// LoadProjectConfig loads the project-specific configuration from .phpier.yml labels
+// LoadProjectConfig loads the project-specific configuration from .phpier.yml
 func LoadProjectConfig() (*ProjectConfig, error) {
-	// Read .phpier.yml to extract project information from labels
 	return LoadProjectConfigFromDockerCompose(".phpier.yml")
 }
 
-// LoadProjectConfigFromDockerCompose loads project config from .phpier.yml file
+// LoadProjectConfigFromDockerCompose loads project config from the x-phpier block of a .phpier.yml file.
+// Projects created before the block existed fall back to defaults derived from the directory name.
 func LoadProjectConfigFromDockerCompose(path string) (*ProjectConfig, error) {
-	// For now, return a basic config based on current directory and defaults
-	// This will be enhanced to read from .phpier.yml labels
-	projectName := GetCurrentDir()
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	defaults := CreateProjectConfig(filepath.Base(filepath.Dir(absPath)), "", "")
 
-	return &ProjectConfig{
-		Name: projectName,
-		PHP:  "8.3", // Default, will be read from docker-compose.yml labels
-		Node: "lts", // Default, will be read from docker-compose.yml labels
-		App: AppConfig{
-			Volumes:     []string{"./:/var/www/html"},
-			Environment: []string{"APP_ENV=local", "APP_DEBUG=true"},
-		},
-	}, nil
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return defaults, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	file := projectConfigFile{Phpier: defaults}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if file.Phpier == nil {
+		return defaults, nil
+	}
+
+	return file.Phpier, nil
+}
+
+// SaveProjectConfig writes cfg to the x-phpier block of the .phpier.yml at path, leaving the
+// rest of the file, including edits to the compose services, as it is
+func SaveProjectConfig(path string, cfg *ProjectConfig) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var buf strings.Builder
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(cfg); err != nil {
+		return fmt.Errorf("failed to encode project config: %w", err)
+	}
+	var block []string
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		block = append(block, "  "+line+"\n")
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.TrimRight(line, "\r\n") == "x-phpier:" {
+			start = i
+			break
+		}
+	}
+
+	var updated []string
+	if start < 0 {
+		// Projects created before the block existed get it at the end
+		updated = append(updated, lines...)
+		if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+			updated = append(updated, "\n")
+		}
+		updated = append(updated, "\n# phpier project settings (ignored by Docker Compose)\nx-phpier:\n")
+		updated = append(updated, block...)
+	} else {
+		// The block runs until the next line that isn't indented, less the blank lines before it
+		end := start + 1
+		for end < len(lines) && (strings.TrimSpace(lines[end]) == "" || strings.HasPrefix(lines[end], " ") || strings.HasPrefix(lines[end], "\t")) {
+			end++
+		}
+		for end > start+1 && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+		updated = append(updated, lines[:start+1]...)
+		updated = append(updated, block...)
+		updated = append(updated, lines[end:]...)
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(updated, "")), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
 }
 
 // LoadGlobalConfig loads the global configuration from ~/.phpier/config.yaml
@@ -162,24 +826,121 @@ func LoadGlobalConfig() (*GlobalConfig, error) {
 			return nil, fmt.Errorf("failed to read global config: %w", err)
 		}
 	}
+	convertedDatabases := migrateDatabaseInstances(globalViper)
 
 	var config GlobalConfig
 	if err := globalViper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("unable to decode global config: %w", err)
 	}
+	// Messages go to stderr, so they don't end up in the output of e.g. 'db export > dump.sql.gz'
+	if convertedDatabases {
+		if err := SaveGlobalConfig(&config); err != nil {
+			logrus.Warnf("Failed to save the database instances: %v", err)
+		} else {
+			logrus.Info("✓ Converted the database services to named instances (services.databases)")
+		}
+	}
 
 	// Migrate legacy configuration if needed
 	if migrated := migrateFromLegacyConfig(&config); migrated {
 		// Save the migrated configuration
 		if err := SaveGlobalConfig(&config); err != nil {
 			// Log warning but don't fail - user can still use legacy config
-			fmt.Printf("Warning: Failed to save migrated configuration: %v\n", err)
+			logrus.Warnf("Failed to save migrated configuration: %v", err)
 		}
 	}
 
 	return &config, nil
 }
 
+// Domains returns the host names the project is served on under the global domain
+func (p *ProjectConfig) Domains(domain string) []string {
+	return []string{p.Name + "." + domain}
+}
+
+// InternalHost returns the stable host name of the project's app on the shared network
+func (p *ProjectConfig) InternalHost() string {
+	return p.Name + ".internal"
+}
+
+// NetworkAliases returns the names the project's app answers to on the shared network:
+// <name>.internal and its public domains, so server-side calls to the public domain from
+// other containers reach the app instead of the caller's loopback
+func (p *ProjectConfig) NetworkAliases(domain string) []string {
+	return append([]string{p.InternalHost()}, p.Domains(domain)...)
+}
+
+// RuntimePreference returns the configured container runtime, overridden by PHPIER_RUNTIME
+func (g *GlobalConfig) RuntimePreference() string {
+	if env := os.Getenv("PHPIER_RUNTIME"); env != "" {
+		return env
+	}
+	if g.Runtime == "" {
+		return "auto"
+	}
+	return g.Runtime
+}
+
+// RuntimePreference returns the container runtime from PHPIER_RUNTIME or the global config file,
+// without creating or migrating the file
+func RuntimePreference() string {
+	return readGlobalConfig().RuntimePreference()
+}
+
+// Timeouts returns the operation timeouts from the global config file, without creating or
+// migrating the file. PHPIER_COMMAND_TIMEOUT replaces the limits of the up, down and build
+// operations.
+func Timeouts() TimeoutsConfig {
+	timeouts := readGlobalConfig().Timeouts
+	if env := os.Getenv("PHPIER_COMMAND_TIMEOUT"); env != "" {
+		if override, err := time.ParseDuration(env); err == nil {
+			timeouts = timeouts.WithCommandTimeout(override)
+		}
+	}
+	return timeouts
+}
+
+// WithCommandTimeout returns the timeouts with the up, down and build limits replaced by
+// timeout. A zero timeout leaves them unchanged.
+func (t TimeoutsConfig) WithCommandTimeout(timeout time.Duration) TimeoutsConfig {
+	if timeout > 0 {
+		t.Up, t.Down, t.Build = timeout, timeout, timeout
+	}
+	return t
+}
+
+// readGlobalConfig reads ~/.phpier/config.yaml over the defaults, falling back to the defaults
+// alone when the file is missing or unreadable
+func readGlobalConfig() *GlobalConfig {
+	v := viper.New()
+	setGlobalDefaults(v)
+	if home, err := os.UserHomeDir(); err == nil {
+		v.SetConfigFile(filepath.Join(home, ".phpier", "config.yaml"))
+		v.ReadInConfig()
+	}
+	migrateDatabaseInstances(v)
+
+	var config GlobalConfig
+	v.Unmarshal(&config)
+	return &config
+}
+
+// ToolHosts returns the host names the global tools (Traefik dashboard, Mailpit, Adminer) are served on
+func (g *GlobalConfig) ToolHosts() []string {
+	domain := g.Traefik.Domain
+	return []string{
+		"phpier-traefik." + domain,
+		"phpier-mailpit." + domain,
+		"phpier-adminer." + domain,
+		"traefik." + domain,
+	}
+}
+
+// DockerNetwork returns the Docker name of the shared network created by the global stack
+func (g *GlobalConfig) DockerNetwork() string {
+	return "phpier_" + g.Network
+}
+
 // CreateProjectConfig creates a project configuration from CLI arguments
 func CreateProjectConfig(name, phpVersion, nodeVersion string) *ProjectConfig {
 	// Set defaults if not provided
@@ -220,9 +981,14 @@ func SaveGlobalConfig(config *GlobalConfig) error {
 	globalViper.SetConfigType("yaml")
 	globalViper.AddConfigPath(configPath)
 
-	globalViper.Set("services", config.Services)
-	globalViper.Set("traefik", config.Traefik)
+	// Nested structs are stored by their mapstructure keys so they read back the same way
+	globalViper.Set("services", configMap(config.Services))
+	globalViper.Set("traefik", configMap(config.Traefik))
+	globalViper.Set("dns", configMap(config.DNS))
+	globalViper.Set("timeouts", configMap(config.Timeouts))
+	globalViper.Set("logging", configMap(config.Logging))
 	globalViper.Set("network", config.Network)
+	globalViper.Set("runtime", config.Runtime)
 
 	if err := globalViper.SafeWriteConfig(); err != nil {
 		// If file exists, use WriteConfig to overwrite
@@ -233,38 +999,71 @@ func SaveGlobalConfig(config *GlobalConfig) error {
 	return nil
 }
 
+// configMap converts a config struct into a map keyed by its mapstructure tags
+func configMap(value interface{}) map[string]interface{} {
+	v := reflect.ValueOf(value)
+	t := v.Type()
+	result := make(map[string]interface{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("mapstructure"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			result[key] = configMap(field.Interface())
+		} else if field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Struct {
+			items := make([]map[string]interface{}, field.Len())
+			for j := range items {
+				items[j] = configMap(field.Index(j).Interface())
+			}
+			result[key] = items
+		} else {
+			result[key] = field.Interface()
+		}
+	}
+	return result
+}
+
 func setGlobalDefaults(v *viper.Viper) {
 	v.SetDefault("network", "phpier_global")
+	v.SetDefault("runtime", "auto")
+	v.SetDefault("timeouts.api", 30*time.Second)
+	v.SetDefault("timeouts.up", 15*time.Minute)
+	v.SetDefault("timeouts.down", 3*time.Minute)
+	v.SetDefault("timeouts.build", time.Duration(0))
+	v.SetDefault("logging.max_size", "10m")
+	v.SetDefault("logging.max_files", 3)
+	v.SetDefault("traefik.version", "v2.10")
 	v.SetDefault("traefik.domain", "localhost")
 	v.SetDefault("traefik.port", 80)
 	v.SetDefault("traefik.ssl_port", 443)
+	v.SetDefault("traefik.dashboard", true)
+	v.SetDefault("traefik.dashboard_port", 8080)
+	v.SetDefault("traefik.https_redirect", false)
+	v.SetDefault("traefik.tcp_mode", TCPModeDirect)
+	v.SetDefault("traefik.log_level", "INFO")
+	v.SetDefault("traefik.access_log.enabled", true)
+	v.SetDefault("traefik.access_log.format", "common")
+	v.SetDefault("traefik.access_log.path", "")
+	v.SetDefault("traefik.metrics", true)
+
+	v.SetDefault("dns.enabled", false)
+	v.SetDefault("dns.port", 5354)
+	v.SetDefault("dns.ip", "127.0.0.1")
+	v.SetDefault("dns.upstream", "")
 
 	// Legacy single database config (for backwards compatibility)
 	v.SetDefault("services.database.type", "mysql")
 	v.SetDefault("services.database.version", "8.0")
 	v.SetDefault("services.database.port", 3306)
 
-	// New multi-database config
-	v.SetDefault("services.databases.mysql.enabled", true)
-	v.SetDefault("services.databases.mysql.version", "8.0")
-	v.SetDefault("services.databases.mysql.port", 3306)
-	v.SetDefault("services.databases.mysql.username", "phpier")
-	v.SetDefault("services.databases.mysql.password", "phpier")
-	v.SetDefault("services.databases.mysql.database", "phpier")
-
-	v.SetDefault("services.databases.postgresql.enabled", false)
-	v.SetDefault("services.databases.postgresql.version", "15")
-	v.SetDefault("services.databases.postgresql.port", 5432)
-	v.SetDefault("services.databases.postgresql.username", "phpier")
-	v.SetDefault("services.databases.postgresql.password", "phpier")
-	v.SetDefault("services.databases.postgresql.database", "phpier")
-
-	v.SetDefault("services.databases.mariadb.enabled", false)
-	v.SetDefault("services.databases.mariadb.version", "10.11")
-	v.SetDefault("services.databases.mariadb.port", 3307)
-	v.SetDefault("services.databases.mariadb.username", "phpier")
-	v.SetDefault("services.databases.mariadb.password", "phpier")
-	v.SetDefault("services.databases.mariadb.database", "phpier")
+	// Named database instances
+	var databases []map[string]interface{}
+	for _, instance := range DefaultDatabases() {
+		databases = append(databases, configMap(instance))
+	}
+	v.SetDefault("services.databases", databases)
 
 	v.SetDefault("services.cache.redis.enabled", true)
 	v.SetDefault("services.cache.redis.port", 6379)
@@ -274,9 +1073,29 @@ func setGlobalDefaults(v *viper.Viper) {
 	v.SetDefault("services.tools.phpmyadmin", true)
 	v.SetDefault("services.tools.mailpit.enabled", true)
 	v.SetDefault("services.tools.mailpit.port", 1025)
+	v.SetDefault("services.tools.mailpit.smtp_port", 1026)
+	v.SetDefault("services.tools.mailpit.ui_port", 8026)
 	v.SetDefault("services.tools.pgadmin", false)
 }
 
+// BaseImagesDir returns the directory holding shared base image build contexts (~/.phpier/images)
+func BaseImagesDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".phpier", "images"), nil
+}
+
+// SnapshotsDir returns the directory holding the database snapshots (~/.phpier/snapshots)
+func SnapshotsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".phpier", "snapshots"), nil
+}
+
 // GetCurrentDir returns the current directory name for domain generation
 func GetCurrentDir() string {
 	pwd, err := os.Getwd()
@@ -296,67 +1115,293 @@ func IsValidPHPVersion(version string) bool {
 	return false
 }
 
-// GetEnabledDatabases returns a list of enabled database services
-func (c *GlobalConfig) GetEnabledDatabases() map[string]DatabaseServiceConfig {
-	enabled := make(map[string]DatabaseServiceConfig)
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
 
-	if c.Services.Databases.MySQL.Enabled {
-		enabled["mysql"] = c.Services.Databases.MySQL
+// ValidateResources checks the resource settings of every global service
+func (c *GlobalConfig) ValidateResources() error {
+	services := []struct {
+		field     string
+		resources ResourcesConfig
+	}{
+		{"traefik.resources", c.Traefik.Resources},
+		{"dns.resources", c.DNS.Resources},
+		{"services.cache.redis.resources", c.Services.Cache.Redis.Resources},
+		{"services.tools.mailpit.resources", c.Services.Tools.Mailpit.Resources},
 	}
-	if c.Services.Databases.PostgreSQL.Enabled {
-		enabled["postgresql"] = c.Services.Databases.PostgreSQL
+	for _, instance := range c.Services.Databases {
+		services = append(services, struct {
+			field     string
+			resources ResourcesConfig
+		}{fmt.Sprintf("services.databases[%s].resources", instance.Name), instance.Resources})
 	}
-	if c.Services.Databases.MariaDB.Enabled {
-		enabled["mariadb"] = c.Services.Databases.MariaDB
+	for _, service := range services {
+		if err := service.resources.Validate(service.field); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	return enabled
+// databaseInstanceNamePattern matches instance names, which become compose service, container,
+// volume and host names
+var databaseInstanceNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,30}$`)
+
+// reservedInstanceNames are the other services of the global stack and Traefik's entrypoints
+var reservedInstanceNames = []string{"traefik", "redis", "memcached", "mailpit", "adminer", "dns", "web", "websecure", "ping"}
+
+// NewDatabaseInstance returns an instance of engine with the default version, port and
+// credentials of the engine
+func NewDatabaseInstance(name, engine string) DatabaseServiceConfig {
+	instance := DatabaseServiceConfig{Name: name, Engine: engine, Username: "phpier", Password: "phpier", Database: "phpier"}
+	switch engine {
+	case "postgresql":
+		instance.Version, instance.Port = "15", 5432
+	case "mariadb":
+		instance.Version, instance.Port = "10.11", 3307
+	default:
+		instance.Version, instance.Port = "8.0", 3306
+	}
+	return instance
+}
+
+// DefaultDatabases returns the instances of a new configuration: MySQL enabled, PostgreSQL and
+// MariaDB disabled, named after their engine's service
+func DefaultDatabases() DatabasesConfig {
+	var databases DatabasesConfig
+	for _, engine := range DatabaseTypes {
+		instance := NewDatabaseInstance(DatabaseServiceName(engine), engine)
+		instance.Enabled = engine == "mysql"
+		databases = append(databases, instance)
+	}
+	return databases
+}
+
+// ContainerName returns the name of the instance's container
+func (d DatabaseServiceConfig) ContainerName() string {
+	return "phpier-" + d.Name
+}
+
+// Image returns the image the instance runs, e.g. "mysql:5.7"
+func (d DatabaseServiceConfig) Image() string {
+	return DatabaseServiceName(d.Engine) + ":" + d.Version
+}
+
+// ServerCommand returns the command line options of the instance's server. MySQL before 8.4
+// gets mysql_native_password as its default authentication plugin, which desktop clients
+// handle better; 8.4 removed the option, and MariaDB uses that plugin already.
+func (d DatabaseServiceConfig) ServerCommand() string {
+	if d.Engine != "mysql" {
+		return ""
+	}
+	parts := strings.SplitN(d.Version, ".", 3)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "" // latest, lts and the like are 8.4 or newer
+	}
+	minor := -1
+	if len(parts) > 1 {
+		if minor, err = strconv.Atoi(parts[1]); err != nil {
+			minor = -1
+		}
+	}
+	// A bare "8" tag is the newest 8.x, which is 8.4 or later
+	if major < 8 || major == 8 && minor >= 0 && minor < 4 {
+		return "--default-authentication-plugin=mysql_native_password"
+	}
+	return ""
+}
+
+// ContainerPort returns the port the instance listens on in its container
+func (d DatabaseServiceConfig) ContainerPort() int {
+	if d.Engine == "postgresql" {
+		return 5432
+	}
+	return 3306
 }
 
-// GetDatabaseService returns the configuration for a specific database service
-func (c *GlobalConfig) GetDatabaseService(dbType string) (DatabaseServiceConfig, bool) {
-	switch dbType {
-	case "mysql":
-		return c.Services.Databases.MySQL, true
+// Label returns how messages name the instance, e.g. "MySQL 5.7 (legacy)"
+func (d DatabaseServiceConfig) Label() string {
+	label := DatabaseEngineLabel(d.Engine) + " " + d.Version
+	if d.Name != DatabaseServiceName(d.Engine) {
+		label += " (" + d.Name + ")"
+	}
+	return label
+}
+
+// DatabaseEngineLabel returns how messages name a database engine
+func DatabaseEngineLabel(engine string) string {
+	switch engine {
 	case "postgresql":
-		return c.Services.Databases.PostgreSQL, true
+		return "PostgreSQL"
 	case "mariadb":
-		return c.Services.Databases.MariaDB, true
+		return "MariaDB"
 	default:
+		return "MySQL"
+	}
+}
+
+// Get returns the instance with the given name
+func (d DatabasesConfig) Get(name string) (DatabaseServiceConfig, bool) {
+	if i := d.index(name); i >= 0 {
+		return d[i], true
+	}
+	return DatabaseServiceConfig{}, false
+}
+
+func (d DatabasesConfig) index(name string) int {
+	for i, instance := range d {
+		if instance.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Validate checks the names and engines of the instances, and that no two enabled ones
+// publish the same port
+func (d DatabasesConfig) Validate() error {
+	seen := make(map[string]bool)
+	ports := make(map[int]string)
+	for _, instance := range d {
+		if err := ValidateDatabaseInstanceName(instance.Name); err != nil {
+			return err
+		}
+		if seen[instance.Name] {
+			return fmt.Errorf("database instance %q is defined more than once", instance.Name)
+		}
+		seen[instance.Name] = true
+		if !containsString(DatabaseTypes, instance.Engine) {
+			return fmt.Errorf("database instance %q has invalid engine %q (available: %s)", instance.Name, instance.Engine, strings.Join(DatabaseTypes, ", "))
+		}
+		if !instance.Enabled {
+			continue
+		}
+		if other, taken := ports[instance.Port]; taken {
+			return fmt.Errorf("database instances %q and %q both use port %d", other, instance.Name, instance.Port)
+		}
+		ports[instance.Port] = instance.Name
+	}
+	return nil
+}
+
+// ValidateDatabaseInstanceName checks that name can be used for a new database instance
+func ValidateDatabaseInstanceName(name string) error {
+	if !databaseInstanceNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid database instance name %q: use up to 31 lowercase letters, digits and dashes, starting with a letter", name)
+	}
+	if containsString(reservedInstanceNames, name) {
+		return fmt.Errorf("database instance name %q is used by another global service", name)
+	}
+	return nil
+}
+
+// GetEnabledDatabases returns the enabled database instances, in the order they are configured
+func (c *GlobalConfig) GetEnabledDatabases() []DatabaseServiceConfig {
+	var enabled []DatabaseServiceConfig
+	for _, instance := range c.Services.Databases {
+		if instance.Enabled {
+			enabled = append(enabled, instance)
+		}
+	}
+	return enabled
+}
+
+// GetDatabaseService returns a database instance by its name or, for configurations and
+// projects that name an engine, the first (preferably enabled) instance of that engine
+func (c *GlobalConfig) GetDatabaseService(name string) (DatabaseServiceConfig, bool) {
+	if instance, ok := c.Services.Databases.Get(name); ok {
+		return instance, true
+	}
+	var found *DatabaseServiceConfig
+	for i, instance := range c.Services.Databases {
+		if instance.Engine == name && (found == nil || instance.Enabled && !found.Enabled) {
+			found = &c.Services.Databases[i]
+		}
+	}
+	if found == nil {
 		return DatabaseServiceConfig{}, false
 	}
+	return *found, true
 }
 
-// IsDatabaseEnabled checks if a specific database service is enabled
-func (c *GlobalConfig) IsDatabaseEnabled(dbType string) bool {
-	config, exists := c.GetDatabaseService(dbType)
+// IsDatabaseEnabled checks if a database instance, or an instance of an engine, is enabled
+func (c *GlobalConfig) IsDatabaseEnabled(name string) bool {
+	config, exists := c.GetDatabaseService(name)
 	return exists && config.Enabled
 }
 
+// SetDatabaseService replaces the instance of the same name, or adds it
+func (c *GlobalConfig) SetDatabaseService(instance DatabaseServiceConfig) {
+	if i := c.Services.Databases.index(instance.Name); i >= 0 {
+		c.Services.Databases[i] = instance
+		return
+	}
+	c.Services.Databases = append(c.Services.Databases, instance)
+}
+
+// RemoveDatabaseService removes the instance with the given name
+func (c *GlobalConfig) RemoveDatabaseService(name string) bool {
+	i := c.Services.Databases.index(name)
+	if i < 0 {
+		return false
+	}
+	c.Services.Databases = append(c.Services.Databases[:i:i], c.Services.Databases[i+1:]...)
+	return true
+}
+
+// migrateDatabaseInstances converts the services.databases of configurations written before
+// named instances, a map with one fixed entry per engine, into the list of instances. Each
+// engine becomes an instance named after its service (mysql, postgres, mariadb), so the
+// containers and data volumes stay the same. It reports whether there was anything to convert.
+func migrateDatabaseInstances(v *viper.Viper) bool {
+	legacy, ok := v.Get("services.databases").(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	var instances []map[string]interface{}
+	for _, engine := range DatabaseTypes {
+		instance := NewDatabaseInstance(DatabaseServiceName(engine), engine)
+		instance.Enabled = engine == "mysql"
+		values := configMap(instance)
+		if settings, ok := legacy[engine].(map[string]interface{}); ok {
+			for key, value := range settings {
+				values[strings.ToLower(key)] = value
+			}
+		}
+		values["name"], values["engine"] = instance.Name, engine
+		instances = append(instances, values)
+	}
+	v.Set("services.databases", instances)
+	return true
+}
+
 // migrateFromLegacyConfig migrates legacy single database configuration to new multi-database format
 func migrateFromLegacyConfig(config *GlobalConfig) bool {
 	// Check if we have legacy config and no new config
 	if config.Services.Database.Type != "" && !hasNewDatabaseConfig(config) {
-		fmt.Println("Migrating legacy database configuration to new multi-database format...")
+		logrus.Info("Migrating legacy database configuration to new multi-database format...")
 
 		// Enable the legacy database type in the new format
-		switch config.Services.Database.Type {
-		case "mysql":
-			config.Services.Databases.MySQL.Enabled = true
-			config.Services.Databases.MySQL.Version = config.Services.Database.Version
-			config.Services.Databases.MySQL.Port = config.Services.Database.Port
-		case "postgresql":
-			config.Services.Databases.PostgreSQL.Enabled = true
-			config.Services.Databases.PostgreSQL.Version = config.Services.Database.Version
-			config.Services.Databases.PostgreSQL.Port = config.Services.Database.Port
-		case "mariadb":
-			config.Services.Databases.MariaDB.Enabled = true
-			config.Services.Databases.MariaDB.Version = config.Services.Database.Version
-			config.Services.Databases.MariaDB.Port = config.Services.Database.Port
-		}
-
-		fmt.Printf("✓ Migrated %s database configuration\n", config.Services.Database.Type)
-		fmt.Println("Use 'phpier global db list' to see enabled database services")
+		instance, ok := config.GetDatabaseService(config.Services.Database.Type)
+		if !ok {
+			instance = NewDatabaseInstance(DatabaseServiceName(config.Services.Database.Type), config.Services.Database.Type)
+		}
+		instance.Enabled = true
+		instance.Version = config.Services.Database.Version
+		instance.Port = config.Services.Database.Port
+		config.SetDatabaseService(instance)
+
+		logrus.Infof("✓ Migrated %s database configuration", config.Services.Database.Type)
+		logrus.Info("Use 'phpier db list' to see enabled database services")
 		return true
 	}
 	return false
@@ -364,9 +1409,7 @@ func migrateFromLegacyConfig(config *GlobalConfig) bool {
 
 // hasNewDatabaseConfig checks if the new multi-database configuration is present
 func hasNewDatabaseConfig(config *GlobalConfig) bool {
-	return config.Services.Databases.MySQL.Enabled ||
-		config.Services.Databases.PostgreSQL.Enabled ||
-		config.Services.Databases.MariaDB.Enabled
+	return len(config.GetEnabledDatabases()) > 0
 }
 
 // ProjectInfo contains information about a discovered phpier project
@@ -375,6 +1418,32 @@ type ProjectInfo struct {
 	Path string
 }
 
+// DiscoverAllProjects combines Docker and filesystem discovery, de-duplicated by project name
+// and sorted. Docker results take precedence unless they lack a path.
+func DiscoverAllProjects() []ProjectInfo {
+	byName := make(map[string]ProjectInfo)
+
+	if dockerProjects, err := DiscoverProjectsFromDocker(); err == nil {
+		for _, project := range dockerProjects {
+			byName[project.Name] = project
+		}
+	}
+	if filesystemProjects, err := DiscoverProjectsFromFilesystem(); err == nil {
+		for _, project := range filesystemProjects {
+			if existing, exists := byName[project.Name]; !exists || existing.Path == "" {
+				byName[project.Name] = project
+			}
+		}
+	}
+
+	projects := make([]ProjectInfo, 0, len(byName))
+	for _, project := range byName {
+		projects = append(projects, project)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects
+}
+
 // FindProjectByName searches for a phpier project by name using Docker and filesystem discovery
 func FindProjectByName(projectName string) (*ProjectInfo, error) {
 	// Get projects from both sources
@@ -442,73 +1511,82 @@ func findProjectInList(projectName string, projects []ProjectInfo) (*ProjectInfo
 
 // DiscoverProjectsFromDocker discovers phpier projects by scanning Docker images with phpier- prefix
 func DiscoverProjectsFromDocker() ([]ProjectInfo, error) {
-	// Check if Docker is available
-	if _, err := exec.LookPath("docker"); err != nil {
-		return []ProjectInfo{}, nil // Docker not available, return empty list
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	r, err := runtime.Detect(RuntimePreference())
+	if err != nil {
+		return []ProjectInfo{}, nil
+	}
+	client, err := r.Engine()
+	if err != nil || client.Ping(ctx) != nil {
+		return []ProjectInfo{}, nil // Runtime not available, return empty list
 	}
 
 	// Get all phpier- prefixed images
-	cmd := exec.Command("docker", "images", "--filter", "reference=phpier-*", "--format", "{{.Repository}}:{{.Tag}}")
-	output, err := cmd.Output()
+	images, err := client.ImageList(ctx, dockerapi.Filters{"reference": {"phpier-*"}})
 	if err != nil {
-		return []ProjectInfo{}, nil // Docker command failed, return empty list
+		return []ProjectInfo{}, nil // Docker request failed, return empty list
 	}
 
-	if strings.TrimSpace(string(output)) == "" {
-		return []ProjectInfo{}, nil
+	// One container listing provides the working directories for every image
+	containers, err := client.ContainerList(ctx, dockerapi.ContainerListOptions{All: true})
+	if err != nil {
+		containers = nil
 	}
+	return projectsFromImages(images, containers), nil
+}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	projectMap := make(map[string]ProjectInfo)
-
-	for _, line := range lines {
-		imageName := strings.TrimSpace(line)
-		if imageName == "" || !strings.HasPrefix(imageName, "phpier-") {
+// projectsFromImages maps phpier-<project>:<tag> images to projects, taking each project's working
+// directory from a container built from the image or from its compose project label
+func projectsFromImages(images []dockerapi.Image, containers []dockerapi.Container) []ProjectInfo {
+	dirByImage := make(map[string]string)
+	dirByProject := make(map[string]string)
+	for _, container := range containers {
+		workingDir := container.Labels["com.docker.compose.working-dir"]
+		if workingDir == "" {
 			continue
 		}
-
-		// Extract project name from image name (remove phpier- prefix and :tag)
-		projectName := strings.TrimPrefix(imageName, "phpier-")
-		if colonIndex := strings.Index(projectName, ":"); colonIndex != -1 {
-			projectName = projectName[:colonIndex]
+		if _, exists := dirByImage[container.Image]; !exists {
+			dirByImage[container.Image] = workingDir
 		}
-
-		if projectName == "" {
-			continue
+		if project := container.Labels["com.docker.compose.project"]; project != "" {
+			if _, exists := dirByProject[project]; !exists {
+				dirByProject[project] = workingDir
+			}
 		}
+	}
 
-		// Check if this project has containers and get working directory
-		workingDir := ""
+	projectMap := make(map[string]ProjectInfo)
 
-		// Try to get container info for this project
-		containerCmd := exec.Command("docker", "ps", "-a",
-			"--filter", fmt.Sprintf("ancestor=%s", imageName),
-			"--format", "{{.Status}}\t{{.Label \"com.docker.compose.working-dir\"}}")
+	for _, image := range images {
+		for _, imageName := range image.RepoTags {
+			if !strings.HasPrefix(imageName, "phpier-") || strings.HasPrefix(imageName, "phpier-base:") {
+				continue
+			}
 
-		if containerOutput, err := containerCmd.Output(); err == nil {
-			containerInfo := strings.TrimSpace(string(containerOutput))
-			if containerInfo != "" {
-				parts := strings.Split(containerInfo, "\t")
-				if len(parts) > 1 {
-					workingDir = strings.TrimSpace(parts[1])
-				}
+			// Extract project name from image name (remove phpier- prefix and :tag)
+			projectName := strings.TrimPrefix(imageName, "phpier-")
+			if colonIndex := strings.Index(projectName, ":"); colonIndex != -1 {
+				projectName = projectName[:colonIndex]
 			}
-		}
 
-		// Try to find working directory from Docker Compose project label if not found
-		if workingDir == "" {
-			projectCmd := exec.Command("docker", "ps", "-a",
-				"--filter", fmt.Sprintf("label=com.docker.compose.project=%s", projectName),
-				"--format", "{{.Label \"com.docker.compose.working-dir\"}}")
+			if projectName == "" {
+				continue
+			}
 
-			if projectOutput, err := projectCmd.Output(); err == nil {
-				workingDir = strings.TrimSpace(string(projectOutput))
+			workingDir := dirByImage[imageName]
+			if workingDir == "" {
+				workingDir = dirByProject[projectName]
+			}
+			if existing, exists := projectMap[projectName]; exists && existing.Path != "" {
+				continue
 			}
-		}
 
-		projectMap[projectName] = ProjectInfo{
-			Name: projectName,
-			Path: workingDir,
+			projectMap[projectName] = ProjectInfo{
+				Name: projectName,
+				Path: workingDir,
+			}
 		}
 	}
 
@@ -518,7 +1596,7 @@ func DiscoverProjectsFromDocker() ([]ProjectInfo, error) {
 		result = append(result, project)
 	}
 
-	return result, nil
+	return result
 }
 
 // DiscoverProjectsFromFilesystem scans the filesystem for phpier projects
@@ -629,17 +1707,5 @@ func extractProjectInfo(projectPath string) (*ProjectInfo, error) {
 
 // LoadProjectConfigFromPath loads project configuration from a specific path
 func LoadProjectConfigFromPath(projectPath string) (*ProjectConfig, error) {
-	// For now, return a basic config based on directory name
-	// This will be enhanced to read from .phpier.yml labels when that feature is implemented
-	projectName := filepath.Base(projectPath)
-
-	return &ProjectConfig{
-		Name: projectName,
-		PHP:  "8.3", // Default, will be read from docker-compose.yml labels
-		Node: "lts", // Default, will be read from docker-compose.yml labels
-		App: AppConfig{
-			Volumes:     []string{"./:/var/www/html"},
-			Environment: []string{"APP_ENV=local", "APP_DEBUG=true"},
-		},
-	}, nil
+	return LoadProjectConfigFromDockerCompose(filepath.Join(projectPath, ".phpier.yml"))
 }
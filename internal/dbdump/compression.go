@@ -0,0 +1,244 @@
+// Package dbdump compresses and decompresses the database dumps 'phpier db export' and
+// 'phpier db import' stream in and out of the database containers, and stores the snapshots
+// of 'phpier db snapshot'.
+package dbdump
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+)
+
+// Compression is how a dump is compressed
+type Compression string
+
+// Supported compressions
+const (
+	None Compression = "none"
+	Gzip Compression = "gzip"
+	Zstd Compression = "zstd"
+)
+
+// zstdCommand is the host command zstd dumps are compressed and decompressed with; Go's
+// standard library has no zstd
+var zstdCommand = "zstd"
+
+// Magic numbers dumps are recognized by
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// CompressionFromPath returns the compression a file name asks for: .gz, .zst or none
+func CompressionFromPath(path string) Compression {
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		return Gzip
+	case strings.HasSuffix(path, ".zst"), strings.HasSuffix(path, ".zstd"):
+		return Zstd
+	default:
+		return None
+	}
+}
+
+// ParseCompression parses a --compress value: none (or sql), gzip (or gz) or zstd (or zst)
+func ParseCompression(value string) (Compression, error) {
+	switch strings.ToLower(value) {
+	case "none", "sql":
+		return None, nil
+	case "gzip", "gz":
+		return Gzip, nil
+	case "zstd", "zst":
+		return Zstd, nil
+	}
+	return "", fmt.Errorf("invalid compression %q: use none, gzip or zstd", value)
+}
+
+// Compress returns a writer that compresses what is written to it into w. Closing it flushes
+// the compressed stream; it doesn't close w.
+func Compress(w io.Writer, compression Compression) (io.WriteCloser, error) {
+	switch compression {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		cmd, err := zstd("-q", "-c")
+		if err != nil {
+			return nil, err
+		}
+		cmd.Stdout = w
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		return &commandWriter{WriteCloser: stdin, cmd: cmd}, nil
+	default:
+		return nopWriteCloser{w}, nil
+	}
+}
+
+// Decompress returns a reader of the SQL in r, recognizing gzip and zstd streams by their
+// magic number, and the compression it found. Closing it doesn't close r.
+func Decompress(r io.Reader) (io.ReadCloser, Compression, error) {
+	buffered := bufio.NewReaderSize(r, 64*1024)
+	magic, err := buffered.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, "", err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		reader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read the gzip stream: %w", err)
+		}
+		return reader, Gzip, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		cmd, err := zstd("-d", "-q", "-c")
+		if err != nil {
+			return nil, "", err
+		}
+		cmd.Stdin = buffered
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, "", err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, "", err
+		}
+		return &commandReader{ReadCloser: stdout, cmd: cmd}, Zstd, nil
+	default:
+		return io.NopCloser(buffered), None, nil
+	}
+}
+
+// zstd returns the zstd command with args, or an error when it isn't installed
+func zstd(args ...string) (*exec.Cmd, error) {
+	path, err := exec.LookPath(zstdCommand)
+	if err != nil {
+		return nil, fmt.Errorf("zstd dumps need the zstd command on the host: install it, or use gzip")
+	}
+	cmd := exec.Command(path, args...)
+	cmd.Stderr = &limitedBuffer{limit: 4096}
+	return cmd, nil
+}
+
+// commandWriter feeds a command's stdin; Close waits for the command to finish writing
+type commandWriter struct {
+	io.WriteCloser
+	cmd *exec.Cmd
+}
+
+func (w *commandWriter) Close() error {
+	w.WriteCloser.Close()
+	return commandError(w.cmd, w.cmd.Wait())
+}
+
+// commandReader reads a command's stdout; Close waits for the command to exit
+type commandReader struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (r *commandReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF {
+		// A corrupt stream only shows in the exit status
+		if waitErr := commandError(r.cmd, r.cmd.Wait()); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+func (r *commandReader) Close() error {
+	r.ReadCloser.Close()
+	if r.cmd.ProcessState == nil {
+		r.cmd.Wait()
+	}
+	return nil
+}
+
+// commandError adds what the command wrote to stderr to the error it exited with
+func commandError(cmd *exec.Cmd, err error) error {
+	if err == nil {
+		return nil
+	}
+	if stderr, ok := cmd.Stderr.(*limitedBuffer); ok && stderr.Len() > 0 {
+		return fmt.Errorf("%s: %w: %s", cmd.Path, err, strings.TrimSpace(stderr.String()))
+	}
+	return fmt.Errorf("%s: %w", cmd.Path, err)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// limitedBuffer keeps the first limit bytes written to it
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// Counter counts the bytes read or written through it. It is safe to read the count while
+// the transfer runs.
+type Counter struct {
+	n int64
+}
+
+// Count returns the bytes counted so far
+func (c *Counter) Count() int64 {
+	return atomic.LoadInt64(&c.n)
+}
+
+// Reader returns r, counting what is read from it
+func (c *Counter) Reader(r io.Reader) io.Reader {
+	return countingReader{r: r, counter: c}
+}
+
+// Writer returns w, counting what is written to it
+func (c *Counter) Writer(w io.Writer) io.Writer {
+	return countingWriter{w: w, counter: c}
+}
+
+type countingReader struct {
+	r       io.Reader
+	counter *Counter
+}
+
+func (r countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	atomic.AddInt64(&r.counter.n, int64(n))
+	return n, err
+}
+
+type countingWriter struct {
+	w       io.Writer
+	counter *Counter
+}
+
+func (w countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	atomic.AddInt64(&w.counter.n, int64(n))
+	return n, err
+}
@@ -0,0 +1,90 @@
+package dbdump
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dump = "CREATE TABLE users (id INT);\nINSERT INTO users VALUES (1);\n"
+
+func TestCompressionFromPath(t *testing.T) {
+	assert.Equal(t, Gzip, CompressionFromPath("backups/shop.sql.gz"))
+	assert.Equal(t, Zstd, CompressionFromPath("shop.sql.zst"))
+	assert.Equal(t, None, CompressionFromPath("shop.sql"))
+}
+
+func TestParseCompression(t *testing.T) {
+	for value, want := range map[string]Compression{"none": None, "sql": None, "GZIP": Gzip, "gz": Gzip, "zst": Zstd} {
+		got, err := ParseCompression(value)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, value)
+	}
+	_, err := ParseCompression("bzip2")
+	assert.Error(t, err)
+}
+
+// roundTrip compresses the dump and reads it back, checking the compression is recognized
+func roundTrip(t *testing.T, compression Compression) {
+	var compressed bytes.Buffer
+	writer, err := Compress(&compressed, compression)
+	require.NoError(t, err)
+	_, err = io.WriteString(writer, dump)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	if compression != None {
+		assert.NotEqual(t, dump, compressed.String())
+	}
+
+	reader, detected, err := Decompress(&compressed)
+	require.NoError(t, err)
+	defer reader.Close()
+	assert.Equal(t, compression, detected)
+	sql, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, dump, string(sql))
+}
+
+func TestRoundTrip(t *testing.T) {
+	roundTrip(t, None)
+	roundTrip(t, Gzip)
+}
+
+func TestRoundTrip_Zstd(t *testing.T) {
+	if _, err := exec.LookPath(zstdCommand); err != nil {
+		t.Skip("zstd is not installed")
+	}
+	roundTrip(t, Zstd)
+}
+
+func TestZstd_NotInstalled(t *testing.T) {
+	zstdCommand = "phpier-no-such-zstd"
+	defer func() { zstdCommand = "zstd" }()
+
+	_, err := Compress(io.Discard, Zstd)
+	assert.ErrorContains(t, err, "install it")
+	_, _, err = Decompress(bytes.NewReader(append(zstdMagic, 0, 0)))
+	assert.ErrorContains(t, err, "install it")
+}
+
+func TestDecompress_Empty(t *testing.T) {
+	reader, compression, err := Decompress(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Equal(t, None, compression)
+	sql, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Empty(t, sql)
+}
+
+func TestCounter(t *testing.T) {
+	var read, written Counter
+	_, err := io.Copy(written.Writer(io.Discard), read.Reader(strings.NewReader(dump)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(dump)), read.Count())
+	assert.Equal(t, int64(len(dump)), written.Count())
+}
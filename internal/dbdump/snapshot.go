@@ -0,0 +1,182 @@
+package dbdump
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Snapshot is a saved dump of a database, which 'phpier db snapshot restore' resets it to
+type Snapshot struct {
+	Name     string    `json:"name"`
+	Engine   string    `json:"engine"`
+	Instance string    `json:"instance,omitempty"`
+	Version  string    `json:"version"`
+	Database string    `json:"database"`
+	Project  string    `json:"project,omitempty"`
+	Size     int64     `json:"size"`     // Size of the compressed dump file
+	SQLSize  int64     `json:"sql_size"` // Size of the SQL it holds
+	Created  time.Time `json:"created"`
+}
+
+// ErrSnapshotNotFound is returned for snapshots that don't exist
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// ValidateSnapshotName checks that a snapshot name is usable as a file name
+func ValidateSnapshotName(name string) error {
+	if !snapshotNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q: use up to 64 letters, digits, '.', '_' and '-', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// MajorVersion returns the release series of a database server version that dumps can be moved
+// between: major.minor for MySQL and MariaDB, whose minor versions change the data and dump
+// formats ("8.0" for "8.0.36", "10.11" for "10.11.6"), and the major version for PostgreSQL ("16"
+// for "16.2"), which numbered majors with two components before 10 ("9.6" for "9.6.24")
+func MajorVersion(engine, version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	if engine == "postgresql" {
+		if major, err := strconv.Atoi(parts[0]); err != nil || major >= 10 {
+			return parts[0]
+		}
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// CheckCompatible returns an error when the snapshot can't be restored on a server of engine
+// and version: dumps aren't portable between engines, nor reliably between major versions
+func (s Snapshot) CheckCompatible(engine, version string) error {
+	if s.Engine != engine {
+		return fmt.Errorf("snapshot %q was taken on %s, not %s", s.Name, s.Engine, engine)
+	}
+	if MajorVersion(engine, s.Version) != MajorVersion(engine, version) {
+		return fmt.Errorf("snapshot %q was taken on %s %s, but the server runs %s", s.Name, s.Engine, s.Version, version)
+	}
+	return nil
+}
+
+// SnapshotStore keeps snapshots in a directory, each as a gzip compressed dump and a JSON file
+// with its metadata
+type SnapshotStore struct {
+	Dir string
+}
+
+// DumpPath returns the path of a snapshot's dump
+func (s SnapshotStore) DumpPath(name string) string {
+	return filepath.Join(s.Dir, name+".sql.gz")
+}
+
+func (s SnapshotStore) metadataPath(name string) string {
+	return filepath.Join(s.Dir, name+".json")
+}
+
+// Get returns the metadata of a snapshot, or ErrSnapshotNotFound
+func (s SnapshotStore) Get(name string) (Snapshot, error) {
+	data, err := os.ReadFile(s.metadataPath(name))
+	if os.IsNotExist(err) {
+		return Snapshot{}, fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to read the metadata of snapshot %s: %w", name, err)
+	}
+	return snapshot, nil
+}
+
+// List returns the snapshots in the store, oldest first
+func (s SnapshotStore) List() ([]Snapshot, error) {
+	paths, err := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []Snapshot
+	for _, path := range paths {
+		snapshot, err := s.Get(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+	return snapshots, nil
+}
+
+// Save stores a snapshot, whose gzip compressed dump write writes, returning the size of the
+// SQL in it. The dump is written to a temporary file first, so a failed save leaves an existing
+// snapshot of the same name intact. It returns the snapshot with its sizes and creation time set.
+func (s SnapshotStore) Save(snapshot Snapshot, write func(w io.Writer) (int64, error)) (Snapshot, error) {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return Snapshot{}, err
+	}
+	file, err := os.CreateTemp(s.Dir, "."+snapshot.Name+"-*.sql.gz")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer os.Remove(file.Name())
+
+	snapshot.SQLSize, err = write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	info, err := os.Stat(file.Name())
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snapshot.Size = info.Size()
+	snapshot.Created = time.Now()
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if err := os.Rename(file.Name(), s.DumpPath(snapshot.Name)); err != nil {
+		return Snapshot{}, err
+	}
+	if err := os.WriteFile(s.metadataPath(snapshot.Name), append(data, '\n'), 0644); err != nil {
+		return Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// Open opens the dump of a snapshot, or returns ErrSnapshotNotFound
+func (s SnapshotStore) Open(name string) (*os.File, error) {
+	file, err := os.Open(s.DumpPath(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+	return file, err
+}
+
+// Delete removes a snapshot, or returns ErrSnapshotNotFound
+func (s SnapshotStore) Delete(name string) error {
+	if _, err := s.Get(name); err != nil {
+		return err
+	}
+	if err := os.Remove(s.DumpPath(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Remove(s.metadataPath(name))
+}
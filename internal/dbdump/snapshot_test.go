@@ -0,0 +1,85 @@
+package dbdump
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSnapshotName(t *testing.T) {
+	for _, name := range []string{"before-migration", "v1.2", "2024_01_01"} {
+		assert.NoError(t, ValidateSnapshotName(name), name)
+	}
+	for _, name := range []string{"", "../etc", ".hidden", "with space", "a/b"} {
+		assert.Error(t, ValidateSnapshotName(name), name)
+	}
+}
+
+func TestSnapshotCheckCompatible(t *testing.T) {
+	snapshot := Snapshot{Name: "base", Engine: "mysql", Version: "8.0.36"}
+	assert.NoError(t, snapshot.CheckCompatible("mysql", "8.0.40"))
+	assert.ErrorContains(t, snapshot.CheckCompatible("mariadb", "8.0.36"), "taken on mysql, not mariadb")
+	assert.ErrorContains(t, snapshot.CheckCompatible("mysql", "8.4.0"), "the server runs 8.4.0")
+	assert.NoError(t, Snapshot{Engine: "mysql", Version: "9.1.0"}.CheckCompatible("mysql", "9.1.2"))
+	assert.Error(t, Snapshot{Engine: "mariadb", Version: "10.4.32"}.CheckCompatible("mariadb", "10.11.6"))
+	assert.NoError(t, Snapshot{Engine: "postgresql", Version: "16.2"}.CheckCompatible("postgresql", "16.4"))
+
+	assert.Equal(t, "16", MajorVersion("postgresql", "16.2"))
+	assert.Equal(t, "9.6", MajorVersion("postgresql", "9.6.24"))
+	assert.Equal(t, "5.7", MajorVersion("mysql", "5.7.44"))
+	assert.Equal(t, "9.1", MajorVersion("mysql", "9.1.0"))
+	assert.Equal(t, "10.11", MajorVersion("mariadb", "10.11.6"))
+}
+
+func TestSnapshotStore(t *testing.T) {
+	store := SnapshotStore{Dir: t.TempDir()}
+
+	_, err := store.Get("base")
+	assert.True(t, errors.Is(err, ErrSnapshotNotFound))
+
+	saved, err := store.Save(Snapshot{Name: "base", Engine: "mysql", Version: "8.0.36", Database: "shop", Project: "shop"},
+		func(w io.Writer) (int64, error) {
+			_, err := io.WriteString(w, dump)
+			return 100, err
+		})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(dump)), saved.Size)
+	assert.Equal(t, int64(100), saved.SQLSize)
+	assert.False(t, saved.Created.IsZero())
+
+	got, err := store.Get("base")
+	require.NoError(t, err)
+	assert.Equal(t, saved.Database, got.Database)
+	assert.Equal(t, saved.SQLSize, got.SQLSize)
+	assert.True(t, saved.Created.Equal(got.Created))
+
+	// A failed save keeps the snapshot it would have replaced
+	_, err = store.Save(Snapshot{Name: "base"}, func(w io.Writer) (int64, error) {
+		io.WriteString(w, "partial")
+		return 0, errors.New("dump failed")
+	})
+	assert.EqualError(t, err, "dump failed")
+	file, err := store.Open("base")
+	require.NoError(t, err)
+	content, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(t, dump, string(content))
+
+	snapshots, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, "base", snapshots[0].Name)
+
+	require.NoError(t, store.Delete("base"))
+	_, err = os.Stat(store.DumpPath("base"))
+	assert.True(t, os.IsNotExist(err))
+	assert.True(t, errors.Is(store.Delete("base"), ErrSnapshotNotFound))
+
+	entries, err := os.ReadDir(store.Dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,61 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"phpier/internal/dbdump"
+
+	"github.com/fatih/color"
+)
+
+// RenderTransfer renders the progress of a database export or import, e.g.
+// "Importing shop: 12.3MiB of 45.6MiB (27%)". total is 0 when the size isn't known.
+func RenderTransfer(verb, database string, done, total int64) string {
+	progress := formatBytes(uint64(done))
+	if total > 0 {
+		percent := done * 100 / total
+		if percent > 100 {
+			percent = 100
+		}
+		progress = fmt.Sprintf("%s of %s (%d%%)", progress, formatBytes(uint64(total)), percent)
+	}
+	return fmt.Sprintf("%s %s: %s", verb, database, progress)
+}
+
+// RenderTransferSummary renders what an export or import moved: the size of the SQL and, when
+// it was compressed, of the compressed stream
+func RenderTransferSummary(verb, database string, sqlBytes, streamBytes int64, compressed bool, elapsed time.Duration, options TableOptions) string {
+	summary := fmt.Sprintf("✓ %s %s: %s of SQL", verb, database, formatBytes(uint64(sqlBytes)))
+	if compressed {
+		summary += fmt.Sprintf(", %s compressed", formatBytes(uint64(streamBytes)))
+	}
+	summary += fmt.Sprintf(" in %s", elapsed.Round(100*time.Millisecond))
+	return colorize(summary, color.FgGreen, options.ColorOutput)
+}
+
+const snapshotRowFormat = "%-24s  %-10s  %-8s  %-16s  %-16s  %9s  %s"
+
+// RenderSnapshots renders the database snapshots as a table
+func RenderSnapshots(snapshots []dbdump.Snapshot, options TableOptions) string {
+	var output strings.Builder
+	header := fmt.Sprintf(snapshotRowFormat, "NAME", "ENGINE", "VERSION", "DATABASE", "PROJECT", "SIZE", "CREATED")
+	output.WriteString(colorize(header, color.FgCyan, options.ColorOutput))
+
+	for _, snapshot := range snapshots {
+		project := snapshot.Project
+		if project == "" {
+			project = "-"
+		}
+		output.WriteString("\n" + fmt.Sprintf(snapshotRowFormat,
+			truncateString(snapshot.Name, 24),
+			snapshot.Engine,
+			truncateString(snapshot.Version, 8),
+			truncateString(snapshot.Database, 16),
+			truncateString(project, 16),
+			formatBytes(uint64(snapshot.Size)),
+			snapshot.Created.Local().Format("2006-01-02 15:04")))
+	}
+	return output.String()
+}
@@ -0,0 +1,42 @@
+package display
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"phpier/internal/dbdump"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTransfer(t *testing.T) {
+	assert.Equal(t, "Exporting shop: 1.50MiB", RenderTransfer("Exporting", "shop", 1536*1024, 0))
+	assert.Equal(t, "Importing shop: 512.0KiB of 2.00MiB (25%)", RenderTransfer("Importing", "shop", 512*1024, 2*1024*1024))
+	assert.Equal(t, "Importing shop: 3.00MiB of 2.00MiB (100%)", RenderTransfer("Importing", "shop", 3*1024*1024, 2*1024*1024))
+}
+
+func TestRenderTransferSummary(t *testing.T) {
+	assert.Equal(t, "✓ Exported shop: 10.0MiB of SQL, 2.00MiB compressed in 1.5s",
+		RenderTransferSummary("Exported", "shop", 10*1024*1024, 2*1024*1024, true, 1520*time.Millisecond, TableOptions{}))
+	assert.Equal(t, "✓ Imported shop: 100B of SQL in 0s",
+		RenderTransferSummary("Imported", "shop", 100, 100, false, 20*time.Millisecond, TableOptions{}))
+}
+
+func TestRenderSnapshots(t *testing.T) {
+	created := time.Date(2024, 5, 1, 9, 30, 0, 0, time.Local)
+	output := RenderSnapshots([]dbdump.Snapshot{
+		{Name: "before-migration", Engine: "mysql", Version: "8.0.36", Database: "shop", Project: "shop", Size: 2048, Created: created},
+		{Name: "reports", Engine: "postgresql", Version: "16.2", Database: "reports", Size: 100, Created: created},
+	}, TableOptions{})
+
+	lines := strings.Split(output, "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "NAME"))
+	assert.Contains(t, lines[1], "before-migration")
+	assert.Contains(t, lines[1], "2.00KiB")
+	assert.Contains(t, lines[1], "2024-05-01 09:30")
+	assert.Contains(t, lines[2], "postgresql")
+	assert.Contains(t, lines[2], " - ")
+}
@@ -0,0 +1,78 @@
+package display
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"phpier/internal/docker"
+)
+
+// eventRowFormat lays out streamed events; columns are fixed because rows are printed as they arrive
+const eventRowFormat = "%-8s  %-16s  %-12s  %-18s  %s"
+
+// RenderEventsHeader renders the column header printed above streamed events
+func RenderEventsHeader(options TableOptions) string {
+	header := fmt.Sprintf(eventRowFormat, "TIME", "PROJECT", "SERVICE", "EVENT", "DOMAIN")
+	return colorize(header, color.FgCyan, options.ColorOutput)
+}
+
+// RenderEvent renders one container event as a row under RenderEventsHeader
+func RenderEvent(event docker.ContainerEvent, options TableOptions) string {
+	project := event.Project
+	if event.Global() {
+		project = "(global)"
+	}
+
+	// Pad before colorizing so escape codes don't break the column widths
+	description := fmt.Sprintf("%-18s", EventDescription(event))
+	return fmt.Sprintf(eventRowFormat,
+		event.Time.Local().Format("15:04:05"),
+		truncateString(project, 16),
+		truncateString(event.Service, 12),
+		colorize(description, eventColor(event), options.ColorOutput),
+		event.Domain)
+}
+
+// EventDescription describes an event in a few words, e.g. "unhealthy" or "died (exit 137)"
+func EventDescription(event docker.ContainerEvent) string {
+	switch event.Action {
+	case "health_status":
+		return event.Health
+	case "die":
+		if event.ExitCode != "" {
+			return fmt.Sprintf("died (exit %s)", event.ExitCode)
+		}
+		return "died"
+	case "oom":
+		return "out of memory"
+	default:
+		if past, ok := eventPastTense[event.Action]; ok {
+			return past
+		}
+		return event.Action
+	}
+}
+
+var eventPastTense = map[string]string{
+	"create":  "created",
+	"start":   "started",
+	"restart": "restarted",
+	"stop":    "stopped",
+	"kill":    "killed",
+	"pause":   "paused",
+	"unpause": "unpaused",
+	"destroy": "destroyed",
+}
+
+// eventColor highlights events that need attention in red and healthy starts in green
+func eventColor(event docker.ContainerEvent) color.Attribute {
+	switch {
+	case event.Action == "oom", event.Action == "kill", event.Health == "unhealthy",
+		event.Action == "die" && event.ExitCode != "" && event.ExitCode != "0":
+		return color.FgRed
+	case event.Action == "start", event.Health == "healthy":
+		return color.FgGreen
+	default:
+		return color.FgYellow
+	}
+}
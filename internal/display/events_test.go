@@ -0,0 +1,33 @@
+package display
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"phpier/internal/docker"
+)
+
+func TestEventDescription(t *testing.T) {
+	assert.Equal(t, "started", EventDescription(docker.ContainerEvent{Action: "start"}))
+	assert.Equal(t, "stopped", EventDescription(docker.ContainerEvent{Action: "stop"}))
+	assert.Equal(t, "unhealthy", EventDescription(docker.ContainerEvent{Action: "health_status", Health: "unhealthy"}))
+	assert.Equal(t, "died (exit 137)", EventDescription(docker.ContainerEvent{Action: "die", ExitCode: "137"}))
+	assert.Equal(t, "out of memory", EventDescription(docker.ContainerEvent{Action: "oom"}))
+}
+
+func TestRenderEvent(t *testing.T) {
+	event := docker.ContainerEvent{
+		Time:    time.Date(2024, 5, 1, 10, 30, 0, 0, time.Local),
+		Action:  "start",
+		Project: "phpier",
+		Service: "traefik",
+		Domain:  "phpier-traefik.localhost",
+	}
+
+	row := RenderEvent(event, TableOptions{})
+	header := RenderEventsHeader(TableOptions{})
+	assert.True(t, strings.HasPrefix(row, "10:30:00  (global)"))
+	assert.Equal(t, strings.Index(header, "DOMAIN"), strings.Index(row, "phpier-traefik.localhost"), "columns line up")
+}
@@ -0,0 +1,221 @@
+package display
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/fatih/color"
+	"phpier/internal/docker"
+	"phpier/internal/logfiles"
+)
+
+// logPrefixColors are cycled through so each project/service keeps its own prefix color
+var logPrefixColors = []color.Attribute{
+	color.FgCyan,
+	color.FgGreen,
+	color.FgYellow,
+	color.FgBlue,
+	color.FgMagenta,
+	color.FgHiCyan,
+	color.FgHiGreen,
+	color.FgHiYellow,
+	color.FgHiBlue,
+	color.FgHiMagenta,
+}
+
+// LogPrefix returns the project/service prefix of a container's log lines, "global/<service>"
+// for global services
+func LogPrefix(project, service string) string {
+	if project == "phpier" || project == "" {
+		project = "global"
+	}
+	return project + "/" + service
+}
+
+// LogPrefixWidth returns the width that lines up the prefixes of containers
+func LogPrefixWidth(containers []docker.LogContainer) int {
+	width := 0
+	for _, container := range containers {
+		if n := len(LogPrefix(container.Project, container.Service)); n > width {
+			width = n
+		}
+	}
+	return width
+}
+
+// RenderLogLine renders a log line as "<prefix> | <time> <message>", with the prefix padded
+// to width and colored per project/service, and errors in red
+func RenderLogLine(line docker.LogLine, width int, options TableOptions) string {
+	prefix := LogPrefix(line.Project, line.Service)
+	// Pad before colorizing so escape codes don't break the alignment
+	prefix = fmt.Sprintf("%-*s |", width, prefix)
+	prefix = colorize(prefix, logPrefixColor(line.Project, line.Service), options.ColorOutput)
+
+	timestamp := strings.Repeat(" ", len("15:04:05.000"))
+	if !line.Time.IsZero() {
+		timestamp = line.Time.Local().Format("15:04:05.000")
+	}
+
+	message := colorizeLevel(line.Message, line.Level, options)
+	return fmt.Sprintf("%s %s %s", prefix, timestamp, message)
+}
+
+// RenderLogFileLine renders a line of a log file, with errors in red and warnings in yellow
+func RenderLogFileLine(line string, options TableOptions) string {
+	return colorizeLevel(line, docker.DetectLevel(line), options)
+}
+
+// RenderPHPError renders a PHP error log entry as a headline with its stack trace indented below
+func RenderPHPError(entry logfiles.PHPError, options TableOptions) string {
+	var output strings.Builder
+
+	if !entry.Time.IsZero() {
+		output.WriteString(entry.Time.Local().Format("2006-01-02 15:04:05") + " ")
+	}
+	if entry.Level != "" {
+		output.WriteString(colorizeLevel("PHP "+entry.Level+":", entry.Severity(), options) + " ")
+	}
+	output.WriteString(entry.Message)
+	if location := entry.Location(); location != "" {
+		output.WriteString(colorize(" at "+location, color.FgCyan, options.ColorOutput))
+	}
+	for _, trace := range entry.Trace {
+		output.WriteString("\n    " + strings.TrimSpace(trace))
+	}
+	return output.String()
+}
+
+// RenderPHPErrorSummary renders distinct PHP errors with how often and when they occurred
+func RenderPHPErrorSummary(summaries []logfiles.PHPErrorSummary, options TableOptions) string {
+	if len(summaries) == 0 {
+		return colorize("No PHP errors found", color.FgGreen, options.ColorOutput)
+	}
+
+	var output strings.Builder
+	header := fmt.Sprintf("%6s  %-12s  %-19s  %s", "COUNT", "LEVEL", "LAST SEEN", "ERROR")
+	output.WriteString(colorize(header, color.FgCyan, options.ColorOutput) + "\n")
+
+	total := 0
+	for _, summary := range summaries {
+		total += summary.Count
+		entry := logfiles.PHPError{Level: summary.Level, File: summary.File, Line: summary.Line}
+
+		level := summary.Level
+		if level == "" {
+			level = "-"
+		}
+		// Pad before colorizing so escape codes don't break the column widths
+		level = colorizeLevel(fmt.Sprintf("%-12s", truncateString(level, 12)), entry.Severity(), options)
+
+		lastSeen := "-"
+		if !summary.LastSeen.IsZero() {
+			lastSeen = summary.LastSeen.Local().Format("2006-01-02 15:04:05")
+		}
+
+		output.WriteString(fmt.Sprintf("%6d  %s  %-19s  %s\n", summary.Count, level, lastSeen, summary.Message))
+		if location := entry.Location(); location != "" {
+			output.WriteString(fmt.Sprintf("%6s  %-12s  %-19s  %s\n", "", "", "", colorize(location, color.FgCyan, options.ColorOutput)))
+		}
+	}
+
+	output.WriteString(fmt.Sprintf("\n%d distinct errors, %d in total", len(summaries), total))
+	return output.String()
+}
+
+// colorizeLevel shows errors in red and warnings in yellow
+func colorizeLevel(text, level string, options TableOptions) string {
+	switch level {
+	case "error", "critical":
+		return colorize(text, color.FgRed, options.ColorOutput)
+	case "warning":
+		return colorize(text, color.FgYellow, options.ColorOutput)
+	}
+	return text
+}
+
+// logPrefixColor picks a color from the project/service name, so it stays the same between runs
+func logPrefixColor(project, service string) color.Attribute {
+	hash := fnv.New32a()
+	hash.Write([]byte(LogPrefix(project, service)))
+	return logPrefixColors[hash.Sum32()%uint32(len(logPrefixColors))]
+}
+
+// LogUsage is the log disk usage of a project, as reported by 'phpier logs prune'
+type LogUsage struct {
+	Project    string
+	Files      logfiles.PruneResult
+	Containers []docker.ContainerLogUsage
+}
+
+// logUsageRowFormat lays out the log usage columns
+const logUsageRowFormat = "%-20s  %10s  %11s  %14s"
+
+// RenderLogUsage renders the log disk usage of projects and what pruning reclaimed, or would
+// reclaim on a dry run
+func RenderLogUsage(usages []LogUsage, dryRun bool, options TableOptions) string {
+	var output strings.Builder
+
+	reclaimedHeader := "RECLAIMED"
+	if dryRun {
+		reclaimedHeader = "RECLAIMABLE"
+	}
+	header := fmt.Sprintf(logUsageRowFormat, "PROJECT", "LOG FILES", reclaimedHeader, "CONTAINER LOGS")
+	output.WriteString(colorize(header, color.FgCyan, options.ColorOutput) + "\n")
+
+	var reclaimed int64
+	var notes []string
+	containerLogsShown := false
+	for _, usage := range usages {
+		reclaimed += usage.Files.Reclaimed
+
+		containerLogs := "-"
+		var containerSize int64
+		var readable bool
+		var uncapped []string
+		for _, container := range usage.Containers {
+			if container.Size >= 0 {
+				containerSize += container.Size
+				readable = true
+			}
+			if container.MaxSize == "" {
+				uncapped = append(uncapped, container.Container)
+			}
+		}
+		if readable {
+			containerLogs = formatBytes(uint64(containerSize))
+		}
+		if len(usage.Containers) > 0 {
+			containerLogsShown = true
+		}
+
+		output.WriteString(fmt.Sprintf(logUsageRowFormat,
+			truncateString(usage.Project, 20),
+			formatBytes(uint64(usage.Files.Size)),
+			formatBytes(uint64(usage.Files.Reclaimed)),
+			containerLogs) + "\n")
+
+		if len(usage.Files.Skipped) > 0 {
+			notes = append(notes, fmt.Sprintf("%s: couldn't truncate %s (not writable); start the project and prune again to truncate them in the container",
+				usage.Project, strings.Join(usage.Files.Skipped, ", ")))
+		}
+		if len(uncapped) > 0 {
+			notes = append(notes, fmt.Sprintf("%s: the logs of %s aren't capped; 'phpier build --regenerate' and 'phpier up' recreate them with the logging settings",
+				usage.Project, strings.Join(uncapped, ", ")))
+		}
+	}
+
+	summary := fmt.Sprintf("\nReclaimed %s", formatBytes(uint64(reclaimed)))
+	if dryRun {
+		summary = fmt.Sprintf("\nWould reclaim %s (dry run)", formatBytes(uint64(reclaimed)))
+	}
+	output.WriteString(colorize(summary, color.FgGreen, options.ColorOutput))
+	if containerLogsShown {
+		output.WriteString("\nContainer logs are kept by the runtime and not pruned; 'logging' in ~/.phpier/config.yaml caps them")
+	}
+
+	for _, note := range notes {
+		output.WriteString("\n" + colorize("⚠️  "+note, color.FgYellow, options.ColorOutput))
+	}
+	return output.String()
+}
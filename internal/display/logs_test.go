@@ -0,0 +1,92 @@
+package display
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"phpier/internal/docker"
+	"phpier/internal/logfiles"
+)
+
+func TestLogPrefix(t *testing.T) {
+	assert.Equal(t, "shop/app", LogPrefix("shop", "app"))
+	assert.Equal(t, "global/mysql", LogPrefix("phpier", "mysql"))
+
+	width := LogPrefixWidth([]docker.LogContainer{{Project: "shop", Service: "app"}, {Project: "phpier", Service: "mysql"}})
+	assert.Equal(t, len("global/mysql"), width)
+}
+
+func TestRenderLogLine(t *testing.T) {
+	line := docker.LogLine{
+		Time:    time.Date(2024, 5, 1, 10, 30, 0, 250000000, time.Local),
+		Project: "shop",
+		Service: "app",
+		Message: "GET /index.php 200",
+	}
+	row := RenderLogLine(line, len("global/mysql"), TableOptions{})
+	assert.Equal(t, "shop/app     | 10:30:00.250 GET /index.php 200", row)
+
+	// Lines without a timestamp keep the messages aligned
+	line.Time = time.Time{}
+	row = RenderLogLine(line, len("shop/app"), TableOptions{})
+	assert.True(t, strings.HasSuffix(row, "|              GET /index.php 200"))
+}
+
+func TestRenderPHPErrorSummary(t *testing.T) {
+	summaries := []logfiles.PHPErrorSummary{
+		{Level: "Warning", Message: "Undefined variable $total", File: "/var/www/html/cart.php", Line: 41, Count: 2,
+			LastSeen: time.Date(2024, 5, 1, 10, 1, 0, 0, time.Local)},
+	}
+	output := RenderPHPErrorSummary(summaries, TableOptions{})
+	assert.Contains(t, output, "     2  Warning       2024-05-01 10:01:00  Undefined variable $total")
+	assert.Contains(t, output, "/var/www/html/cart.php:41")
+	assert.True(t, strings.HasSuffix(output, "1 distinct errors, 2 in total"))
+
+	assert.Equal(t, "No PHP errors found", RenderPHPErrorSummary(nil, TableOptions{}))
+}
+
+func TestRenderPHPError(t *testing.T) {
+	entry := logfiles.PHPError{
+		Time:    time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local),
+		Level:   "Fatal error",
+		Message: "Uncaught Exception: boom",
+		File:    "/var/www/html/index.php",
+		Line:    3,
+		Trace:   []string{"Stack trace:", "#0 {main}"},
+	}
+	assert.Equal(t, "2024-05-01 10:00:00 PHP Fatal error: Uncaught Exception: boom at /var/www/html/index.php:3\n    Stack trace:\n    #0 {main}",
+		RenderPHPError(entry, TableOptions{}))
+}
+
+func TestRenderLogUsage(t *testing.T) {
+	usages := []LogUsage{
+		{
+			Project: "shop",
+			Files:   logfiles.PruneResult{Size: 3 * 1024 * 1024, Files: 4, Reclaimed: 2 * 1024 * 1024, Skipped: []string{"nginx/error.log"}},
+			Containers: []docker.ContainerLogUsage{
+				{Container: "shop-app", Size: 2048, MaxSize: "10m"},
+				{Container: "shop-worker", Size: -1},
+			},
+		},
+		{Project: "blog", Files: logfiles.PruneResult{}},
+	}
+
+	output := RenderLogUsage(usages, false, TableOptions{})
+	assert.Contains(t, output, "PROJECT                LOG FILES    RECLAIMED  CONTAINER LOGS")
+	assert.Contains(t, output, "shop                     3.00MiB      2.00MiB         2.00KiB")
+	assert.Contains(t, output, "blog                          0B           0B               -")
+	assert.Contains(t, output, "Reclaimed 2.00MiB")
+	assert.Contains(t, output, "shop: couldn't truncate nginx/error.log")
+	assert.Contains(t, output, "the logs of shop-worker aren't capped")
+	assert.NotContains(t, output, "shop-app aren't capped")
+	assert.Contains(t, output, "Container logs are kept by the runtime and not pruned")
+
+	output = RenderLogUsage(usages, true, TableOptions{})
+	assert.Contains(t, output, "RECLAIMABLE")
+	assert.Contains(t, output, "Would reclaim 2.00MiB (dry run)")
+
+	output = RenderLogUsage(usages[1:], false, TableOptions{})
+	assert.NotContains(t, output, "Container logs", "no note without container logs")
+}
@@ -0,0 +1,116 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"phpier/internal/docker"
+)
+
+// statsRowFormat lays out the resource usage columns
+const statsRowFormat = "%-20s  %7s  %-21s  %6s  %-19s  %-19s  %5s"
+
+// RenderStatsTable renders the resource usage of containers, grouped like RenderServicesTable:
+// global services first, then one table per project
+func RenderStatsTable(stats []docker.ContainerStats, options TableOptions) string {
+	if len(stats) == 0 {
+		return colorize("No phpier containers running", color.FgYellow, options.ColorOutput)
+	}
+
+	var global []docker.ContainerStats
+	projects := make(map[string][]docker.ContainerStats)
+	for _, s := range stats {
+		if s.Project == "phpier" || s.Project == "" {
+			global = append(global, s)
+		} else {
+			projects[s.Project] = append(projects[s.Project], s)
+		}
+	}
+
+	var output strings.Builder
+	output.WriteString(colorize("PHPIER RESOURCE USAGE\n\n", color.FgCyan, options.ColorOutput))
+
+	if len(global) > 0 {
+		output.WriteString(colorize("Global Services:\n", color.FgMagenta, options.ColorOutput))
+		output.WriteString(renderStatsGroup(global, options))
+		output.WriteString("\n")
+	}
+
+	// Sorted so the live view doesn't reorder between refreshes
+	names := make([]string, 0, len(projects))
+	for name := range projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		header := fmt.Sprintf("Project Services (%s):\n", name)
+		output.WriteString(colorize(header, color.FgMagenta, options.ColorOutput))
+		output.WriteString(renderStatsGroup(projects[name], options))
+		output.WriteString("\n")
+	}
+
+	return strings.TrimSpace(output.String())
+}
+
+// renderStatsGroup renders the rows of one group under a column header
+func renderStatsGroup(stats []docker.ContainerStats, options TableOptions) string {
+	var output strings.Builder
+
+	if options.ShowHeaders {
+		header := fmt.Sprintf(statsRowFormat, "NAME", "CPU %", "MEM USAGE / LIMIT", "MEM %", "NET I/O", "BLOCK I/O", "PIDS")
+		output.WriteString(colorize(header+"\n", color.FgWhite, options.ColorOutput))
+	}
+
+	for _, s := range stats {
+		name := truncateString(s.Name, 20)
+		if s.Stats == nil {
+			row := fmt.Sprintf(statsRowFormat, name, "-", "-", "-", "-", "-", "-")
+			output.WriteString(strings.TrimRight(row, " ") + "  " + formatStatus(s.State, options.ColorOutput) + "\n")
+			continue
+		}
+
+		st := s.Stats
+		memPercent := "-"
+		if st.MemoryLimit > 0 {
+			memPercent = fmt.Sprintf("%.1f%%", float64(st.MemoryUsage)/float64(st.MemoryLimit)*100)
+		}
+		// Pad before colorizing so escape codes don't break the column widths
+		cpu := fmt.Sprintf("%7s", fmt.Sprintf("%.1f%%", st.CPUPercent))
+		if st.CPUPercent >= 90 {
+			cpu = colorize(cpu, color.FgRed, options.ColorOutput)
+		}
+
+		output.WriteString(fmt.Sprintf(statsRowFormat,
+			name,
+			cpu,
+			formatBytes(st.MemoryUsage)+" / "+formatBytes(st.MemoryLimit),
+			memPercent,
+			formatBytes(st.NetworkRx)+" / "+formatBytes(st.NetworkTx),
+			formatBytes(st.BlockRead)+" / "+formatBytes(st.BlockWrite),
+			fmt.Sprintf("%d", st.PIDs)) + "\n")
+	}
+
+	return output.String()
+}
+
+// formatBytes formats a byte count with binary units, e.g. 512B, 1.50KiB or 256.0MiB
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	value := float64(bytes)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB"}
+	for i, suffix := range suffixes {
+		value /= unit
+		if value < unit || i == len(suffixes)-1 {
+			if value < 10 {
+				return fmt.Sprintf("%.2f%s", value, suffix)
+			}
+			return fmt.Sprintf("%.1f%s", value, suffix)
+		}
+	}
+	return fmt.Sprintf("%dB", bytes)
+}
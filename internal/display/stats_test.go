@@ -0,0 +1,52 @@
+package display
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"phpier/internal/docker"
+	"phpier/internal/dockerapi"
+)
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512B", formatBytes(512))
+	assert.Equal(t, "1.50KiB", formatBytes(1536))
+	assert.Equal(t, "256.0MiB", formatBytes(256<<20))
+	assert.Equal(t, "2.00GiB", formatBytes(2<<30))
+}
+
+func TestRenderStatsTable(t *testing.T) {
+	stats := []docker.ContainerStats{
+		{Name: "shop-app", Project: "shop", Service: "app", State: "running", Stats: &dockerapi.Stats{
+			CPUPercent: 12.5, MemoryUsage: 256 << 20, MemoryLimit: 1 << 30, NetworkRx: 2048, NetworkTx: 1024, PIDs: 12,
+		}},
+		{Name: "blog-app", Project: "blog", Service: "app", State: "exited"},
+		{Name: "phpier-mysql", Project: "phpier", Service: "mysql", State: "running", Stats: &dockerapi.Stats{MemoryUsage: 400 << 20}},
+	}
+
+	output := RenderStatsTable(stats, TableOptions{ShowHeaders: true})
+	global := strings.Index(output, "Global Services:")
+	blog := strings.Index(output, "Project Services (blog):")
+	shop := strings.Index(output, "Project Services (shop):")
+	assert.True(t, global >= 0 && global < blog && blog < shop, "global services first, then projects by name")
+
+	assert.Contains(t, output, "12.5%  256.0MiB / 1.00GiB      25.0%  2.00KiB / 1.00KiB")
+	assert.Regexp(t, `blog-app\s+-.*exited`, output)
+
+	lines := strings.Split(output, "\n")
+	var header, row string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "NAME") {
+			header = line
+		}
+		if strings.HasPrefix(line, "shop-app") {
+			row = line
+		}
+	}
+	assert.Equal(t, len(header), len(row), "columns line up")
+}
+
+func TestRenderStatsTable_Empty(t *testing.T) {
+	assert.Equal(t, "No phpier containers running", RenderStatsTable(nil, TableOptions{}))
+}
@@ -0,0 +1,196 @@
+package dns
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Record types and classes used by the responder
+const (
+	TypeA    uint16 = 1
+	TypeAAAA uint16 = 28
+	TypeANY  uint16 = 255
+	ClassIN  uint16 = 1
+
+	headerLen = 12
+)
+
+// RcodeSuccess is the NOERROR response code
+const RcodeSuccess = 0
+
+// Question is the single question of a DNS query
+type Question struct {
+	Name  string // Lower-case, without trailing dot
+	Type  uint16
+	Class uint16
+	raw   []byte // Wire format of the question section, copied into responses
+}
+
+// parseQuery extracts the ID and the first question from a DNS query packet
+func parseQuery(packet []byte) (uint16, *Question, error) {
+	if len(packet) < headerLen {
+		return 0, nil, fmt.Errorf("packet too short")
+	}
+
+	id := binary.BigEndian.Uint16(packet[0:2])
+	if packet[2]&0x80 != 0 {
+		return id, nil, fmt.Errorf("packet is a response")
+	}
+	if binary.BigEndian.Uint16(packet[4:6]) != 1 {
+		return id, nil, fmt.Errorf("expected exactly one question")
+	}
+
+	var labels []string
+	offset := headerLen
+	for {
+		if offset >= len(packet) {
+			return id, nil, fmt.Errorf("truncated question name")
+		}
+		length := int(packet[offset])
+		offset++
+		if length == 0 {
+			break
+		}
+		if length&0xC0 != 0 {
+			return id, nil, fmt.Errorf("compressed names are not allowed in questions")
+		}
+		if offset+length > len(packet) {
+			return id, nil, fmt.Errorf("truncated question label")
+		}
+		labels = append(labels, string(packet[offset:offset+length]))
+		offset += length
+	}
+
+	if offset+4 > len(packet) {
+		return id, nil, fmt.Errorf("truncated question type")
+	}
+
+	question := &Question{
+		Name:  strings.ToLower(strings.Join(labels, ".")),
+		Type:  binary.BigEndian.Uint16(packet[offset : offset+2]),
+		Class: binary.BigEndian.Uint16(packet[offset+2 : offset+4]),
+		raw:   packet[headerLen : offset+4],
+	}
+	return id, question, nil
+}
+
+// buildResponse creates an authoritative response to query answering with ips
+func buildResponse(query []byte, question *Question, rcode int, ips []net.IP, ttl uint32) []byte {
+	response := make([]byte, headerLen, headerLen+len(question.raw)+len(ips)*28)
+	copy(response[0:2], query[0:2])
+
+	// QR=1, keep opcode and RD from the query, AA=1
+	response[2] = 0x80 | (query[2] & 0x79) | 0x04
+	// RA=1 plus response code
+	response[3] = 0x80 | byte(rcode&0x0F)
+
+	binary.BigEndian.PutUint16(response[4:6], 1)
+	binary.BigEndian.PutUint16(response[6:8], uint16(len(ips)))
+
+	response = append(response, question.raw...)
+
+	for _, ip := range ips {
+		recordType, data := TypeA, ip.To4()
+		if data == nil {
+			recordType, data = TypeAAAA, ip.To16()
+		}
+
+		record := make([]byte, 12+len(data))
+		// Pointer to the question name at offset 12
+		binary.BigEndian.PutUint16(record[0:2], 0xC000|headerLen)
+		binary.BigEndian.PutUint16(record[2:4], recordType)
+		binary.BigEndian.PutUint16(record[4:6], ClassIN)
+		binary.BigEndian.PutUint32(record[6:10], ttl)
+		binary.BigEndian.PutUint16(record[10:12], uint16(len(data)))
+		copy(record[12:], data)
+		response = append(response, record...)
+	}
+
+	return response
+}
+
+// buildQuery creates a recursive query packet for name
+func buildQuery(id uint16, name string, qtype uint16) ([]byte, error) {
+	packet := make([]byte, headerLen)
+	binary.BigEndian.PutUint16(packet[0:2], id)
+	packet[2] = 0x01 // RD
+	binary.BigEndian.PutUint16(packet[4:6], 1)
+
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("invalid name %q", name)
+		}
+		packet = append(packet, byte(len(label)))
+		packet = append(packet, label...)
+	}
+	packet = append(packet, 0)
+
+	tail := make([]byte, 4)
+	binary.BigEndian.PutUint16(tail[0:2], qtype)
+	binary.BigEndian.PutUint16(tail[2:4], ClassIN)
+	return append(packet, tail...), nil
+}
+
+// parseAnswers returns the response code and the A/AAAA addresses in a response packet
+func parseAnswers(packet []byte) (int, []net.IP, error) {
+	if len(packet) < headerLen {
+		return 0, nil, fmt.Errorf("response too short")
+	}
+
+	rcode := int(packet[3] & 0x0F)
+	questions := int(binary.BigEndian.Uint16(packet[4:6]))
+	answers := int(binary.BigEndian.Uint16(packet[6:8]))
+
+	offset := headerLen
+	for i := 0; i < questions; i++ {
+		next, err := skipName(packet, offset)
+		if err != nil {
+			return rcode, nil, err
+		}
+		offset = next + 4
+	}
+
+	var ips []net.IP
+	for i := 0; i < answers; i++ {
+		next, err := skipName(packet, offset)
+		if err != nil {
+			return rcode, nil, err
+		}
+		offset = next
+		if offset+10 > len(packet) {
+			return rcode, nil, fmt.Errorf("truncated answer")
+		}
+		recordType := binary.BigEndian.Uint16(packet[offset : offset+2])
+		length := int(binary.BigEndian.Uint16(packet[offset+8 : offset+10]))
+		offset += 10
+		if offset+length > len(packet) {
+			return rcode, nil, fmt.Errorf("truncated answer data")
+		}
+		if recordType == TypeA || recordType == TypeAAAA {
+			ips = append(ips, net.IP(append([]byte(nil), packet[offset:offset+length]...)))
+		}
+		offset += length
+	}
+
+	return rcode, ips, nil
+}
+
+// skipName returns the offset just past the (possibly compressed) name starting at offset
+func skipName(packet []byte, offset int) (int, error) {
+	for {
+		if offset >= len(packet) {
+			return 0, fmt.Errorf("truncated name")
+		}
+		length := int(packet[offset])
+		switch {
+		case length == 0:
+			return offset + 1, nil
+		case length&0xC0 == 0xC0:
+			return offset + 2, nil
+		default:
+			offset += length + 1
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package dns
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultTTL      = 60
+	upstreamTimeout = 3 * time.Second
+	maxPacketSize   = 4096
+
+	// DefaultUpstream is used when no upstream resolver is configured or detected
+	DefaultUpstream = "1.1.1.1:53"
+)
+
+// Server answers queries for a local development domain and forwards everything else
+type Server struct {
+	Domain   string // e.g. "test"; queries for the domain and *.domain are answered locally
+	IP       net.IP // Address returned for the domain
+	Upstream string // host:port of the resolver used for other names; empty refuses them
+	TTL      uint32
+
+	mu   sync.Mutex
+	conn net.PacketConn
+}
+
+// NewServer creates a DNS responder for domain answering with ip
+func NewServer(domain string, ip net.IP, upstream string) *Server {
+	return &Server{
+		Domain:   strings.ToLower(strings.Trim(domain, ".")),
+		IP:       ip,
+		Upstream: upstream,
+		TTL:      defaultTTL,
+	}
+}
+
+// ListenAndServe listens on the UDP address addr and serves queries until Close is called
+func (s *Server) ListenAndServe(addr string) error {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return s.Serve(conn)
+}
+
+// Serve answers queries received on conn until Close is called
+func (s *Server) Serve(conn net.PacketConn) error {
+	s.mu.Lock()
+	s.conn = conn
+	s.mu.Unlock()
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		packet := append([]byte(nil), buf[:n]...)
+		go func() {
+			response, err := s.Handle(packet)
+			if err != nil {
+				logrus.Debugf("DNS query from %s failed: %v", addr, err)
+			}
+			if response != nil {
+				conn.WriteTo(response, addr)
+			}
+		}()
+	}
+}
+
+// Close stops the server
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+// Handle returns the response for a single query packet
+func (s *Server) Handle(packet []byte) ([]byte, error) {
+	_, question, err := parseQuery(packet)
+	if err != nil {
+		// Queries this responder doesn't understand are still valid for the upstream resolver
+		if len(packet) >= headerLen && packet[2]&0x80 == 0 {
+			return s.forward(packet)
+		}
+		return nil, err
+	}
+
+	if !s.IsLocal(question.Name) {
+		return s.forward(packet)
+	}
+
+	var answers []net.IP
+	isV4 := s.IP.To4() != nil
+	switch question.Type {
+	case TypeA:
+		if isV4 {
+			answers = append(answers, s.IP)
+		}
+	case TypeAAAA:
+		if !isV4 {
+			answers = append(answers, s.IP)
+		}
+	case TypeANY:
+		answers = append(answers, s.IP)
+	}
+
+	logrus.Debugf("DNS %s (type %d) -> %v", question.Name, question.Type, answers)
+	return buildResponse(packet, question, RcodeSuccess, answers, s.TTL), nil
+}
+
+// IsLocal reports whether name is the served domain or one of its subdomains
+func (s *Server) IsLocal(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	return name == s.Domain || strings.HasSuffix(name, "."+s.Domain)
+}
+
+// forward relays a query to the upstream resolver and returns its response unchanged
+func (s *Server) forward(packet []byte) ([]byte, error) {
+	if s.Upstream == "" {
+		return nil, fmt.Errorf("no upstream resolver configured")
+	}
+
+	conn, err := net.DialTimeout("udp", s.Upstream, upstreamTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach upstream %s: %w", s.Upstream, err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(upstreamTimeout))
+	if _, err := conn.Write(packet); err != nil {
+		return nil, fmt.Errorf("failed to forward query: %w", err)
+	}
+
+	buf := make([]byte, maxPacketSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, fmt.Errorf("no answer from upstream %s: %w", s.Upstream, err)
+	}
+	return buf[:n], nil
+}
+
+// Query sends an A query for name to the DNS server at addr and returns the addresses in the answer
+func Query(addr, name string, timeout time.Duration) ([]net.IP, error) {
+	id := uint16(rand.Intn(1 << 16))
+	packet, err := buildQuery(id, name, TypeA)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("udp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write(packet); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, maxPacketSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	rcode, ips, err := parseAnswers(buf[:n])
+	if err != nil {
+		return nil, err
+	}
+	if rcode != RcodeSuccess {
+		return nil, fmt.Errorf("server returned response code %d", rcode)
+	}
+	return ips, nil
+}
+
+// SystemUpstream returns the first non-loopback nameserver from /etc/resolv.conf, or DefaultUpstream
+func SystemUpstream() string {
+	file, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return DefaultUpstream
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		ip := net.ParseIP(fields[1])
+		if ip == nil || ip.IsLoopback() {
+			continue
+		}
+		return net.JoinHostPort(ip.String(), "53")
+	}
+	return DefaultUpstream
+}
+
+// LANAddress returns the IP of the interface used for outbound traffic
+func LANAddress() (net.IP, error) {
+	// UDP "connect" only selects a route; no packets are sent
+	conn, err := net.Dial("udp", "192.0.2.1:53")
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine LAN address: %w", err)
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// AnswerIP resolves the configured answer address: "lan" for the LAN address, otherwise a literal IP
+func AnswerIP(value string) (net.IP, error) {
+	if value == "" {
+		return net.IPv4(127, 0, 0, 1), nil
+	}
+	if value == "lan" {
+		return LANAddress()
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid DNS answer address %q (use an IP or \"lan\")", value)
+	}
+	return ip, nil
+}
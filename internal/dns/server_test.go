@@ -0,0 +1,97 @@
+package dns
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startServer runs s on a random local UDP port and returns its address
+func startServer(t *testing.T, s *Server) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go s.Serve(conn)
+	t.Cleanup(func() { s.Close() })
+
+	return conn.LocalAddr().String()
+}
+
+func TestServer_AnswersLocalDomain(t *testing.T) {
+	addr := startServer(t, NewServer("test", net.ParseIP("127.0.0.1"), ""))
+
+	for _, name := range []string{"test", "shop.test", "vite.shop.TEST"} {
+		ips, err := Query(addr, name, time.Second)
+		require.NoError(t, err, name)
+		require.Len(t, ips, 1, name)
+		assert.True(t, ips[0].Equal(net.ParseIP("127.0.0.1")), name)
+	}
+}
+
+func TestServer_LANAddress(t *testing.T) {
+	addr := startServer(t, NewServer("test", net.ParseIP("192.168.1.20"), ""))
+
+	ips, err := Query(addr, "shop.test", time.Second)
+	require.NoError(t, err)
+	require.Len(t, ips, 1)
+	assert.Equal(t, "192.168.1.20", ips[0].String())
+}
+
+func TestServer_ForwardsOtherNames(t *testing.T) {
+	upstream := startServer(t, NewServer("example", net.ParseIP("10.0.0.7"), ""))
+	addr := startServer(t, NewServer("test", net.ParseIP("127.0.0.1"), upstream))
+
+	ips, err := Query(addr, "www.example", time.Second)
+	require.NoError(t, err)
+	require.Len(t, ips, 1)
+	assert.Equal(t, "10.0.0.7", ips[0].String(), "non-local names are answered by the upstream resolver")
+}
+
+func TestServer_NoUpstream(t *testing.T) {
+	addr := startServer(t, NewServer("test", net.ParseIP("127.0.0.1"), ""))
+
+	_, err := Query(addr, "example.com", 200*time.Millisecond)
+	assert.Error(t, err, "queries outside the domain are dropped without an upstream")
+}
+
+func TestServer_AAAAForIPv4Domain(t *testing.T) {
+	s := NewServer("test", net.ParseIP("127.0.0.1"), "")
+
+	query, err := buildQuery(42, "shop.test", TypeAAAA)
+	require.NoError(t, err)
+
+	response, err := s.Handle(query)
+	require.NoError(t, err)
+
+	rcode, ips, err := parseAnswers(response)
+	require.NoError(t, err)
+	assert.Equal(t, RcodeSuccess, rcode)
+	assert.Empty(t, ips, "no AAAA record for an IPv4 address, but the name exists")
+}
+
+func TestServer_IsLocal(t *testing.T) {
+	s := NewServer(".test.", net.ParseIP("127.0.0.1"), "")
+
+	assert.True(t, s.IsLocal("test"))
+	assert.True(t, s.IsLocal("shop.test."))
+	assert.False(t, s.IsLocal("latest"))
+	assert.False(t, s.IsLocal("test.com"))
+}
+
+func TestAnswerIP(t *testing.T) {
+	ip, err := AnswerIP("")
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", ip.String())
+
+	ip, err = AnswerIP("10.1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, "10.1.2.3", ip.String())
+
+	_, err = AnswerIP("not-an-ip")
+	assert.Error(t, err)
+}
@@ -7,9 +7,13 @@ import (
 	"os/exec"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"phpier/internal/config"
+	"phpier/internal/dockerapi"
 	"phpier/internal/errors"
+	"phpier/internal/runtime"
 )
 
 // ExecConfig represents configuration for executing commands in containers
@@ -25,122 +29,167 @@ type ExecConfig struct {
 	Environment  []string
 }
 
-// Client represents a Docker client wrapper
+// Client represents a container runtime client. Queries go to the runtime's engine (the
+// Engine API for Docker and Podman); compose operations and interactive exec use its CLI.
 type Client struct {
-	ctx context.Context
+	ctx      context.Context
+	runtime  runtime.Runtime
+	api      runtime.Engine
+	timeouts config.TimeoutsConfig
 }
 
-// NewClient creates a new Docker client
-func NewClient() (*Client, error) {
-	// Check if Docker is available
-	if err := checkDockerAvailable(); err != nil {
+// NewClient creates a client for the configured container runtime. Every request and command
+// the client runs stops when ctx is cancelled. A non-zero commandTimeout replaces the configured
+// limits of the up, down and build operations.
+func NewClient(ctx context.Context, commandTimeout time.Duration) (*Client, error) {
+	r, err := runtime.Detect(config.RuntimePreference())
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrorTypeInvalidConfig, "Invalid container runtime", err).
+			WithSuggestion("Set 'runtime' in ~/.phpier/config.yaml to auto, docker, podman or nerdctl")
+	}
+
+	return NewClientForRuntime(ctx, r, commandTimeout)
+}
+
+// NewClientForRuntime creates a client for r after checking that it is available
+func NewClientForRuntime(ctx context.Context, r runtime.Runtime, commandTimeout time.Duration) (*Client, error) {
+	engine, err := r.Engine()
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrorTypeInvalidConfig, "Invalid DOCKER_HOST", err)
+	}
+
+	timeouts := config.Timeouts().WithCommandTimeout(commandTimeout)
+	api := &timeoutEngine{engine: engine, timeout: timeouts.API}
+
+	// Check if the runtime is available
+	if err := checkRuntimeAvailable(ctx, r, api); err != nil {
 		return nil, err
 	}
 
+	logrus.Debugf("Using container runtime %s (compose: %s)", r.Name(), strings.Join(r.ComposeCommand(), " "))
+
 	return &Client{
-		ctx: context.Background(),
+		ctx:      ctx,
+		runtime:  r,
+		api:      api,
+		timeouts: timeouts,
 	}, nil
 }
 
-// Close closes the Docker client
+// Close closes the client
 func (c *Client) Close() {
-	// No cleanup needed for exec-based client
+	if api, ok := c.api.(*timeoutEngine).engine.(*dockerapi.Client); ok {
+		api.HTTPClient.CloseIdleConnections()
+	}
 }
 
-// checkDockerAvailable checks if Docker is installed and running
-func checkDockerAvailable() error {
-	// Check if docker command exists
-	if _, err := exec.LookPath("docker"); err != nil {
-		return errors.NewDockerNotFoundError()
-	}
+// API returns the runtime's query engine
+func (c *Client) API() runtime.Engine {
+	return c.api
+}
 
-	// Check if Docker daemon is running
-	cmd := exec.Command("docker", "version", "--format", "{{.Server.Version}}")
-	if err := cmd.Run(); err != nil {
-		return errors.NewDockerNotRunningError()
+// Runtime returns the container runtime the client drives
+func (c *Client) Runtime() runtime.Runtime {
+	return c.runtime
+}
+
+// CLI returns the runtime's command line tool (docker, podman or nerdctl)
+func (c *Client) CLI() string {
+	return c.runtime.CLI()
+}
+
+// checkRuntimeAvailable checks if the runtime is installed and running and has compose
+func checkRuntimeAvailable(ctx context.Context, r runtime.Runtime, api runtime.Engine) error {
+	err := runtime.Check(ctx, r, api)
+	if err == nil {
+		return nil
 	}
+	logrus.Debugf("Container runtime check failed: %v", err)
 
-	// Check if docker-compose is available
-	if _, err := exec.LookPath("docker-compose"); err != nil {
-		// Try compose plugin
-		if _, err2 := exec.LookPath("docker"); err2 == nil {
-			cmd := exec.Command("docker", "compose", "version")
-			if err3 := cmd.Run(); err3 != nil {
-				return errors.NewDockerComposeNotFoundError()
-			}
-		} else {
-			return errors.NewDockerComposeNotFoundError()
+	switch cause := err.(type) {
+	case *runtime.NotRunningError:
+		// A hung engine or Ctrl-C is reported as such, not as a stopped runtime
+		if _, ok := cause.Err.(*errors.PhpierError); ok {
+			return cause.Err
 		}
+		return errors.NewRuntimeNotRunningError(r.Name(), r.Socket())
+	case *runtime.ComposeNotFoundError:
+		return errors.NewRuntimeComposeNotFoundError(r.Name())
+	default:
+		return errors.NewRuntimeNotFoundError(r.Name())
 	}
-
-	return nil
 }
 
-// IsDockerRunning checks if Docker daemon is running
+// IsDockerRunning checks if the runtime's engine is running
 func (c *Client) IsDockerRunning() bool {
-	cmd := exec.Command("docker", "version", "--format", "{{.Server.Version}}")
-	return cmd.Run() == nil
+	return c.api.Ping(c.ctx) == nil
 }
 
-// GetDockerComposeCommand returns the appropriate docker-compose command
-func (c *Client) GetDockerComposeCommand() string {
-	// Check if docker-compose is available
-	if _, err := exec.LookPath("docker-compose"); err == nil {
-		return "docker-compose"
-	}
-
-	// Check if docker compose plugin is available
-	cmd := exec.Command("docker", "compose", "version")
-	if err := cmd.Run(); err == nil {
-		return "docker compose"
-	}
-
-	return "docker-compose" // fallback
+// ComposeCommand returns the compose command prefix, e.g. [docker compose] or [podman-compose]
+func (c *Client) ComposeCommand() []string {
+	return c.runtime.ComposeCommand()
 }
 
-// RunCommand executes a Docker command
+// RunCommand executes a Docker command, limited only by the client's context
 func (c *Client) RunCommand(command string, args ...string) error {
-	cmd := exec.Command(command, args...)
+	return c.RunCommandTimeout(0, command, args...)
+}
+
+// RunCommandTimeout executes a Docker command, interrupting it after timeout (0 for no limit)
+func (c *Client) RunCommandTimeout(timeout time.Duration, command string, args ...string) error {
+	ctx, cancel := withTimeout(c.ctx, timeout)
+	defer cancel()
+
+	cmd := commandContext(ctx, command, args...)
 	cmd.Stdout = logrus.StandardLogger().Out
 	cmd.Stderr = logrus.StandardLogger().Out
 
 	logrus.Debugf("Executing: %s %s", command, strings.Join(args, " "))
 
 	if err := cmd.Run(); err != nil {
-		return errors.NewCommandFailedError(command, args, err)
+		return c.commandError(ctx, timeout, command, args, err)
 	}
 
 	return nil
 }
 
-// RunCommandOutput executes a Docker command and returns output
+// RunCommandOutput executes a Docker command within the API timeout and returns output
 func (c *Client) RunCommandOutput(command string, args ...string) (string, error) {
-	cmd := exec.Command(command, args...)
+	ctx, cancel := withTimeout(c.ctx, c.timeouts.API)
+	defer cancel()
+
+	cmd := commandContext(ctx, command, args...)
 
 	logrus.Debugf("Executing: %s %s", command, strings.Join(args, " "))
 
 	output, err := cmd.Output()
 	if err != nil {
-		return "", errors.NewCommandFailedError(command, args, err)
+		return "", c.commandError(ctx, c.timeouts.API, command, args, err)
 	}
 
 	return strings.TrimSpace(string(output)), nil
 }
 
+// commandError reports a failed command, or why it was stopped if ctx ended
+func (c *Client) commandError(ctx context.Context, timeout time.Duration, command string, args []string, err error) error {
+	operation := strings.TrimSpace(command + " " + strings.Join(args, " "))
+	if ctxErr := interruption(ctx, operation, timeout, err); ctxErr != nil {
+		return ctxErr
+	}
+	return errors.NewCommandFailedError(command, args, err)
+}
+
+// Timeouts returns the operation timeouts the client applies
+func (c *Client) Timeouts() config.TimeoutsConfig {
+	return c.timeouts
+}
+
 // GetContainerID gets the container ID for a service
 func (c *Client) GetContainerID(projectName, serviceName string) (string, error) {
-	composeCmdStr := c.GetDockerComposeCommand()
-	var args []string
-
-	if strings.Contains(composeCmdStr, " ") {
-		parts := strings.Split(composeCmdStr, " ")
-		composeCmdStr = parts[0]
-		args = append(parts[1:], "-p", projectName, "ps", "-q", serviceName)
-	} else {
-		args = []string{"-p", projectName, "ps", "-q", serviceName}
-	}
+	compose := c.ComposeCommand()
+	args := append(append([]string{}, compose[1:]...), "-p", projectName, "ps", "-q", serviceName)
 
-	containerID, err := c.RunCommandOutput(composeCmdStr, args...)
+	containerID, err := c.RunCommandOutput(compose[0], args...)
 	if err != nil {
 		return "", err
 	}
@@ -155,26 +204,37 @@ func (c *Client) GetContainerID(projectName, serviceName string) (string, error)
 // ExecInContainer executes a command in a container
 func (c *Client) ExecInContainer(containerID string, command []string) error {
 	args := append([]string{"exec", "-it", containerID}, command...)
-	return c.RunCommand("docker", args...)
+	return c.RunCommand(c.CLI(), args...)
 }
 
 // ExecInContainerOutput executes a command in a container and returns output
 func (c *Client) ExecInContainerOutput(containerID string, command []string) (string, error) {
-	args := append([]string{"exec", "-i", containerID}, command...)
-	return c.RunCommandOutput("docker", args...)
+	logrus.Debugf("Executing in %s: %s", containerID, strings.Join(command, " "))
+
+	result, err := c.api.Exec(c.ctx, containerID, dockerapi.ExecOptions{Cmd: command})
+	if err != nil {
+		return "", errors.NewCommandFailedError(c.CLI()+" exec", command, err)
+	}
+	if result.ExitCode != 0 {
+		return "", errors.NewCommandFailedError(c.CLI()+" exec", command,
+			fmt.Errorf("exit status %d: %s", result.ExitCode, strings.TrimSpace(result.Stderr)))
+	}
+
+	return strings.TrimSpace(result.Stdout), nil
 }
 
 // IsContainerRunning checks if a container is running
 func (c *Client) IsContainerRunning(ctx context.Context, containerName string) (bool, error) {
-	output, err := c.RunCommandOutput("docker", "ps", "--filter", fmt.Sprintf("name=%s", containerName), "--filter", "status=running", "--format", "{{.Names}}")
+	containers, err := c.api.ContainerList(ctx, dockerapi.ContainerListOptions{
+		Filters: dockerapi.Filters{"name": {containerName}, "status": {"running"}},
+	})
 	if err != nil {
 		return false, err
 	}
 
-	// Check if the container name appears in the output
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-	for _, line := range lines {
-		if strings.TrimSpace(line) == containerName {
+	// The name filter matches substrings, so compare exactly
+	for _, container := range containers {
+		if container.Name() == containerName {
 			return true, nil
 		}
 	}
@@ -188,13 +248,81 @@ func (c *Client) IsContainerRunningByID(ctx context.Context, containerID string)
 		return false, nil
 	}
 
-	output, err := c.RunCommandOutput("docker", "inspect", "--format", "{{.State.Status}}", containerID)
+	info, err := c.api.ContainerInspect(ctx, containerID)
 	if err != nil {
 		return false, err
 	}
 
-	status := strings.TrimSpace(output)
-	return status == "running", nil
+	return info.State.Status == "running", nil
+}
+
+// ContainerHealth returns the healthcheck status of a container (starting, healthy, unhealthy),
+// or an empty string when the container has no healthcheck
+func (c *Client) ContainerHealth(ctx context.Context, containerID string) (string, error) {
+	info, err := c.api.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	if info.State.Health == nil {
+		return "", nil
+	}
+	return info.State.Health.Status, nil
+}
+
+// ContainerPublishingPort returns the name and compose project of the container publishing
+// host port, or an empty name when no container publishes it
+func (c *Client) ContainerPublishingPort(port int) (string, string, error) {
+	containers, err := c.api.ContainerList(c.ctx, dockerapi.ContainerListOptions{
+		Filters: dockerapi.Filters{"publish": {fmt.Sprintf("%d", port)}},
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if len(containers) == 0 {
+		return "", "", nil
+	}
+	return containers[0].Name(), containers[0].Labels["com.docker.compose.project"], nil
+}
+
+// NetworkEndpoint is a container attached to a Docker network and the aliases it answers to there
+type NetworkEndpoint struct {
+	Container string
+	Project   string
+	Aliases   []string
+}
+
+// NetworkEndpoints returns the containers attached to network with their compose project and aliases
+func (c *Client) NetworkEndpoints(network string) ([]NetworkEndpoint, error) {
+	if _, err := c.api.NetworkInspect(c.ctx, network); err != nil {
+		return nil, err
+	}
+
+	containers, err := c.api.ContainerList(c.ctx, dockerapi.ContainerListOptions{
+		Filters: dockerapi.Filters{"network": {network}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return networkEndpoints(containers, network), nil
+}
+
+func networkEndpoints(containers []dockerapi.Container, network string) []NetworkEndpoint {
+	var endpoints []NetworkEndpoint
+	for _, container := range containers {
+		endpoint := NetworkEndpoint{
+			Container: container.Name(),
+			Project:   container.Labels["com.docker.compose.project"],
+		}
+		// Docker reports the short container ID as an alias too; it isn't a stable name
+		for _, alias := range container.NetworkSettings.Networks[network].Aliases {
+			if len(alias) == 12 && strings.HasPrefix(container.ID, alias) {
+				continue
+			}
+			endpoint.Aliases = append(endpoint.Aliases, alias)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
 }
 
 // ExecInteractive executes a command interactively in a container
@@ -232,7 +360,7 @@ func (c *Client) ExecInteractive(ctx context.Context, config *ExecConfig) (int,
 	args = append(args, config.Command...)
 
 	// Create command
-	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd := commandContext(ctx, c.CLI(), args...)
 
 	// Attach stdio
 	if config.AttachStdin {
@@ -245,11 +373,14 @@ func (c *Client) ExecInteractive(ctx context.Context, config *ExecConfig) (int,
 		cmd.Stderr = os.Stderr
 	}
 
-	logrus.Debugf("Executing: docker %s", strings.Join(args, " "))
+	logrus.Debugf("Executing: %s %s", c.CLI(), strings.Join(args, " "))
 
 	// Run the command
 	err := cmd.Run()
 	if err != nil {
+		if ctxErr := interruption(ctx, c.CLI()+" exec", 0, err); ctxErr != nil {
+			return 1, ctxErr
+		}
 		// Check if it's an exit error to get the exit code
 		if exitError, ok := err.(*exec.ExitError); ok {
 			if status, ok := exitError.Sys().(syscall.WaitStatus); ok {
@@ -272,21 +403,18 @@ type ProjectInfo struct {
 
 // GetRunningPhpierProjects returns a list of running phpier projects
 func (c *Client) GetRunningPhpierProjects() ([]string, error) {
-	output, err := c.RunCommandOutput("docker", "ps", "--filter", "label=com.docker.compose.project", "--format", "{{.Label \"com.docker.compose.project\"}}")
+	containers, err := c.api.ContainerList(c.ctx, dockerapi.ContainerListOptions{
+		Filters: dockerapi.Filters{"label": {"com.docker.compose.project"}},
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if strings.TrimSpace(output) == "" {
-		return []string{}, nil
-	}
-
-	lines := strings.Split(strings.TrimSpace(output), "\n")
 	projects := make(map[string]bool)
-	var result []string
+	result := []string{}
 
-	for _, line := range lines {
-		project := strings.TrimSpace(line)
+	for _, container := range containers {
+		project := container.Labels["com.docker.compose.project"]
 		if project != "" && project != "phpier" && !projects[project] {
 			projects[project] = true
 			result = append(result, project)
@@ -299,55 +427,33 @@ func (c *Client) GetRunningPhpierProjects() ([]string, error) {
 // GetAllPhpierProjects returns all phpier projects (running and stopped)
 func (c *Client) GetAllPhpierProjects() ([]ProjectInfo, error) {
 	// Get all containers (running and stopped) with phpier labels
-	output, err := c.RunCommandOutput("docker", "ps", "-a",
-		"--filter", "label=com.docker.compose.project",
-		"--filter", "label=phpier.managed=true",
-		"--format", "{{.Label \"com.docker.compose.project\"}}\t{{.Status}}\t{{.Image}}\t{{.Label \"com.docker.compose.working-dir\"}}")
+	containers, err := c.api.ContainerList(c.ctx, dockerapi.ContainerListOptions{
+		All:     true,
+		Filters: dockerapi.Filters{"label": {"com.docker.compose.project", "phpier.managed=true"}},
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if strings.TrimSpace(output) == "" {
-		return []ProjectInfo{}, nil
-	}
-
-	lines := strings.Split(strings.TrimSpace(output), "\n")
 	projectMap := make(map[string]*ProjectInfo)
 
-	for _, line := range lines {
-		parts := strings.Split(strings.TrimSpace(line), "\t")
-		if len(parts) < 3 {
-			continue
-		}
-
-		projectName := strings.TrimSpace(parts[0])
-		status := strings.TrimSpace(parts[1])
-		imageName := strings.TrimSpace(parts[2])
-		workingDir := ""
-		if len(parts) > 3 {
-			workingDir = strings.TrimSpace(parts[3])
-		}
+	for _, container := range containers {
+		projectName := container.Labels["com.docker.compose.project"]
 
 		// Skip global phpier services
 		if projectName == "" || projectName == "phpier" {
 			continue
 		}
 
-		// Determine project status from container status
-		projectStatus := "stopped"
-		if strings.Contains(status, "Up") {
-			projectStatus = "running"
-		} else if strings.Contains(status, "Created") {
-			projectStatus = "created"
-		}
+		projectStatus := containerProjectStatus(container.State)
 
 		// Use the first container found for each project or update with running status
 		if existing, exists := projectMap[projectName]; !exists || (existing.Status != "running" && projectStatus == "running") {
 			projectMap[projectName] = &ProjectInfo{
 				Name:      projectName,
 				Status:    projectStatus,
-				ImageName: imageName,
-				Path:      workingDir,
+				ImageName: container.Image,
+				Path:      container.Labels["com.docker.compose.working-dir"],
 			}
 		}
 	}
@@ -361,6 +467,18 @@ func (c *Client) GetAllPhpierProjects() ([]ProjectInfo, error) {
 	return result, nil
 }
 
+// containerProjectStatus maps a container state to a project status (running, created, stopped)
+func containerProjectStatus(state string) string {
+	switch state {
+	case "running", "restarting", "paused":
+		return "running"
+	case "created":
+		return "created"
+	default:
+		return "stopped"
+	}
+}
+
 // GetPhpierProjectByName finds a specific phpier project by name using Docker
 func (c *Client) GetPhpierProjectByName(projectName string) (*ProjectInfo, error) {
 	projects, err := c.GetAllPhpierProjects()
@@ -377,96 +495,80 @@ func (c *Client) GetPhpierProjectByName(projectName string) (*ProjectInfo, error
 	return nil, fmt.Errorf("project '%s' not found in Docker containers", projectName)
 }
 
-// GetPhpierImages returns all phpier-built images (prefixed with phpier-)
+// GetPhpierImages returns all phpier-built project images (prefixed with phpier-, excluding shared base images)
 func (c *Client) GetPhpierImages() ([]string, error) {
-	output, err := c.RunCommandOutput("docker", "images",
-		"--filter", "reference=phpier-*",
-		"--format", "{{.Repository}}:{{.Tag}}")
+	images, err := c.api.ImageList(c.ctx, dockerapi.Filters{"reference": {"phpier-*"}})
 	if err != nil {
 		return nil, err
 	}
 
-	if strings.TrimSpace(output) == "" {
-		return []string{}, nil
-	}
-
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-	var result []string
-
-	for _, line := range lines {
-		imageName := strings.TrimSpace(line)
-		if imageName != "" && strings.HasPrefix(imageName, "phpier-") {
-			result = append(result, imageName)
+	result := []string{}
+	for _, image := range images {
+		for _, imageName := range image.RepoTags {
+			if isProjectImage(imageName) {
+				result = append(result, imageName)
+			}
 		}
 	}
 
 	return result, nil
 }
 
+// isProjectImage reports whether ref is a phpier-<project>:<tag> image rather than a shared base image
+func isProjectImage(ref string) bool {
+	return strings.HasPrefix(ref, "phpier-") && !strings.HasPrefix(ref, BaseImageRepository+":")
+}
+
+// projectNameFromImage extracts the project name from a phpier-<project>:<tag> image reference
+func projectNameFromImage(ref string) string {
+	projectName := strings.TrimPrefix(ref, "phpier-")
+	if colonIndex := strings.Index(projectName, ":"); colonIndex != -1 {
+		projectName = projectName[:colonIndex]
+	}
+	return projectName
+}
+
 // GetPhpierProjectsFromImages discovers projects by scanning phpier- prefixed Docker images
 func (c *Client) GetPhpierProjectsFromImages() ([]ProjectInfo, error) {
-	// Get all phpier- prefixed images
-	output, err := c.RunCommandOutput("docker", "images",
-		"--filter", "reference=phpier-*",
-		"--format", "{{.Repository}}:{{.Tag}}")
+	images, err := c.GetPhpierImages()
 	if err != nil {
 		return nil, err
 	}
-
-	if strings.TrimSpace(output) == "" {
+	if len(images) == 0 {
 		return []ProjectInfo{}, nil
 	}
 
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-	projectMap := make(map[string]ProjectInfo)
-
-	for _, line := range lines {
-		imageName := strings.TrimSpace(line)
-		if imageName == "" || !strings.HasPrefix(imageName, "phpier-") {
-			continue
+	// One listing of all containers replaces a lookup per image
+	containers, err := c.api.ContainerList(c.ctx, dockerapi.ContainerListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+	byImage := make(map[string]dockerapi.Container)
+	for _, container := range containers {
+		if existing, exists := byImage[container.Image]; !exists || (existing.State != "running" && container.State == "running") {
+			byImage[container.Image] = container
 		}
+	}
 
-		// Extract project name from image name (remove phpier- prefix and :tag)
-		projectName := strings.TrimPrefix(imageName, "phpier-")
-		if colonIndex := strings.Index(projectName, ":"); colonIndex != -1 {
-			projectName = projectName[:colonIndex]
-		}
+	projectMap := make(map[string]ProjectInfo)
 
+	for _, imageName := range images {
+		projectName := projectNameFromImage(imageName)
 		if projectName == "" {
 			continue
 		}
 
-		// Check if this project has running containers
-		status := "stopped"
-		workingDir := ""
-
-		// Try to get container info for this project
-		containerOutput, err := c.RunCommandOutput("docker", "ps", "-a",
-			"--filter", fmt.Sprintf("ancestor=%s", imageName),
-			"--format", "{{.Status}}\t{{.Label \"com.docker.compose.working-dir\"}}")
-
-		if err == nil && strings.TrimSpace(containerOutput) != "" {
-			parts := strings.Split(strings.TrimSpace(containerOutput), "\t")
-			if len(parts) > 0 {
-				containerStatus := strings.TrimSpace(parts[0])
-				if strings.Contains(containerStatus, "Up") {
-					status = "running"
-				} else if strings.Contains(containerStatus, "Created") {
-					status = "created"
-				}
-
-				if len(parts) > 1 {
-					workingDir = strings.TrimSpace(parts[1])
-				}
-			}
-		}
-
-		projectMap[projectName] = ProjectInfo{
+		project := ProjectInfo{
 			Name:      projectName,
-			Status:    status,
+			Status:    "stopped",
 			ImageName: imageName,
-			Path:      workingDir,
 		}
+		if container, exists := byImage[imageName]; exists {
+			project.Status = containerProjectStatus(container.State)
+			project.Path = container.Labels["com.docker.compose.working-dir"]
+		}
+
+		projectMap[projectName] = project
 	}
 
 	// Convert map to slice
@@ -480,30 +582,25 @@ func (c *Client) GetPhpierProjectsFromImages() ([]ProjectInfo, error) {
 
 // GetProjectWorkingDirectory attempts to find the working directory for a project
 func (c *Client) GetProjectWorkingDirectory(projectName string) (string, error) {
-	// Try to get working directory from running container
-	output, err := c.RunCommandOutput("docker", "ps",
-		"--filter", fmt.Sprintf("label=com.docker.compose.project=%s", projectName),
-		"--format", "{{.Label \"com.docker.compose.working-dir\"}}")
-	if err != nil {
-		return "", err
-	}
-
-	if workDir := strings.TrimSpace(output); workDir != "" {
-		return workDir, nil
-	}
-
-	// If not running, try to get from stopped containers
-	output, err = c.RunCommandOutput("docker", "ps", "-a",
-		"--filter", fmt.Sprintf("label=com.docker.compose.project=%s", projectName),
-		"--format", "{{.Label \"com.docker.compose.working-dir\"}}")
+	containers, err := c.api.ContainerList(c.ctx, dockerapi.ContainerListOptions{
+		All:     true,
+		Filters: dockerapi.Filters{"label": {fmt.Sprintf("com.docker.compose.project=%s", projectName)}},
+	})
 	if err != nil {
 		return "", err
 	}
 
-	workDir := strings.TrimSpace(output)
-	if workDir == "" {
-		return "", fmt.Errorf("working directory not found for project '%s'", projectName)
+	// Prefer running containers, then fall back to stopped ones
+	for _, running := range []bool{true, false} {
+		for _, container := range containers {
+			if (container.State == "running") != running {
+				continue
+			}
+			if workDir := container.Labels["com.docker.compose.working-dir"]; workDir != "" {
+				return workDir, nil
+			}
+		}
 	}
 
-	return workDir, nil
+	return "", fmt.Errorf("working directory not found for project '%s'", projectName)
 }
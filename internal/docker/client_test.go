@@ -0,0 +1,35 @@
+package docker
+
+import (
+	"testing"
+
+	"phpier/internal/dockerapi"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkEndpoints(t *testing.T) {
+	app := dockerapi.Container{ID: "0123456789abcdef", Names: []string{"/shop-app-1"}, Labels: map[string]string{"com.docker.compose.project": "shop"}}
+	app.NetworkSettings.Networks = map[string]dockerapi.EndpointSettings{
+		"phpier_phpier_global": {Aliases: []string{"shop.internal", "shop.localhost", "app", "0123456789ab"}},
+	}
+	standalone := dockerapi.Container{ID: "fedcba", Names: []string{"/standalone"}}
+
+	endpoints := networkEndpoints([]dockerapi.Container{app, standalone}, "phpier_phpier_global")
+	assert.Equal(t, []NetworkEndpoint{
+		{Container: "shop-app-1", Project: "shop", Aliases: []string{"shop.internal", "shop.localhost", "app"}},
+		{Container: "standalone"},
+	}, endpoints)
+}
+
+func TestContainerProjectStatus(t *testing.T) {
+	assert.Equal(t, "running", containerProjectStatus("running"))
+	assert.Equal(t, "created", containerProjectStatus("created"))
+	assert.Equal(t, "stopped", containerProjectStatus("exited"))
+}
+
+func TestProjectNameFromImage(t *testing.T) {
+	assert.Equal(t, "shop", projectNameFromImage("phpier-shop:8.3"))
+	assert.True(t, isProjectImage("phpier-shop:8.3"))
+	assert.False(t, isProjectImage(BaseImageRepository+":8.3-abc123"))
+}
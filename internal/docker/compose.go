@@ -1,10 +1,12 @@
 package docker
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"sort"
+	"time"
 
 	"phpier/internal/config"
 
@@ -35,7 +37,6 @@ type ComposeManager interface {
 	DownWithOptions(options DownOptions) error
 	Build(noCache bool, services ...string) error
 	Reload(options ReloadOptions) error
-	Logs(service string, follow bool, tail int, since string) error
 }
 
 // GlobalServiceChecker interface for checking global service status.
@@ -48,7 +49,7 @@ type GlobalServiceChecker interface {
 type GlobalComposeManager struct {
 	client     *Client
 	globalCfg  *config.GlobalConfig
-	composeCmd string
+	composeCmd []string
 	workDir    string
 }
 
@@ -57,13 +58,14 @@ type ProjectComposeManager struct {
 	client     *Client
 	projectCfg *config.ProjectConfig
 	globalCfg  *config.GlobalConfig
-	composeCmd string
+	composeCmd []string
 	workDir    string
 }
 
-// NewProjectComposeManager creates a new Docker Compose manager for a project.
-func NewProjectComposeManager(projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig) (*ProjectComposeManager, error) {
-	client, err := NewClient()
+// NewProjectComposeManager creates a new Docker Compose manager for a project. A non-zero
+// commandTimeout replaces the configured up, down and build limits.
+func NewProjectComposeManager(ctx context.Context, projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig, commandTimeout time.Duration) (*ProjectComposeManager, error) {
+	client, err := NewClient(ctx, commandTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -81,14 +83,14 @@ func NewProjectComposeManager(projectCfg *config.ProjectConfig, globalCfg *confi
 		client:     client,
 		projectCfg: projectCfg,
 		globalCfg:  globalCfg,
-		composeCmd: client.GetDockerComposeCommand(),
+		composeCmd: client.ComposeCommand(),
 		workDir:    workDir,
 	}, nil
 }
 
 // NewProjectComposeManagerWithPath creates a new Docker Compose manager for a project at a specific path.
-func NewProjectComposeManagerWithPath(projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig, projectPath string) (*ProjectComposeManager, error) {
-	client, err := NewClient()
+func NewProjectComposeManagerWithPath(ctx context.Context, projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig, projectPath string, commandTimeout time.Duration) (*ProjectComposeManager, error) {
+	client, err := NewClient(ctx, commandTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +105,7 @@ func NewProjectComposeManagerWithPath(projectCfg *config.ProjectConfig, globalCf
 		client:     client,
 		projectCfg: projectCfg,
 		globalCfg:  globalCfg,
-		composeCmd: client.GetDockerComposeCommand(),
+		composeCmd: client.ComposeCommand(),
 		workDir:    projectPath,
 	}, nil
 }
@@ -114,12 +116,19 @@ func (cm *ProjectComposeManager) Up(detached bool) error {
 		return fmt.Errorf("Docker daemon is not running. Please start Docker")
 	}
 
+	// Compose builds the app image on first start, which needs the shared base image
+	if !cm.client.ImageExists(cm.projectImage()) {
+		if err := cm.ensureBaseImage(); err != nil {
+			return err
+		}
+	}
+
 	args := cm.buildComposeArgs("up")
 	if detached {
 		args = append(args, "-d")
 	}
 
-	return cm.runComposeCommand(args...)
+	return cm.runComposeCommand(cm.client.timeouts.Up, args...)
 }
 
 // Down stops the Docker Compose services for a project.
@@ -129,7 +138,7 @@ func (cm *ProjectComposeManager) Down(removeVolumes bool) error {
 		args = append(args, "-v")
 	}
 
-	return cm.runComposeCommand(args...)
+	return cm.runComposeCommand(cm.client.timeouts.Down, args...)
 }
 
 // DownWithOptions stops the Docker Compose services for a project with additional options.
@@ -145,18 +154,26 @@ func (cm *ProjectComposeManager) DownWithOptions(options DownOptions) error {
 		args = append(args, "--timeout", fmt.Sprintf("%d", options.Timeout))
 	}
 
-	return cm.runComposeCommand(args...)
+	return cm.runComposeCommand(cm.client.timeouts.Down, args...)
 }
 
+// proxyEnvVars are host proxy settings forwarded to image builds when set.
+var proxyEnvVars = config.BuildProxyVars
+
 // Build builds the Docker image for a project.
 func (cm *ProjectComposeManager) Build(noCache bool, services ...string) error {
+	if err := cm.ensureBaseImage(); err != nil {
+		return err
+	}
+
 	args := cm.buildComposeArgs("build")
 	if noCache {
 		args = append(args, "--no-cache")
 	}
+	// build.args and the proxy settings are in .phpier.yml, so 'up' builds the same image
 	args = append(args, services...)
 
-	return cm.runComposeCommand(args...)
+	return cm.runComposeCommand(cm.client.timeouts.Build, args...)
 }
 
 // Reload restarts the Docker Compose services for a project with various options.
@@ -184,7 +201,7 @@ func (cm *ProjectComposeManager) Reload(options ReloadOptions) error {
 		if options.Pull {
 			logrus.Infof("📥 Pulling latest base images...")
 			pullArgs := cm.buildComposeArgs("pull")
-			if err := cm.runComposeCommand(pullArgs...); err != nil {
+			if err := cm.runComposeCommand(cm.client.timeouts.Build, pullArgs...); err != nil {
 				return fmt.Errorf("failed to pull images: %w", err)
 			}
 		}
@@ -204,43 +221,43 @@ func (cm *ProjectComposeManager) Reload(options ReloadOptions) error {
 	return nil
 }
 
-// Logs displays logs from the Docker Compose services for a project.
-func (cm *ProjectComposeManager) Logs(service string, follow bool, tail int, since string) error {
-	if !cm.client.IsDockerRunning() {
-		return fmt.Errorf("Docker daemon is not running. Please start Docker")
-	}
-
-	args := cm.buildComposeArgs("logs")
+// buildArgs returns --build-arg flags for the project's build.args and any host proxy settings,
+// for the base image, which is built outside Compose.
+func (cm *ProjectComposeManager) buildArgs() []string {
+	return ProjectBuildArgs(cm.projectCfg)
+}
 
-	// Add follow flag
-	if follow {
-		args = append(args, "-f")
+// ProjectBuildArgs returns --build-arg flags for a project's build.args and any host proxy settings.
+func ProjectBuildArgs(projectCfg *config.ProjectConfig) []string {
+	values := make(map[string]string)
+	for _, name := range proxyEnvVars {
+		if value := os.Getenv(name); value != "" {
+			values[name] = value
+		}
 	}
-
-	// Add tail flag
-	if tail > 0 {
-		args = append(args, "--tail", fmt.Sprintf("%d", tail))
+	if projectCfg != nil {
+		for name, value := range projectCfg.Build.Args {
+			values[name] = value
+		}
 	}
 
-	// Add since flag
-	if since != "" {
-		args = append(args, "--since", since)
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	// Add service if specified
-	if service != "" {
-		args = append(args, service)
+	var args []string
+	for _, name := range names {
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", name, values[name]))
 	}
-
-	return cm.runComposeCommand(args...)
+	return args
 }
 
-// buildComposeArgs builds the base arguments for project docker-compose commands.
+// buildComposeArgs builds the base arguments for project compose commands.
 func (cm *ProjectComposeManager) buildComposeArgs(command string) []string {
-	var args []string
-	if strings.Contains(cm.composeCmd, " ") {
-		args = []string{"compose"}
-	}
+	// Subcommand-style compose ([docker compose]) needs its subcommand before the flags
+	args := append([]string{}, cm.composeCmd[1:]...)
 
 	args = append(args, "-f", ".phpier.yml")
 	args = append(args, "-p", cm.projectCfg.Name)
@@ -249,8 +266,9 @@ func (cm *ProjectComposeManager) buildComposeArgs(command string) []string {
 	return args
 }
 
-// runComposeCommand runs a docker-compose command in the project's .phpier directory.
-func (cm *ProjectComposeManager) runComposeCommand(args ...string) error {
+// runComposeCommand runs a compose command in the project's .phpier directory, interrupting
+// it after timeout (0 for no limit).
+func (cm *ProjectComposeManager) runComposeCommand(timeout time.Duration, args ...string) error {
 	originalDir, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
@@ -265,15 +283,13 @@ func (cm *ProjectComposeManager) runComposeCommand(args ...string) error {
 		}
 	}()
 
-	if strings.Contains(cm.composeCmd, " ") {
-		return cm.client.RunCommand("docker", args...)
-	}
-	return cm.client.RunCommand(cm.composeCmd, args...)
+	return cm.client.RunCommandTimeout(timeout, cm.composeCmd[0], args...)
 }
 
-// NewGlobalComposeManager creates a new Docker Compose manager for the global services.
-func NewGlobalComposeManager(globalCfg *config.GlobalConfig) (*GlobalComposeManager, error) {
-	client, err := NewClient()
+// NewGlobalComposeManager creates a new Docker Compose manager for the global services. A
+// non-zero commandTimeout replaces the configured up, down and build limits.
+func NewGlobalComposeManager(ctx context.Context, globalCfg *config.GlobalConfig, commandTimeout time.Duration) (*GlobalComposeManager, error) {
+	client, err := NewClient(ctx, commandTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -286,7 +302,7 @@ func NewGlobalComposeManager(globalCfg *config.GlobalConfig) (*GlobalComposeMana
 	return &GlobalComposeManager{
 		client:     client,
 		globalCfg:  globalCfg,
-		composeCmd: client.GetDockerComposeCommand(),
+		composeCmd: client.ComposeCommand(),
 		workDir:    filepath.Join(home, ".phpier"),
 	}, nil
 }
@@ -302,7 +318,20 @@ func (gcm *GlobalComposeManager) Up(detached bool) error {
 		args = append(args, "-d")
 	}
 
-	return gcm.runComposeCommand(args...)
+	return gcm.runComposeCommand(gcm.client.timeouts.Up, args...)
+}
+
+// Recreate recreates the given global services so they pick up changed configuration.
+func (gcm *GlobalComposeManager) Recreate(services ...string) error {
+	if !gcm.client.IsDockerRunning() {
+		return fmt.Errorf("Docker daemon is not running. Please start Docker")
+	}
+
+	args := gcm.buildComposeArgs("up")
+	args = append(args, "-d", "--force-recreate")
+	args = append(args, services...)
+
+	return gcm.runComposeCommand(gcm.client.timeouts.Up, args...)
 }
 
 // Down stops the Docker Compose services for the global stack.
@@ -312,7 +341,7 @@ func (gcm *GlobalComposeManager) Down(removeVolumes bool) error {
 		args = append(args, "-v")
 	}
 
-	return gcm.runComposeCommand(args...)
+	return gcm.runComposeCommand(gcm.client.timeouts.Down, args...)
 }
 
 // DownWithOptions stops the Docker Compose services for the global stack with additional options.
@@ -328,7 +357,7 @@ func (gcm *GlobalComposeManager) DownWithOptions(options DownOptions) error {
 		args = append(args, "--timeout", fmt.Sprintf("%d", options.Timeout))
 	}
 
-	return gcm.runComposeCommand(args...)
+	return gcm.runComposeCommand(gcm.client.timeouts.Down, args...)
 }
 
 // Build builds the Docker images for the global stack.
@@ -339,7 +368,7 @@ func (gcm *GlobalComposeManager) Build(noCache bool, services ...string) error {
 	}
 	args = append(args, services...)
 
-	return gcm.runComposeCommand(args...)
+	return gcm.runComposeCommand(gcm.client.timeouts.Build, args...)
 }
 
 // Reload is not supported for global services - use 'phpier global down' and 'phpier global up' instead.
@@ -347,43 +376,10 @@ func (gcm *GlobalComposeManager) Reload(options ReloadOptions) error {
 	return fmt.Errorf("reload is not supported for global services - use 'phpier global down' and 'phpier global up' instead")
 }
 
-// Logs displays logs from the Docker Compose services for the global stack.
-func (gcm *GlobalComposeManager) Logs(service string, follow bool, tail int, since string) error {
-	if !gcm.client.IsDockerRunning() {
-		return fmt.Errorf("Docker daemon is not running. Please start Docker")
-	}
-
-	args := gcm.buildComposeArgs("logs")
-
-	// Add follow flag
-	if follow {
-		args = append(args, "-f")
-	}
-
-	// Add tail flag
-	if tail > 0 {
-		args = append(args, "--tail", fmt.Sprintf("%d", tail))
-	}
-
-	// Add since flag
-	if since != "" {
-		args = append(args, "--since", since)
-	}
-
-	// Add service if specified
-	if service != "" {
-		args = append(args, service)
-	}
-
-	return gcm.runComposeCommand(args...)
-}
-
-// buildComposeArgs builds the base arguments for global docker-compose commands.
+// buildComposeArgs builds the base arguments for global compose commands.
 func (gcm *GlobalComposeManager) buildComposeArgs(command string) []string {
-	var args []string
-	if strings.Contains(gcm.composeCmd, " ") {
-		args = []string{"compose"}
-	}
+	// Subcommand-style compose ([docker compose]) needs its subcommand before the flags
+	args := append([]string{}, gcm.composeCmd[1:]...)
 
 	args = append(args, "-f", "docker-compose.yml")
 	args = append(args, "-p", "phpier")
@@ -392,8 +388,9 @@ func (gcm *GlobalComposeManager) buildComposeArgs(command string) []string {
 	return args
 }
 
-// runComposeCommand runs a docker-compose command in the global .phpier directory.
-func (gcm *GlobalComposeManager) runComposeCommand(args ...string) error {
+// runComposeCommand runs a compose command in the global .phpier directory, interrupting it
+// after timeout (0 for no limit).
+func (gcm *GlobalComposeManager) runComposeCommand(timeout time.Duration, args ...string) error {
 	originalDir, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
@@ -408,10 +405,7 @@ func (gcm *GlobalComposeManager) runComposeCommand(args ...string) error {
 		}
 	}()
 
-	if strings.Contains(gcm.composeCmd, " ") {
-		return gcm.client.RunCommand("docker", args...)
-	}
-	return gcm.client.RunCommand(gcm.composeCmd, args...)
+	return gcm.client.RunCommandTimeout(timeout, gcm.composeCmd[0], args...)
 }
 
 // findProjectRoot finds the project root directory by looking for .phpier.yml file
@@ -1,12 +1,15 @@
 package docker
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"phpier/internal/config"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockClient implements the Docker client interface for testing
@@ -187,3 +190,42 @@ func TestGlobalServiceChecker_Interface(t *testing.T) {
 	var _ GlobalServiceChecker = gcm
 	assert.NotNil(t, gcm)
 }
+
+func TestProjectComposeManager_BuildArgs(t *testing.T) {
+	for _, name := range proxyEnvVars {
+		t.Setenv(name, "")
+	}
+	t.Setenv("HTTP_PROXY", "http://proxy.corp:3128")
+
+	cm := &ProjectComposeManager{
+		projectCfg: &config.ProjectConfig{
+			Name: "demo",
+			Build: config.BuildConfig{
+				Args: map[string]string{"APP_VERSION": "1.2.3"},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"--build-arg", "APP_VERSION=1.2.3",
+		"--build-arg", "HTTP_PROXY=http://proxy.corp:3128",
+	}, cm.buildArgs())
+}
+
+func TestBaseImageFromDockerfile(t *testing.T) {
+	dir := t.TempDir()
+
+	shared := filepath.Join(dir, "shared.Dockerfile")
+	require.NoError(t, os.WriteFile(shared, []byte("# Project image\nFROM phpier-base:8.3-0123456789ab\nCOPY . .\n"), 0644))
+	ref, err := BaseImageFromDockerfile(shared)
+	assert.NoError(t, err)
+	assert.Equal(t, "phpier-base:8.3-0123456789ab", ref)
+
+	legacy := filepath.Join(dir, "legacy.Dockerfile")
+	require.NoError(t, os.WriteFile(legacy, []byte("FROM php:8.3-fpm\n"), 0644))
+	ref, err = BaseImageFromDockerfile(legacy)
+	assert.NoError(t, err)
+	assert.Empty(t, ref)
+
+	assert.Equal(t, "8.3", baseImagePHPVersion("8.3-0123456789ab"))
+}
@@ -0,0 +1,405 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"phpier/internal/config"
+	"phpier/internal/dockerapi"
+	"phpier/internal/errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DatabaseServer is a shared database instance databases and users are created on
+type DatabaseServer struct {
+	Engine      string                       // mysql, postgresql or mariadb
+	Config      config.DatabaseServiceConfig // The instance
+	ContainerID string                       // Set when the container was looked up already
+}
+
+// Container returns the server's container: its ID when known, or its name in the global stack
+func (s DatabaseServer) Container() string {
+	if s.ContainerID != "" {
+		return s.ContainerID
+	}
+	if s.Config.Name != "" {
+		return s.Config.ContainerName()
+	}
+	return "phpier-" + config.DatabaseServiceName(s.Engine)
+}
+
+// DatabaseUser is a user of a database server and the databases it has access to
+type DatabaseUser struct {
+	Name      string   `json:"name"`
+	Databases []string `json:"databases"`
+}
+
+// mysqlSystemUsers are the accounts MySQL and MariaDB create for themselves
+var mysqlSystemUsers = []string{"root", "mysql.sys", "mysql.session", "mysql.infoschema", "mariadb.sys", "healthcheck"}
+
+// systemDatabases are the databases each engine keeps for itself
+var systemDatabases = map[string][]string{
+	"mysql":      {"mysql", "sys", "information_schema", "performance_schema"},
+	"mariadb":    {"mysql", "sys", "information_schema", "performance_schema"},
+	"postgresql": {"postgres", "template0", "template1"},
+}
+
+// CheckDroppableDatabase returns an error for the databases that must not be dropped: the
+// engine's own and the instance's shared default database
+func CheckDroppableDatabase(server DatabaseServer, name string) error {
+	if containsString(systemDatabases[server.Engine], name) {
+		return errors.NewInvalidArgumentsError(fmt.Sprintf("'%s' is a system database of %s and can't be dropped", name, config.DatabaseEngineLabel(server.Engine)))
+	}
+	if server.Config.Database != "" && name == server.Config.Database {
+		return errors.NewInvalidArgumentsError(fmt.Sprintf("'%s' is the shared default database of the %s instance and can't be dropped", name, server.Config.Name)).
+			WithSuggestion("Create a database of its own with 'phpier db create <name>' instead")
+	}
+	return nil
+}
+
+// CreateDatabase creates the database and its user on the server, granting the user all
+// privileges on it. Both are kept when they exist already; the user's password is updated.
+func (c *Client) CreateDatabase(ctx context.Context, server DatabaseServer, db config.ProjectDatabase) error {
+	if err := c.checkDatabaseServer(ctx, server); err != nil {
+		return err
+	}
+
+	if server.Engine == "postgresql" {
+		exists, err := c.postgresExists(ctx, server, "pg_roles", "rolname", db.Username)
+		if err != nil {
+			return err
+		}
+		verb := "CREATE"
+		if exists {
+			verb = "ALTER"
+		}
+		if _, err := c.postgresQuery(ctx, server, "postgres", fmt.Sprintf(`%s ROLE "%s" LOGIN PASSWORD '%s'`, verb, db.Username, sqlString(db.Password))); err != nil {
+			return err
+		}
+
+		exists, err = c.postgresExists(ctx, server, "pg_database", "datname", db.Name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			// CREATE DATABASE can't run in a transaction, so it is a statement of its own
+			if _, err := c.postgresQuery(ctx, server, "postgres", fmt.Sprintf(`CREATE DATABASE "%s" OWNER "%s"`, db.Name, db.Username)); err != nil {
+				return err
+			}
+		}
+		_, err = c.postgresQuery(ctx, server, "postgres", fmt.Sprintf(`GRANT ALL PRIVILEGES ON DATABASE "%s" TO "%s"`, db.Name, db.Username))
+		return err
+	}
+
+	_, err := c.mysqlQuery(ctx, server, fmt.Sprintf(
+		"CREATE DATABASE IF NOT EXISTS `%[1]s`; "+
+			"CREATE USER IF NOT EXISTS '%[2]s'@'%%' IDENTIFIED BY '%[3]s'; "+
+			"ALTER USER '%[2]s'@'%%' IDENTIFIED BY '%[3]s'; "+
+			"GRANT ALL PRIVILEGES ON `%[1]s`.* TO '%[2]s'@'%%'; "+
+			"FLUSH PRIVILEGES;",
+		db.Name, db.Username, sqlString(db.Password)))
+	return err
+}
+
+// DropDatabase drops the database and, unless it is empty, its user from the server
+func (c *Client) DropDatabase(ctx context.Context, server DatabaseServer, name, username string) error {
+	if err := CheckDroppableDatabase(server, name); err != nil {
+		return err
+	}
+	if err := c.checkDatabaseServer(ctx, server); err != nil {
+		return err
+	}
+
+	if server.Engine == "postgresql" {
+		// Connections to the database would make the drop fail
+		terminate := fmt.Sprintf(`SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE datname = '%s' AND pid <> pg_backend_pid()`, sqlString(name))
+		if _, err := c.postgresQuery(ctx, server, "postgres", terminate); err != nil {
+			return err
+		}
+		if _, err := c.postgresQuery(ctx, server, "postgres", fmt.Sprintf(`DROP DATABASE IF EXISTS "%s"`, name)); err != nil {
+			return err
+		}
+		if username == "" || username == server.Config.Username {
+			return nil
+		}
+		_, err := c.postgresQuery(ctx, server, "postgres", fmt.Sprintf(`DROP ROLE IF EXISTS "%s"`, username))
+		return err
+	}
+
+	query := fmt.Sprintf("DROP DATABASE IF EXISTS `%s`;", name)
+	if username != "" && !containsString(mysqlSystemUsers, username) && username != server.Config.Username {
+		query += fmt.Sprintf(" DROP USER IF EXISTS '%s'@'%%';", username)
+	}
+	_, err := c.mysqlQuery(ctx, server, query)
+	return err
+}
+
+// DatabaseUsers lists the users of the server that phpier or projects created, with the
+// databases each has access to
+func (c *Client) DatabaseUsers(ctx context.Context, server DatabaseServer) ([]DatabaseUser, error) {
+	if err := c.checkDatabaseServer(ctx, server); err != nil {
+		return nil, err
+	}
+
+	var output string
+	var err error
+	if server.Engine == "postgresql" {
+		output, err = c.postgresQuery(ctx, server, "postgres",
+			`SELECT r.rolname, COALESCE(string_agg(d.datname, ',' ORDER BY d.datname), '') `+
+				`FROM pg_roles r LEFT JOIN pg_database d ON d.datdba = r.oid AND NOT d.datistemplate `+
+				`WHERE r.rolcanlogin GROUP BY r.rolname ORDER BY r.rolname`)
+	} else {
+		output, err = c.mysqlQuery(ctx, server,
+			"SELECT u.User, IFNULL(GROUP_CONCAT(DISTINCT d.Db ORDER BY d.Db), '') "+
+				"FROM mysql.user u LEFT JOIN mysql.db d ON d.User = u.User "+
+				"GROUP BY u.User ORDER BY u.User")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseDatabaseUsers(output), nil
+}
+
+// RecreateDatabase drops a database and creates it again, empty. The grants of MySQL and
+// MariaDB users outlive the database; a PostgreSQL database keeps its owner, or belongs to the
+// instance's user when it didn't exist yet.
+func (c *Client) RecreateDatabase(ctx context.Context, server DatabaseServer, name string) error {
+	if err := CheckDroppableDatabase(server, name); err != nil {
+		return err
+	}
+	if server.Engine != "postgresql" {
+		_, err := c.mysqlQuery(ctx, server, fmt.Sprintf("DROP DATABASE IF EXISTS `%[1]s`; CREATE DATABASE `%[1]s`;", name))
+		return err
+	}
+
+	exists, err := c.postgresExists(ctx, server, "pg_database", "datname", name)
+	if err != nil {
+		return err
+	}
+	owner := server.Config.Username
+	if exists {
+		if owner, err = c.postgresDatabaseOwner(ctx, server, name); err != nil {
+			return err
+		}
+		if err := c.DropDatabase(ctx, server, name, ""); err != nil {
+			return err
+		}
+	}
+	_, err = c.postgresQuery(ctx, server, "postgres", fmt.Sprintf(`CREATE DATABASE "%s" OWNER "%s"`, name, owner))
+	return err
+}
+
+// DatabaseVersion returns the version of a database server, e.g. "8.0.36" or "16.2"
+func (c *Client) DatabaseVersion(ctx context.Context, server DatabaseServer) (string, error) {
+	if err := c.checkDatabaseServer(ctx, server); err != nil {
+		return "", err
+	}
+	var version string
+	var err error
+	if server.Engine == "postgresql" {
+		version, err = c.postgresQuery(ctx, server, "postgres", "SHOW server_version")
+	} else {
+		version, err = c.mysqlQuery(ctx, server, "SELECT VERSION()")
+	}
+	if err != nil {
+		return "", err
+	}
+	// Drop the build details: "10.11.6-MariaDB-1:10.11.6+maria~ubu2204", "16.2 (Debian 16.2-1.pgdg120+2)"
+	fields := strings.FieldsFunc(version, func(r rune) bool { return r == '-' || r == ' ' })
+	if len(fields) == 0 {
+		return "", fmt.Errorf("the %s server didn't report its version", server.Engine)
+	}
+	return fields[0], nil
+}
+
+// DumpDatabase streams a plain SQL dump of a database to w. The dump leaves out ownership, so
+// it can be imported into another database or server.
+func (c *Client) DumpDatabase(ctx context.Context, server DatabaseServer, database string, w io.Writer) error {
+	var command []string
+	switch server.Engine {
+	case "postgresql":
+		command = []string{"pg_dump", "-U", server.Config.Username, "--no-owner", "--no-privileges", database}
+	case "mariadb":
+		command = []string{"mariadb-dump", "-u", "root", "--single-transaction", "--quick", "--routines", "--triggers", "--events", database}
+	default:
+		command = []string{"mysqldump", "-u", "root", "--single-transaction", "--quick", "--routines", "--triggers", "--events", database}
+	}
+	return c.streamDatabase(ctx, server, command, nil, nil, w)
+}
+
+// RestoreDatabase runs the SQL read from r against a database. On PostgreSQL the objects it
+// creates belong to the database's owner rather than the superuser running the import.
+func (c *Client) RestoreDatabase(ctx context.Context, server DatabaseServer, database string, r io.Reader) error {
+	if server.Engine != "postgresql" {
+		client := "mysql"
+		if server.Engine == "mariadb" {
+			client = "mariadb"
+		}
+		return c.streamDatabase(ctx, server, []string{client, "-u", "root", database}, nil, r, io.Discard)
+	}
+
+	owner, err := c.postgresDatabaseOwner(ctx, server, database)
+	if err != nil {
+		return err
+	}
+	command := []string{"psql", "-U", server.Config.Username, "-d", database, "-v", "ON_ERROR_STOP=1", "-q"}
+	return c.streamDatabase(ctx, server, command, []string{"PGOPTIONS=-c role=" + owner}, r, io.Discard)
+}
+
+// streamDatabase runs a database client in the server's container through the runtime's
+// 'exec -i', so dumps stream through pipes instead of temporary files. The server password
+// and env are passed by name so their values don't show up in the host's process list.
+func (c *Client) streamDatabase(ctx context.Context, server DatabaseServer, command, env []string, stdin io.Reader, stdout io.Writer) error {
+	passwordVar := "MYSQL_PWD"
+	if server.Engine == "postgresql" {
+		passwordVar = "PGPASSWORD"
+	}
+	env = append([]string{passwordVar + "=" + server.Config.Password}, env...)
+
+	args := []string{"exec"}
+	if stdin != nil {
+		args = append(args, "-i")
+	}
+	for _, variable := range env {
+		name, _, _ := strings.Cut(variable, "=")
+		args = append(args, "-e", name)
+	}
+	args = append(append(args, server.Container()), command...)
+
+	logrus.Debugf("Executing in %s: %s", server.Container(), strings.Join(command, " "))
+	cmd := commandContext(ctx, c.CLI(), args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	stderr := &strings.Builder{}
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return databaseClientError(server, command[0], err, stderr.String())
+	}
+	return nil
+}
+
+// postgresDatabaseOwner returns the role that owns a PostgreSQL database
+func (c *Client) postgresDatabaseOwner(ctx context.Context, server DatabaseServer, name string) (string, error) {
+	owner, err := c.postgresQuery(ctx, server, "postgres", fmt.Sprintf(`SELECT pg_get_userbyid(datdba) FROM pg_database WHERE datname = '%s'`, sqlString(name)))
+	if err != nil {
+		return "", err
+	}
+	if owner == "" {
+		return "", fmt.Errorf("database %q does not exist", name)
+	}
+	return owner, nil
+}
+
+// lastLines returns the last n lines of output, where clients put the error that stopped them
+func lastLines(output string, n int) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseDatabaseUsers parses the tab-separated user and comma-separated databases rows the
+// user queries print, leaving out the engines' own accounts
+func parseDatabaseUsers(output string) []DatabaseUser {
+	var users []DatabaseUser
+	for _, row := range strings.Split(output, "\n") {
+		name, databases, _ := strings.Cut(strings.TrimRight(row, "\r"), "\t")
+		if name == "" || containsString(mysqlSystemUsers, name) {
+			continue
+		}
+		user := DatabaseUser{Name: name, Databases: []string{}}
+		for _, db := range strings.Split(databases, ",") {
+			if db = strings.TrimSpace(db); db != "" {
+				// MySQL stores grants with _ escaped, as it is a LIKE pattern
+				user.Databases = append(user.Databases, strings.ReplaceAll(db, `\_`, "_"))
+			}
+		}
+		sort.Strings(user.Databases)
+		users = append(users, user)
+	}
+	return users
+}
+
+// checkDatabaseServer returns an error that says how to start the server when it isn't running
+func (c *Client) checkDatabaseServer(ctx context.Context, server DatabaseServer) error {
+	var running bool
+	var err error
+	if server.ContainerID != "" {
+		running, err = c.IsContainerRunningByID(ctx, server.ContainerID)
+	} else {
+		running, err = c.IsContainerRunning(ctx, server.Container())
+	}
+	if err != nil {
+		return errors.WrapError(errors.ErrorTypeDockerError, "Failed to check the database server", err)
+	}
+	if !running {
+		return errors.NewPhpierError(errors.ErrorTypeContainerNotRunning,
+			fmt.Sprintf("The %s server (%s) is not running", server.Engine, server.Container())).
+			WithSuggestion("Run 'phpier global up' to start the global services")
+	}
+	return nil
+}
+
+// mysqlQuery runs statements on a MySQL or MariaDB server as root and returns the rows as
+// tab-separated lines without headers
+func (c *Client) mysqlQuery(ctx context.Context, server DatabaseServer, query string) (string, error) {
+	client := "mysql"
+	if server.Engine == "mariadb" {
+		client = "mariadb"
+	}
+	// The root password is passed in the environment so it doesn't show up in the process list
+	return c.execDatabase(ctx, server, []string{"MYSQL_PWD=" + server.Config.Password},
+		[]string{client, "-u", "root", "--batch", "--skip-column-names", "-e", query})
+}
+
+// postgresQuery runs a statement on a PostgreSQL server as its superuser and returns the rows
+// as tab-separated lines without headers
+func (c *Client) postgresQuery(ctx context.Context, server DatabaseServer, database, query string) (string, error) {
+	return c.execDatabase(ctx, server, []string{"PGPASSWORD=" + server.Config.Password},
+		[]string{"psql", "-U", server.Config.Username, "-d", database, "-v", "ON_ERROR_STOP=1", "-tA", "-F", "\t", "-c", query})
+}
+
+// postgresExists reports whether a row of a PostgreSQL catalog has column = value
+func (c *Client) postgresExists(ctx context.Context, server DatabaseServer, catalog, column, value string) (bool, error) {
+	output, err := c.postgresQuery(ctx, server, "postgres", fmt.Sprintf(`SELECT 1 FROM %s WHERE %s = '%s'`, catalog, column, sqlString(value)))
+	return strings.TrimSpace(output) == "1", err
+}
+
+// execDatabase runs a database client in the server's container
+func (c *Client) execDatabase(ctx context.Context, server DatabaseServer, env, command []string) (string, error) {
+	logrus.Debugf("Executing in %s: %s", server.Container(), strings.Join(command, " "))
+
+	result, err := c.api.Exec(ctx, server.Container(), dockerapi.ExecOptions{Cmd: command, Env: env})
+	if err != nil {
+		return "", errors.NewCommandFailedError(c.CLI()+" exec", command[:1], err)
+	}
+	if result.ExitCode != 0 {
+		return "", databaseClientError(server, command[0], fmt.Errorf("exit status %d", result.ExitCode), result.Stderr)
+	}
+	return strings.TrimSpace(result.Stdout), nil
+}
+
+// databaseClientError reports a database client that failed, with the error it printed
+func databaseClientError(server DatabaseServer, client string, exitErr error, stderr string) error {
+	output := lastLines(stderr, 5)
+	if output == "" {
+		output = exitErr.Error()
+	}
+	return errors.WrapError(errors.ErrorTypeCommandFailed, fmt.Sprintf("%s failed on the %s server: %s", client, server.Engine, output), exitErr)
+}
+
+// sqlString escapes a value for a single-quoted SQL string
+func sqlString(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
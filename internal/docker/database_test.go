@@ -0,0 +1,223 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"phpier/internal/config"
+	"phpier/internal/dockerapi"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// databaseEngine runs database clients in a running server container, answering queries
+// from stdout
+type databaseEngine struct {
+	replayEngine
+	running bool
+	stdout  map[string]string // Output of the queries containing the key
+	execs   []dockerapi.ExecOptions
+}
+
+func (e *databaseEngine) ContainerList(ctx context.Context, options dockerapi.ContainerListOptions) ([]dockerapi.Container, error) {
+	if !e.running {
+		return nil, nil
+	}
+	return []dockerapi.Container{{ID: "abc", Names: []string{"/" + options.Filters["name"][0]}, State: "running"}}, nil
+}
+
+func (e *databaseEngine) Exec(ctx context.Context, container string, options dockerapi.ExecOptions) (*dockerapi.ExecResult, error) {
+	e.execs = append(e.execs, options)
+	query := options.Cmd[len(options.Cmd)-1]
+	for key, stdout := range e.stdout {
+		if strings.Contains(query, key) {
+			return &dockerapi.ExecResult{Stdout: stdout}, nil
+		}
+	}
+	return &dockerapi.ExecResult{}, nil
+}
+
+func (e *databaseEngine) queries() []string {
+	var queries []string
+	for _, exec := range e.execs {
+		queries = append(queries, exec.Cmd[len(exec.Cmd)-1])
+	}
+	return queries
+}
+
+func TestCreateDatabase_MySQL(t *testing.T) {
+	engine := &databaseEngine{running: true}
+	client := &Client{ctx: context.Background(), api: engine}
+	server := DatabaseServer{Engine: "mysql", Config: config.DatabaseServiceConfig{Username: "phpier", Password: "root'pw"}}
+
+	db := config.ProjectDatabase{Engine: "mysql", Name: "shop", Username: "shop", Password: "secret"}
+	require.NoError(t, client.CreateDatabase(context.Background(), server, db))
+
+	require.Len(t, engine.execs, 1)
+	exec := engine.execs[0]
+	assert.Equal(t, []string{"MYSQL_PWD=root'pw"}, exec.Env, "the root password isn't on the command line")
+	assert.Equal(t, []string{"mysql", "-u", "root"}, exec.Cmd[:3])
+	assert.Equal(t, "CREATE DATABASE IF NOT EXISTS `shop`; "+
+		"CREATE USER IF NOT EXISTS 'shop'@'%' IDENTIFIED BY 'secret'; "+
+		"ALTER USER 'shop'@'%' IDENTIFIED BY 'secret'; "+
+		"GRANT ALL PRIVILEGES ON `shop`.* TO 'shop'@'%'; "+
+		"FLUSH PRIVILEGES;", exec.Cmd[len(exec.Cmd)-1])
+}
+
+func TestCreateDatabase_PostgreSQL(t *testing.T) {
+	// The role exists already, the database doesn't
+	engine := &databaseEngine{running: true, stdout: map[string]string{"FROM pg_roles": "1"}}
+	client := &Client{ctx: context.Background(), api: engine}
+	server := DatabaseServer{Engine: "postgresql", Config: config.DatabaseServiceConfig{Username: "phpier", Password: "phpier"}}
+
+	db := config.ProjectDatabase{Engine: "postgresql", Name: "shop", Username: "shop", Password: "secret"}
+	require.NoError(t, client.CreateDatabase(context.Background(), server, db))
+
+	assert.Equal(t, []string{
+		`SELECT 1 FROM pg_roles WHERE rolname = 'shop'`,
+		`ALTER ROLE "shop" LOGIN PASSWORD 'secret'`,
+		`SELECT 1 FROM pg_database WHERE datname = 'shop'`,
+		`CREATE DATABASE "shop" OWNER "shop"`,
+		`GRANT ALL PRIVILEGES ON DATABASE "shop" TO "shop"`,
+	}, engine.queries())
+	assert.Equal(t, []string{"psql", "-U", "phpier", "-d", "postgres"}, engine.execs[0].Cmd[:5])
+}
+
+func TestCreateDatabase_ServerNotRunning(t *testing.T) {
+	engine := &databaseEngine{}
+	client := &Client{ctx: context.Background(), api: engine}
+
+	err := client.CreateDatabase(context.Background(), DatabaseServer{Engine: "mariadb"}, config.ProjectDatabase{Engine: "mariadb", Name: "shop", Username: "shop"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "phpier-mariadb")
+	assert.Empty(t, engine.execs)
+}
+
+func TestDropDatabase_KeepsServerUser(t *testing.T) {
+	engine := &databaseEngine{running: true}
+	client := &Client{ctx: context.Background(), api: engine}
+	server := DatabaseServer{Engine: "mariadb", Config: config.DatabaseServiceConfig{Username: "phpier"}}
+
+	require.NoError(t, client.DropDatabase(context.Background(), server, "shop", "shop"))
+	require.NoError(t, client.DropDatabase(context.Background(), server, "phpier", "phpier"))
+	assert.Equal(t, []string{
+		"DROP DATABASE IF EXISTS `shop`; DROP USER IF EXISTS 'shop'@'%';",
+		"DROP DATABASE IF EXISTS `phpier`;",
+	}, engine.queries())
+	assert.Equal(t, "mariadb", engine.execs[0].Cmd[0])
+}
+
+func TestDropDatabase_RefusesSystemDatabases(t *testing.T) {
+	engine := &databaseEngine{running: true}
+	client := &Client{ctx: context.Background(), api: engine}
+	mysql := DatabaseServer{Engine: "mysql", Config: config.DatabaseServiceConfig{Name: "mysql", Database: "phpier"}}
+	postgres := DatabaseServer{Engine: "postgresql", Config: config.DatabaseServiceConfig{Name: "postgres", Database: "phpier"}}
+
+	for _, name := range []string{"mysql", "sys", "information_schema", "phpier"} {
+		assert.Error(t, client.DropDatabase(context.Background(), mysql, name, ""), name)
+		assert.Error(t, client.RecreateDatabase(context.Background(), mysql, name), name)
+	}
+	for _, name := range []string{"postgres", "template0", "template1", "phpier"} {
+		assert.Error(t, client.DropDatabase(context.Background(), postgres, name, ""), name)
+		assert.Error(t, client.RecreateDatabase(context.Background(), postgres, name), name)
+	}
+	assert.Empty(t, engine.execs)
+	assert.NoError(t, CheckDroppableDatabase(postgres, "mysql"))
+}
+
+func TestRecreateDatabase_PostgreSQL(t *testing.T) {
+	server := DatabaseServer{Engine: "postgresql", Config: config.DatabaseServiceConfig{Name: "postgres", Username: "phpier"}}
+
+	// An existing database keeps its owner
+	engine := &databaseEngine{running: true, stdout: map[string]string{"SELECT 1 FROM pg_database": "1", "pg_get_userbyid": "shop"}}
+	client := &Client{ctx: context.Background(), api: engine}
+	require.NoError(t, client.RecreateDatabase(context.Background(), server, "shop"))
+	queries := engine.queries()
+	assert.Contains(t, queries, `DROP DATABASE IF EXISTS "shop"`)
+	assert.Equal(t, `CREATE DATABASE "shop" OWNER "shop"`, queries[len(queries)-1])
+
+	// A missing one is created for the instance's user
+	engine = &databaseEngine{running: true}
+	client = &Client{ctx: context.Background(), api: engine}
+	require.NoError(t, client.RecreateDatabase(context.Background(), server, "newdb"))
+	assert.Equal(t, []string{
+		`SELECT 1 FROM pg_database WHERE datname = 'newdb'`,
+		`CREATE DATABASE "newdb" OWNER "phpier"`,
+	}, engine.queries())
+}
+
+func TestParseDatabaseUsers(t *testing.T) {
+	output := "blog\tblog\nmysql.sys\t\nphpier\tphpier\nroot\t\nshop\tshop,shop\\_test\nviewer\t"
+	assert.Equal(t, []DatabaseUser{
+		{Name: "blog", Databases: []string{"blog"}},
+		{Name: "phpier", Databases: []string{"phpier"}},
+		{Name: "shop", Databases: []string{"shop", "shop_test"}},
+		{Name: "viewer", Databases: []string{}},
+	}, parseDatabaseUsers(output))
+}
+
+func TestDumpAndRestoreDatabase(t *testing.T) {
+	dir := t.TempDir()
+	cli := filepath.Join(dir, "docker")
+	// Records its arguments and the passed-through password, and answers like the clients
+	script := `#!/bin/sh
+echo "$@" >> "` + dir + `/args"
+echo "$MYSQL_PWD$PGPASSWORD" >> "` + dir + `/passwords"
+case "$*" in
+  *broken*) echo "ERROR 1049 (42000): Unknown database 'broken'" >&2; exit 1 ;;
+  *mysqldump*) echo "CREATE TABLE users (id INT);" ;;
+  *" mysql "*) cat > "` + dir + `/imported" ;;
+esac
+`
+	require.NoError(t, os.WriteFile(cli, []byte(script), 0o755))
+	client := &Client{ctx: context.Background(), runtime: cliRuntime{cli: cli}}
+	mysql := DatabaseServer{Engine: "mysql", Config: config.DatabaseServiceConfig{Password: "rootpw"}, ContainerID: "abc"}
+
+	var dump strings.Builder
+	require.NoError(t, client.DumpDatabase(context.Background(), mysql, "shop", &dump))
+	assert.Equal(t, "CREATE TABLE users (id INT);\n", dump.String())
+
+	require.NoError(t, client.RestoreDatabase(context.Background(), mysql, "shop", strings.NewReader("INSERT INTO users VALUES (1);\n")))
+	imported, err := os.ReadFile(filepath.Join(dir, "imported"))
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users VALUES (1);\n", string(imported))
+
+	err = client.RestoreDatabase(context.Background(), mysql, "broken", strings.NewReader(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unknown database 'broken'")
+
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(args)), "\n")
+	assert.Equal(t, "exec -e MYSQL_PWD abc mysqldump -u root --single-transaction --quick --routines --triggers --events shop", lines[0])
+	assert.Equal(t, "exec -i -e MYSQL_PWD abc mysql -u root shop", lines[1])
+	assert.NotContains(t, string(args), "rootpw", "the password isn't on the command line")
+
+	passwords, err := os.ReadFile(filepath.Join(dir, "passwords"))
+	require.NoError(t, err)
+	assert.Equal(t, "rootpw", strings.Split(string(passwords), "\n")[0])
+}
+
+func TestDatabaseVersion(t *testing.T) {
+	tests := []struct {
+		engine string
+		output string
+		want   string
+	}{
+		{"mysql", "8.0.36", "8.0.36"},
+		{"mariadb", "10.11.6-MariaDB-1:10.11.6+maria~ubu2204", "10.11.6"},
+		{"postgresql", "16.2 (Debian 16.2-1.pgdg120+2)", "16.2"},
+	}
+	for _, tt := range tests {
+		engine := &databaseEngine{running: true, stdout: map[string]string{"VERSION()": tt.output, "server_version": tt.output}}
+		client := &Client{ctx: context.Background(), api: engine}
+
+		version, err := client.DatabaseVersion(context.Background(), DatabaseServer{Engine: tt.engine})
+		require.NoError(t, err, tt.engine)
+		assert.Equal(t, tt.want, version, tt.engine)
+	}
+}
@@ -0,0 +1,144 @@
+package docker
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"phpier/internal/dockerapi"
+)
+
+// lifecycleActions are the container event actions phpier reports; exec and attach events,
+// which phpier itself causes constantly, are left out
+var lifecycleActions = map[string]bool{
+	"create":        true,
+	"start":         true,
+	"restart":       true,
+	"stop":          true,
+	"kill":          true,
+	"die":           true,
+	"oom":           true,
+	"health_status": true,
+	"pause":         true,
+	"unpause":       true,
+	"destroy":       true,
+}
+
+// ContainerEvent is a lifecycle event of a phpier-managed container
+type ContainerEvent struct {
+	Time      time.Time `json:"time"`
+	Action    string    `json:"action"`           // start, stop, die, oom, health_status, ...
+	Health    string    `json:"health,omitempty"` // healthy, unhealthy or starting, for health_status
+	ExitCode  string    `json:"exit_code,omitempty"`
+	Container string    `json:"container"`
+	Project   string    `json:"project"`
+	Service   string    `json:"service"`
+	Domain    string    `json:"domain,omitempty"`
+}
+
+// Global reports whether the event is about a global service rather than a project container
+func (e ContainerEvent) Global() bool {
+	return e.Project == "phpier"
+}
+
+// EventsOptions selects the events WatchEvents streams
+type EventsOptions struct {
+	Since   time.Time // Replay events since this time
+	Project string    // Only this compose project ("phpier" for global services)
+}
+
+// WatchEvents streams lifecycle events of phpier project containers (phpier.managed=true) and
+// global services until ctx is cancelled. The events channel is closed when the stream ends;
+// a non-nil error is sent on errs first.
+func (c *Client) WatchEvents(ctx context.Context, options EventsOptions) (<-chan ContainerEvent, <-chan error) {
+	projectFilter := "com.docker.compose.project"
+	if options.Project != "" {
+		projectFilter += "=" + options.Project
+	}
+
+	raw, rawErrs := c.api.Events(ctx, dockerapi.EventsOptions{
+		Since:   options.Since,
+		Filters: dockerapi.Filters{"type": {"container"}, "label": {projectFilter}},
+	})
+
+	events := make(chan ContainerEvent)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(events)
+		for event := range raw {
+			containerEvent, ok := newContainerEvent(event)
+			if !ok {
+				continue
+			}
+			select {
+			case events <- containerEvent:
+			case <-ctx.Done():
+				return
+			}
+		}
+		// The engine sends its error before closing the raw stream, and nothing on a clean end
+		select {
+		case err := <-rawErrs:
+			errs <- err
+		default:
+		}
+	}()
+
+	return events, errs
+}
+
+// newContainerEvent converts a daemon event, reporting false for events phpier doesn't show
+func newContainerEvent(event dockerapi.Event) (ContainerEvent, bool) {
+	attributes := event.Actor.Attributes
+	project := attributes["com.docker.compose.project"]
+	if attributes["phpier.managed"] != "true" && project != "phpier" {
+		return ContainerEvent{}, false
+	}
+
+	action, health, _ := strings.Cut(event.Action, ": ")
+	if !lifecycleActions[action] {
+		return ContainerEvent{}, false
+	}
+
+	containerEvent := ContainerEvent{
+		Time:      event.Timestamp(),
+		Action:    action,
+		Health:    health,
+		Container: attributes["name"],
+		Project:   project,
+		Service:   attributes["com.docker.compose.service"],
+		Domain:    routerDomain(attributes),
+	}
+	if action == "die" {
+		containerEvent.ExitCode = attributes["exitCode"]
+	}
+	return containerEvent, true
+}
+
+var hostRulePattern = regexp.MustCompile("Host\\(`([^`]+)`")
+
+// routerDomain returns the host of the container's main Traefik router: the one with the
+// shortest name, so <project> wins over <project>-secure and <project>-devserver
+func routerDomain(labels map[string]string) string {
+	var keys []string
+	for key := range labels {
+		if strings.HasPrefix(key, "traefik.http.routers.") && strings.HasSuffix(key, ".rule") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	for _, key := range keys {
+		if match := hostRulePattern.FindStringSubmatch(labels[key]); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"phpier/internal/dockerapi"
+	"phpier/internal/runtime"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replayEngine is an engine whose event stream replays a fixed list of events
+type replayEngine struct {
+	runtime.Engine
+	events  []dockerapi.Event
+	options dockerapi.EventsOptions
+}
+
+func (e *replayEngine) Events(ctx context.Context, options dockerapi.EventsOptions) (<-chan dockerapi.Event, <-chan error) {
+	e.options = options
+	events := make(chan dockerapi.Event, len(e.events))
+	for _, event := range e.events {
+		events <- event
+	}
+	close(events)
+	return events, make(chan error, 1)
+}
+
+func containerEvent(action string, attributes map[string]string) dockerapi.Event {
+	return dockerapi.Event{Type: "container", Action: action, Actor: dockerapi.Actor{ID: "abc", Attributes: attributes}, Time: 1700000000}
+}
+
+func TestWatchEvents(t *testing.T) {
+	app := map[string]string{
+		"name":                       "shop-app",
+		"com.docker.compose.project": "shop",
+		"com.docker.compose.service": "app",
+		"phpier.managed":             "true",
+		"traefik.http.routers.shop-devserver.rule": "Host(`vite.shop.localhost`)",
+		"traefik.http.routers.shop-secure.rule":    "Host(`shop.localhost`)",
+		"traefik.http.routers.shop.rule":           "Host(`shop.localhost`)",
+	}
+	mysql := map[string]string{"name": "phpier-mysql-1", "com.docker.compose.project": "phpier", "com.docker.compose.service": "mysql"}
+	other := map[string]string{"name": "blog-db-1", "com.docker.compose.project": "blog"}
+
+	engine := &replayEngine{events: []dockerapi.Event{
+		containerEvent("start", app),
+		containerEvent("exec_start: php -v", app),
+		containerEvent("health_status: unhealthy", mysql),
+		containerEvent("die", map[string]string{"name": "phpier-mysql-1", "com.docker.compose.project": "phpier", "exitCode": "137"}),
+		containerEvent("start", other),
+	}}
+	client := &Client{ctx: context.Background(), api: engine}
+
+	events, errs := client.WatchEvents(context.Background(), EventsOptions{Since: time.Unix(1600000000, 0)})
+	var received []ContainerEvent
+	for event := range events {
+		received = append(received, event)
+	}
+	assert.Empty(t, errs)
+
+	assert.Equal(t, dockerapi.Filters{"type": {"container"}, "label": {"com.docker.compose.project"}}, engine.options.Filters)
+	assert.Equal(t, time.Unix(1600000000, 0), engine.options.Since)
+
+	require.Len(t, received, 3, "exec events and containers not managed by phpier are skipped")
+	assert.Equal(t, ContainerEvent{
+		Time: time.Unix(1700000000, 0), Action: "start", Container: "shop-app",
+		Project: "shop", Service: "app", Domain: "shop.localhost",
+	}, received[0])
+	assert.Equal(t, "health_status", received[1].Action)
+	assert.Equal(t, "unhealthy", received[1].Health)
+	assert.True(t, received[1].Global())
+	assert.Equal(t, "137", received[2].ExitCode)
+}
+
+func TestWatchEvents_ProjectFilter(t *testing.T) {
+	engine := &replayEngine{}
+	client := &Client{ctx: context.Background(), api: engine}
+
+	events, _ := client.WatchEvents(context.Background(), EventsOptions{Project: "shop"})
+	for range events {
+	}
+	assert.Equal(t, []string{"com.docker.compose.project=shop"}, engine.options.Filters["label"])
+}
@@ -0,0 +1,183 @@
+package docker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"phpier/internal/config"
+	"phpier/internal/dockerapi"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BaseImageRepository is the repository used for shared per-PHP-version base images
+const BaseImageRepository = "phpier-base"
+
+// BaseImageInfo represents a shared base image present in the local Docker image store
+type BaseImageInfo struct {
+	Tag     string `json:"tag"`
+	ID      string `json:"id"`
+	PHP     string `json:"php"`
+	Size    string `json:"size"`
+	Created string `json:"created"`
+}
+
+// Reference returns the full image reference (phpier-base:<tag>)
+func (b BaseImageInfo) Reference() string {
+	return BaseImageRepository + ":" + b.Tag
+}
+
+// ImageExists checks if an image is present in the local image store
+func (c *Client) ImageExists(ref string) bool {
+	_, err := c.api.ImageInspect(c.ctx, ref)
+	return err == nil
+}
+
+// BuildBaseImage builds a shared base image from its prepared build context
+func (c *Client) BuildBaseImage(ref, contextDir string, buildArgs []string, noCache bool) error {
+	if _, err := os.Stat(filepath.Join(contextDir, "Dockerfile")); err != nil {
+		return fmt.Errorf("base image context for %s not found in %s - run 'phpier build --regenerate' to prepare it", ref, contextDir)
+	}
+
+	args := []string{"build", "-t", ref}
+	if noCache {
+		args = append(args, "--no-cache")
+	}
+	args = append(args, buildArgs...)
+	args = append(args, contextDir)
+
+	logrus.Infof("🧱 Building shared base image %s...", ref)
+	return c.RunCommand(c.CLI(), args...)
+}
+
+// ListBaseImages returns all shared base images in the local image store
+func (c *Client) ListBaseImages() ([]BaseImageInfo, error) {
+	list, err := c.api.ImageList(c.ctx, dockerapi.Filters{"reference": {BaseImageRepository}})
+	if err != nil {
+		return nil, err
+	}
+
+	var images []BaseImageInfo
+	for _, image := range list {
+		for _, ref := range image.RepoTags {
+			tag := strings.TrimPrefix(ref, BaseImageRepository+":")
+			if tag == ref || tag == "<none>" {
+				continue
+			}
+
+			images = append(images, BaseImageInfo{
+				Tag:     tag,
+				ID:      shortImageID(image.ID),
+				PHP:     baseImagePHPVersion(tag),
+				Size:    formatSize(image.Size),
+				Created: formatUptime(time.Since(time.Unix(image.Created, 0))) + " ago",
+			})
+		}
+	}
+
+	return images, nil
+}
+
+// RemoveImage removes an image from the local image store
+func (c *Client) RemoveImage(ref string) error {
+	return c.RunCommand(c.CLI(), "rmi", ref)
+}
+
+// BaseImageFromDockerfile returns the phpier-base image a project Dockerfile builds FROM,
+// or an empty string for Dockerfiles that don't use a shared base image
+func BaseImageFromDockerfile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		if strings.HasPrefix(fields[1], BaseImageRepository+":") {
+			return fields[1], nil
+		}
+		return "", nil
+	}
+
+	return "", scanner.Err()
+}
+
+// BaseImageContextDir returns the build context directory for a phpier-base image reference
+func BaseImageContextDir(ref string) (string, error) {
+	imagesDir, err := config.BaseImagesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(imagesDir, strings.TrimPrefix(ref, BaseImageRepository+":")), nil
+}
+
+// shortImageID returns the 12-character image ID the docker CLI shows
+func shortImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// formatSize formats a byte count in the decimal units the docker CLI uses
+func formatSize(size int64) string {
+	units := []string{"B", "kB", "MB", "GB", "TB"}
+	value := float64(size)
+	unit := 0
+	for value >= 1000 && unit < len(units)-1 {
+		value /= 1000
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d%s", size, units[unit])
+	}
+	return fmt.Sprintf("%.3g%s", value, units[unit])
+}
+
+// baseImagePHPVersion extracts the PHP version from a base image tag (<php>-<hash>)
+func baseImagePHPVersion(tag string) string {
+	if idx := strings.LastIndex(tag, "-"); idx != -1 {
+		return tag[:idx]
+	}
+	return tag
+}
+
+// ensureBaseImage builds the project's shared base image if the Dockerfile uses one and it is missing
+func (cm *ProjectComposeManager) ensureBaseImage() error {
+	ref, err := BaseImageFromDockerfile(filepath.Join(cm.workDir, ".phpier", "Dockerfile.php"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read project Dockerfile: %w", err)
+	}
+	if ref == "" {
+		return nil
+	}
+
+	if cm.client.ImageExists(ref) {
+		logrus.Debugf("Reusing shared base image %s", ref)
+		return nil
+	}
+
+	contextDir, err := BaseImageContextDir(ref)
+	if err != nil {
+		return err
+	}
+	return cm.client.BuildBaseImage(ref, contextDir, cm.buildArgs(), false)
+}
+
+// projectImage returns the image reference compose builds for the project's app service
+func (cm *ProjectComposeManager) projectImage() string {
+	return fmt.Sprintf("phpier-%s:%s", cm.projectCfg.Name, cm.projectCfg.PHP)
+}
@@ -0,0 +1,479 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"phpier/internal/dockerapi"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logLineLimit is the longest log line read; longer lines (minified JSON dumps and the like) are
+// cut there, and the rest of them is skipped
+const logLineLimit = 1024 * 1024
+
+// LogLine is one line a container wrote to stdout or stderr
+type LogLine struct {
+	Time      time.Time `json:"time"`
+	Project   string    `json:"project"`
+	Service   string    `json:"service"`
+	Container string    `json:"container"`
+	Stream    string    `json:"stream"`          // stdout or stderr
+	Level     string    `json:"level,omitempty"` // see DetectLevel
+	Message   string    `json:"message"`
+}
+
+// Global reports whether the line was logged by a global service rather than a project container
+func (l LogLine) Global() bool {
+	return l.Project == "phpier"
+}
+
+// LogContainer is a container whose logs are streamed
+type LogContainer struct {
+	Name    string
+	Project string
+	Service string
+}
+
+// LogsOptions selects the containers and lines StreamLogs reads
+type LogsOptions struct {
+	Projects    []string // Compose projects to read ("phpier" for global services)
+	AllProjects bool     // Read every phpier project (phpier.managed=true)
+	Services    []string // Only these services; empty reads all of them
+	Follow      bool
+	Tail        int    // Lines per container from the end of the logs; 0 reads all of them
+	Since       string // Timestamp or relative time such as 10m, passed to the runtime
+	Filter      LogFilter
+}
+
+// LogContainers returns the containers, running or not, that options select, sorted by
+// project and service
+func (c *Client) LogContainers(ctx context.Context, options LogsOptions) ([]LogContainer, error) {
+	containers, err := c.api.ContainerList(ctx, dockerapi.ContainerListOptions{
+		All:     true,
+		Filters: dockerapi.Filters{"label": {"com.docker.compose.project"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []LogContainer
+	for _, container := range containers {
+		project := container.Labels["com.docker.compose.project"]
+		service := container.Labels["com.docker.compose.service"]
+		selected := containsString(options.Projects, project) ||
+			options.AllProjects && container.Labels["phpier.managed"] == "true"
+		if !selected || len(options.Services) > 0 && !containsString(options.Services, service) {
+			continue
+		}
+		result = append(result, LogContainer{Name: container.Name(), Project: project, Service: service})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Project != result[j].Project {
+			return result[i].Project < result[j].Project
+		}
+		return result[i].Service < result[j].Service
+	})
+	return result, nil
+}
+
+// StreamLogs merges the logs of containers into one stream of lines that pass options.Filter.
+// Without Follow the lines are merged in time order: the output of each container is read to
+// the end and sorted, and a container's line is sent once every other container has a later
+// one or has ended. With Follow they are sent as they are written, until ctx is cancelled. The lines
+// channel is closed when every stream has ended; the first error is sent on errs before that.
+func (c *Client) StreamLogs(ctx context.Context, containers []LogContainer, options LogsOptions) (<-chan LogLine, <-chan error) {
+	lines := make(chan LogLine)
+	errs := make(chan error, 1)
+	report := func(err error) {
+		if err != nil && ctx.Err() == nil {
+			select {
+			case errs <- err:
+			default:
+			}
+		}
+	}
+
+	// Each stream is in time order: a container's sorted output without Follow, and its
+	// stdout and stderr as they are written with it
+	var streams []<-chan LogLine
+	for _, container := range containers {
+		if !options.Follow {
+			stream := make(chan LogLine)
+			streams = append(streams, stream)
+			go func(container LogContainer) {
+				defer close(stream)
+				containerLines, err := c.collectContainerLogs(ctx, container, options)
+				report(err)
+				for _, line := range containerLines {
+					select {
+					case stream <- line:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}(container)
+			continue
+		}
+
+		stdout, stderr := make(chan LogLine), make(chan LogLine)
+		streams = append(streams, stdout, stderr)
+		go func(container LogContainer) {
+			report(c.readContainerLogs(ctx, container, options, stdout, stderr))
+		}(container)
+	}
+
+	go func() {
+		defer close(lines)
+
+		if !options.Follow {
+			mergeLogStreams(ctx, streams, lines)
+			return
+		}
+
+		// Following, lines go out in the order they are written
+		var wg sync.WaitGroup
+		for _, stream := range streams {
+			wg.Add(1)
+			go func(stream <-chan LogLine) {
+				defer wg.Done()
+				for line := range stream {
+					select {
+					case lines <- line:
+					case <-ctx.Done():
+						// Drain so the reader can exit once its command is stopped
+						for range stream {
+						}
+						return
+					}
+				}
+			}(stream)
+		}
+		wg.Wait()
+	}()
+
+	return lines, errs
+}
+
+// mergeLogStreams sends the lines of streams that are each in time order to out in time order,
+// holding back at most one line per stream
+func mergeLogStreams(ctx context.Context, streams []<-chan LogLine, out chan<- LogLine) {
+	heads := make([]*LogLine, len(streams))
+	open := make([]bool, len(streams))
+	for i := range open {
+		open[i] = true
+	}
+	for {
+		// Every open stream needs a line before the earliest one is known
+		for i, stream := range streams {
+			if !open[i] || heads[i] != nil {
+				continue
+			}
+			select {
+			case line, ok := <-stream:
+				if ok {
+					heads[i] = &line
+				} else {
+					open[i] = false
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		earliest := -1
+		for i, head := range heads {
+			if head != nil && (earliest < 0 || head.Time.Before(heads[earliest].Time)) {
+				earliest = i
+			}
+		}
+		if earliest < 0 {
+			return
+		}
+		select {
+		case out <- *heads[earliest]:
+			heads[earliest] = nil
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// collectContainerLogs reads the logs of a container to the end and returns them in time order.
+// stdout and stderr are read independently, so a quiet stream never holds up the other one
+// while the command is blocked writing to a full pipe.
+func (c *Client) collectContainerLogs(ctx context.Context, container LogContainer, options LogsOptions) ([]LogLine, error) {
+	stdout, stderr := make(chan LogLine), make(chan LogLine)
+	var stdoutLines, stderrLines []LogLine
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for line := range stdout {
+			stdoutLines = append(stdoutLines, line)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for line := range stderr {
+			stderrLines = append(stderrLines, line)
+		}
+	}()
+	err := c.readContainerLogs(ctx, container, options, stdout, stderr)
+	wg.Wait()
+
+	containerLines := append(stdoutLines, stderrLines...)
+	sort.SliceStable(containerLines, func(i, j int) bool {
+		return containerLines[i].Time.Before(containerLines[j].Time)
+	})
+	return containerLines, err
+}
+
+// readContainerLogs runs the runtime's logs command for one container and sends the lines of
+// its stdout and stderr, closing each channel when its stream ends
+func (c *Client) readContainerLogs(ctx context.Context, container LogContainer, options LogsOptions, stdoutLines, stderrLines chan<- LogLine) error {
+	args := []string{"logs", "--timestamps"}
+	if options.Follow {
+		args = append(args, "--follow")
+	}
+	if options.Tail > 0 {
+		args = append(args, "--tail", strconv.Itoa(options.Tail))
+	}
+	if options.Since != "" {
+		args = append(args, "--since", options.Since)
+	}
+	args = append(args, container.Name)
+
+	// Each channel is closed by its reader, or here when the command doesn't start
+	closeLines := func() {
+		close(stdoutLines)
+		close(stderrLines)
+	}
+	cmd := commandContext(ctx, c.CLI(), args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		closeLines()
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		closeLines()
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		closeLines()
+		return err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer close(stdoutLines)
+		scanLogLines(ctx, stdout, container, "stdout", options.Filter, stdoutLines)
+	}()
+	go func() {
+		defer wg.Done()
+		defer close(stderrLines)
+		scanLogLines(ctx, stderr, container, "stderr", options.Filter, stderrLines)
+	}()
+	wg.Wait()
+
+	return cmd.Wait()
+}
+
+// scanLogLines sends the lines read from r until it ends or ctx is cancelled
+func scanLogLines(ctx context.Context, r io.Reader, container LogContainer, stream string, filter LogFilter, lines chan<- LogLine) {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	for {
+		text, err := readLogLine(reader)
+		if err != nil {
+			if err != io.EOF {
+				logrus.Debugf("Failed to read %s of %s: %v", stream, container.Name, err)
+			}
+			return
+		}
+		line := parseLogLine(text)
+		line.Project = container.Project
+		line.Service = container.Service
+		line.Container = container.Name
+		line.Stream = stream
+		if !filter.Match(line) {
+			continue
+		}
+		select {
+		case lines <- line:
+		case <-ctx.Done():
+			// Keep reading so the command isn't blocked writing to a full pipe
+			io.Copy(io.Discard, reader)
+			return
+		}
+	}
+}
+
+// readLogLine reads a line without its line ending. Lines longer than logLineLimit are cut
+// there and the rest of them is skipped, so one huge line doesn't end the stream.
+func readLogLine(reader *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if room := logLineLimit - len(line); room > 0 {
+			if len(chunk) > room {
+				chunk = chunk[:room]
+			}
+			line = append(line, chunk...)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return "", err
+		}
+		line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+		return string(line), nil
+	}
+}
+
+// parseLogLine splits the timestamp the runtime puts in front of each line with --timestamps
+// from the message and detects the message's level
+func parseLogLine(text string) LogLine {
+	var line LogLine
+	line.Message = text
+	if timestamp, message, ok := strings.Cut(text, " "); ok {
+		if t, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+			line.Time = t
+			line.Message = message
+		}
+	}
+	line.Level = DetectLevel(line.Message)
+	return line
+}
+
+// logLevels ranks the levels DetectLevel reports
+var logLevels = map[string]int{
+	"debug":    0,
+	"info":     1,
+	"notice":   2,
+	"warning":  3,
+	"error":    4,
+	"critical": 5,
+}
+
+// levelPatterns recognize the level markers of the services phpier runs: nginx and PHP-FPM
+// ("[error]", "WARNING:"), PHP ("PHP Fatal error:"), Monolog ("local.ERROR:"), MySQL and
+// MariaDB ("[ERROR]"), PostgreSQL ("ERROR:"), Redis ("#" warnings), Traefik and other Go
+// services ("level=error", "ERR"), and JSON logs ("level":"error")
+var levelPatterns = []struct {
+	pattern *regexp.Regexp
+	level   string
+}{
+	{regexp.MustCompile(`(?i)\bPHP (Fatal|Parse) error\b|\b(emerg|emergency|alert|crit|critical|fatal|panic)\b[\]:]|level=(fatal|panic)|"level":\s*"(fatal|panic|critical)"|\.(CRITICAL|ALERT|EMERGENCY):`), "critical"},
+	{regexp.MustCompile(`(?i)\[error\]|\bERROR:|level=error|"level":\s*"error"|\.ERROR:|\bERR\b`), "error"},
+	{regexp.MustCompile(`(?i)\bPHP Warning\b|\[(warn|warning)\]|\bWARNING:|level=warn(ing)?|"level":\s*"warn(ing)?"|\.WARNING:|\bWRN\b|^\d+:[A-Z] .* # `), "warning"},
+	{regexp.MustCompile(`(?i)\bPHP (Notice|Deprecated)\b|\[notice\]|\bNOTICE:|\.NOTICE:|"level":\s*"notice"`), "notice"},
+	{regexp.MustCompile(`(?i)\[(debug|trace)\]|\bDEBUG:|level=(debug|trace)|"level":\s*"(debug|trace)"|\.DEBUG:|\bDBG\b`), "debug"},
+	{regexp.MustCompile(`(?i)\[(info|note)\]|\bINFO:|level=info|"level":\s*"info"|\.INFO:|\bINF\b`), "info"},
+}
+
+// DetectLevel returns the severity a log message is marked with (debug, info, notice, warning,
+// error or critical), or an empty string when it has no recognizable marker
+func DetectLevel(message string) string {
+	for _, p := range levelPatterns {
+		if p.pattern.MatchString(message) {
+			return p.level
+		}
+	}
+	return ""
+}
+
+// ValidLogLevel reports whether level is one DetectLevel reports
+func ValidLogLevel(level string) bool {
+	_, ok := logLevels[level]
+	return ok
+}
+
+// LogFilter selects log lines by content and severity
+type LogFilter struct {
+	Pattern  *regexp.Regexp // Only lines whose message matches
+	MinLevel string         // Only lines of this level or worse; lines without a level count as info
+}
+
+// Match reports whether line passes the filter
+func (f LogFilter) Match(line LogLine) bool {
+	if f.Pattern != nil && !f.Pattern.MatchString(line.Message) {
+		return false
+	}
+	if f.MinLevel != "" {
+		level := line.Level
+		if level == "" {
+			level = "info"
+		}
+		if logLevels[level] < logLevels[f.MinLevel] {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainerLogUsage is the size of the log the runtime keeps of a container's output
+type ContainerLogUsage struct {
+	Container string
+	Size      int64  // -1 when the log can't be read from the host (Docker Desktop, root-owned logs)
+	MaxSize   string // Size the runtime rotates the log at; empty when it is unbounded
+}
+
+// ContainerLogUsage reports the size and cap of the runtime's log of each container of a
+// compose project
+func (c *Client) ContainerLogUsage(ctx context.Context, project string) ([]ContainerLogUsage, error) {
+	containers, err := c.api.ContainerList(ctx, dockerapi.ContainerListOptions{
+		All:     true,
+		Filters: dockerapi.Filters{"label": {"com.docker.compose.project=" + project}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var usage []ContainerLogUsage
+	for _, container := range containers {
+		info, err := c.api.ContainerInspect(ctx, container.ID)
+		if err != nil {
+			return nil, err
+		}
+		entry := ContainerLogUsage{
+			Container: container.Name(),
+			Size:      -1,
+			MaxSize:   info.HostConfig.LogConfig.Config["max-size"],
+		}
+		if info.LogPath != "" {
+			if stat, err := os.Stat(info.LogPath); err == nil {
+				entry.Size = stat.Size()
+			}
+		}
+		usage = append(usage, entry)
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Container < usage[j].Container
+	})
+	return usage, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
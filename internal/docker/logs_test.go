@@ -0,0 +1,220 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"phpier/internal/dockerapi"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLevel(t *testing.T) {
+	tests := map[string]string{
+		`2024/05/01 10:00:00 [error] 31#31: *1 FastCGI sent in stderr: "PHP message: oops"`:  "error",
+		`[01-May-2024 10:00:00] WARNING: [pool www] server reached pm.max_children setting`:  "warning",
+		`PHP Fatal error:  Uncaught Error: Call to undefined function foo()`:                 "critical",
+		`PHP Deprecated:  Creation of dynamic property`:                                      "notice",
+		`[2024-05-01 10:00:00] local.ERROR: SQLSTATE[HY000] [2002] Connection refused`:       "error",
+		`2024-05-01T10:00:00.000000Z 0 [ERROR] [MY-010119] [Server] Aborting`:                "error",
+		`2024-05-01 10:00:00.000 UTC [42] ERROR:  relation "users" does not exist`:           "error",
+		`time="2024-05-01T10:00:00Z" level=warning msg="Router uses a non-existent service"`: "warning",
+		`{"level":"debug","msg":"Loading configuration"}`:                                    "debug",
+		`2024-05-01T10:00:00.000000Z 0 [Note] mysqld: ready for connections.`:                "info",
+		`172.18.0.1 - - [01/May/2024:10:00:00 +0000] "GET / HTTP/1.1" 200 612`:               "",
+	}
+	for message, level := range tests {
+		assert.Equal(t, level, DetectLevel(message), message)
+	}
+}
+
+func TestParseLogLine(t *testing.T) {
+	line := parseLogLine("2024-05-01T10:00:00.123456789Z [error] upstream timed out")
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 123456789, time.UTC), line.Time)
+	assert.Equal(t, "[error] upstream timed out", line.Message)
+	assert.Equal(t, "error", line.Level)
+
+	line = parseLogLine("no timestamp here")
+	assert.True(t, line.Time.IsZero())
+	assert.Equal(t, "no timestamp here", line.Message)
+}
+
+func TestReadLogLine(t *testing.T) {
+	long := strings.Repeat("x", logLineLimit+100)
+	reader := bufio.NewReaderSize(strings.NewReader("first\r\n"+long+"\nafter\nlast"), 64*1024)
+
+	var got []string
+	for {
+		line, err := readLogLine(reader)
+		if err != nil {
+			assert.Equal(t, io.EOF, err)
+			break
+		}
+		got = append(got, line)
+	}
+	require.Len(t, got, 4)
+	assert.Equal(t, "first", got[0])
+	assert.Len(t, got[1], logLineLimit, "long lines are cut")
+	assert.Equal(t, []string{"after", "last"}, got[2:], "reading goes on after a long line")
+}
+
+func TestMergeLogStreams(t *testing.T) {
+	at := func(second int, message string) LogLine {
+		return LogLine{Time: time.Date(2024, 5, 1, 10, 0, second, 0, time.UTC), Message: message}
+	}
+	first, second := make(chan LogLine), make(chan LogLine)
+	go func() {
+		first <- at(1, "a")
+		first <- at(4, "d")
+		close(first)
+	}()
+	go func() {
+		second <- at(2, "b")
+		second <- at(3, "c")
+		second <- at(5, "e")
+		close(second)
+	}()
+
+	out := make(chan LogLine)
+	go func() {
+		mergeLogStreams(context.Background(), []<-chan LogLine{first, second}, out)
+		close(out)
+	}()
+	var got []string
+	for line := range out {
+		got = append(got, line.Message)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, got)
+}
+
+func TestLogFilter_Match(t *testing.T) {
+	errorLine := LogLine{Message: "[error] upstream timed out", Level: "error"}
+	plainLine := LogLine{Message: "GET /health 200"}
+
+	assert.True(t, LogFilter{}.Match(plainLine))
+	assert.True(t, LogFilter{MinLevel: "warning"}.Match(errorLine))
+	assert.False(t, LogFilter{MinLevel: "warning"}.Match(plainLine), "lines without a level count as info")
+	assert.True(t, LogFilter{MinLevel: "info"}.Match(plainLine))
+	assert.True(t, LogFilter{Pattern: regexp.MustCompile("health")}.Match(plainLine))
+	assert.False(t, LogFilter{Pattern: regexp.MustCompile("health"), MinLevel: "error"}.Match(plainLine))
+}
+
+// logsEngine lists containers of a project, another project and the global stack
+type logsEngine struct {
+	replayEngine
+}
+
+func (e *logsEngine) ContainerList(ctx context.Context, options dockerapi.ContainerListOptions) ([]dockerapi.Container, error) {
+	return []dockerapi.Container{
+		{Names: []string{"/shop-app"}, Labels: map[string]string{
+			"com.docker.compose.project": "shop", "com.docker.compose.service": "app", "phpier.managed": "true"}},
+		{Names: []string{"/blog-app"}, Labels: map[string]string{
+			"com.docker.compose.project": "blog", "com.docker.compose.service": "app", "phpier.managed": "true"}},
+		{Names: []string{"/phpier-mysql"}, Labels: map[string]string{
+			"com.docker.compose.project": "phpier", "com.docker.compose.service": "mysql"}},
+		{Names: []string{"/other-web-1"}, Labels: map[string]string{
+			"com.docker.compose.project": "other", "com.docker.compose.service": "web"}},
+	}, nil
+}
+
+func TestLogContainers(t *testing.T) {
+	client := &Client{ctx: context.Background(), api: &logsEngine{}}
+
+	containers, err := client.LogContainers(context.Background(), LogsOptions{Projects: []string{"shop", "phpier"}})
+	require.NoError(t, err)
+	assert.Equal(t, []LogContainer{
+		{Name: "phpier-mysql", Project: "phpier", Service: "mysql"},
+		{Name: "shop-app", Project: "shop", Service: "app"},
+	}, containers)
+
+	containers, err = client.LogContainers(context.Background(), LogsOptions{AllProjects: true, Services: []string{"app"}})
+	require.NoError(t, err)
+	require.Len(t, containers, 2)
+	assert.Equal(t, "blog-app", containers[0].Name)
+	assert.Equal(t, "shop-app", containers[1].Name)
+}
+
+func TestStreamLogs(t *testing.T) {
+	dir := t.TempDir()
+	cli := filepath.Join(dir, "docker")
+	script := `#!/bin/sh
+for name; do :; done
+if [ "$name" = "shop-app" ]; then
+  echo "2024-05-01T10:00:02.000000000Z GET /index.php 200"
+  echo "2024-05-01T10:00:00.000000000Z PHP Fatal error:  Uncaught Exception" >&2
+else
+  echo "2024-05-01T10:00:01.000000000Z [Note] mysqld: ready for connections."
+fi
+`
+	require.NoError(t, os.WriteFile(cli, []byte(script), 0o755))
+	client := &Client{ctx: context.Background(), runtime: cliRuntime{cli: cli}}
+
+	containers := []LogContainer{
+		{Name: "shop-app", Project: "shop", Service: "app"},
+		{Name: "phpier-mysql", Project: "phpier", Service: "mysql"},
+	}
+	lines, errs := client.StreamLogs(context.Background(), containers, LogsOptions{Tail: 10})
+
+	var got []LogLine
+	for line := range lines {
+		got = append(got, line)
+	}
+	require.Len(t, got, 3)
+	assert.Equal(t, "PHP Fatal error:  Uncaught Exception", got[0].Message, "lines are sorted by time")
+	assert.Equal(t, "stderr", got[0].Stream)
+	assert.Equal(t, "critical", got[0].Level)
+	assert.Equal(t, "mysql", got[1].Service)
+	assert.True(t, got[1].Global())
+	assert.Equal(t, "GET /index.php 200", got[2].Message)
+	assert.Equal(t, "shop-app", got[2].Container)
+	assert.Empty(t, errs)
+
+	lines, _ = client.StreamLogs(context.Background(), containers, LogsOptions{Filter: LogFilter{MinLevel: "error"}})
+	got = nil
+	for line := range lines {
+		got = append(got, line)
+	}
+	require.Len(t, got, 1)
+	assert.Equal(t, "app", got[0].Service)
+}
+
+func TestStreamLogs_QuietStderr(t *testing.T) {
+	dir := t.TempDir()
+	cli := filepath.Join(dir, "docker")
+	// Far more stdout than a pipe buffer holds, while stderr stays open and quiet
+	script := `#!/bin/sh
+echo "2024-05-01T10:00:00.000000000Z starting" >&2
+i=0
+while [ $i -lt 30000 ]; do
+  echo "2024-05-01T10:00:01.000000000Z request $i"
+  i=$((i+1))
+done
+`
+	require.NoError(t, os.WriteFile(cli, []byte(script), 0o755))
+	client := &Client{ctx: context.Background(), runtime: cliRuntime{cli: cli}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	lines, errs := client.StreamLogs(ctx, []LogContainer{{Name: "shop-app", Project: "shop", Service: "app"}}, LogsOptions{})
+
+	count := 0
+	var first LogLine
+	for line := range lines {
+		if count == 0 {
+			first = line
+		}
+		count++
+	}
+	require.NoError(t, ctx.Err(), "the stream finished")
+	assert.Equal(t, 30001, count)
+	assert.Equal(t, "starting", first.Message)
+	assert.Empty(t, errs)
+}
@@ -2,13 +2,15 @@ package docker
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"phpier/internal/dockerapi"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -63,13 +65,27 @@ func (c *Client) GetPhpierServices(ctx context.Context, filter *ServicesFilter)
 		return nil, fmt.Errorf("failed to get containers: %w", err)
 	}
 
-	for _, container := range containers {
-		serviceInfo, err := c.getServiceInfo(ctx, container)
-		if err != nil {
-			logrus.Warnf("Failed to get info for container %s: %v", container, err)
-			continue
+	// Inspect requests are cheap over the API socket, so run them side by side
+	results := make([]*ServiceInfo, len(containers))
+	var wg sync.WaitGroup
+	for i, container := range containers {
+		wg.Add(1)
+		go func(i int, container string) {
+			defer wg.Done()
+			serviceInfo, err := c.getServiceInfo(ctx, container)
+			if err != nil {
+				logrus.Warnf("Failed to get info for container %s: %v", container, err)
+				return
+			}
+			results[i] = &serviceInfo
+		}(i, container)
+	}
+	wg.Wait()
+
+	for _, serviceInfo := range results {
+		if serviceInfo != nil {
+			services = append(services, *serviceInfo)
 		}
-		services = append(services, serviceInfo)
 	}
 
 	// Sort services by project, then by service type
@@ -85,40 +101,29 @@ func (c *Client) GetPhpierServices(ctx context.Context, filter *ServicesFilter)
 
 // getContainersByFilter gets containers matching the filter criteria
 func (c *Client) getContainersByFilter(ctx context.Context, filter *ServicesFilter) ([]string, error) {
-	args := []string{"ps", "-a", "--format", "{{.Names}}"}
-
 	// Add filter for phpier containers
-	args = append(args, "--filter", "label=com.docker.compose.project")
+	filters := dockerapi.Filters{"label": {"com.docker.compose.project"}}
 
 	// Add project filter if specified
 	if filter != nil && filter.Project != "" {
-		args = append(args, "--filter", fmt.Sprintf("label=com.docker.compose.project=%s", filter.Project))
+		filters["label"] = []string{fmt.Sprintf("com.docker.compose.project=%s", filter.Project)}
 	}
 
 	// Add status filter if specified
 	if filter != nil && filter.Status != "" {
-		args = append(args, "--filter", fmt.Sprintf("status=%s", filter.Status))
+		filters["status"] = []string{filter.Status}
 	}
 
-	output, err := c.RunCommandOutput("docker", args...)
+	list, err := c.api.ContainerList(ctx, dockerapi.ContainerListOptions{All: true, Filters: filters})
 	if err != nil {
 		return nil, err
 	}
 
-	if strings.TrimSpace(output) == "" {
-		return []string{}, nil
-	}
-
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-	var containers []string
-
-	for _, line := range lines {
-		containerName := strings.TrimSpace(line)
-		if containerName != "" {
-			// Filter for phpier-related containers
-			if c.isPhpierContainer(containerName) {
-				containers = append(containers, containerName)
-			}
+	containers := []string{}
+	for _, container := range list {
+		// Filter for phpier-related containers
+		if containerName := container.Name(); containerName != "" && c.isPhpierContainer(containerName) {
+			containers = append(containers, containerName)
 		}
 	}
 
@@ -169,101 +174,50 @@ func (c *Client) isPhpierContainer(containerName string) bool {
 // getServiceInfo gets detailed information about a specific container
 func (c *Client) getServiceInfo(ctx context.Context, containerName string) (ServiceInfo, error) {
 	// Get container inspect information
-	inspectOutput, err := c.RunCommandOutput("docker", "inspect", containerName)
+	container, err := c.api.ContainerInspect(ctx, containerName)
 	if err != nil {
 		return ServiceInfo{}, fmt.Errorf("failed to inspect container %s: %w", containerName, err)
 	}
 
-	var containers []map[string]interface{}
-	if err := json.Unmarshal([]byte(inspectOutput), &containers); err != nil {
-		return ServiceInfo{}, fmt.Errorf("failed to parse inspect output: %w", err)
-	}
-
-	if len(containers) == 0 {
-		return ServiceInfo{}, fmt.Errorf("no container data found")
-	}
-
-	container := containers[0]
-
 	// Extract basic information
 	serviceInfo := ServiceInfo{
 		Name:   containerName,
+		Image:  container.Config.Image,
+		Status: container.State.Status,
+		State:  container.State.Status,
 		Labels: make(map[string]string),
 	}
-
-	// Extract image
-	if config, ok := container["Config"].(map[string]interface{}); ok {
-		if image, ok := config["Image"].(string); ok {
-			serviceInfo.Image = image
-		}
-
-		// Extract labels
-		if labels, ok := config["Labels"].(map[string]interface{}); ok {
-			for k, v := range labels {
-				if str, ok := v.(string); ok {
-					serviceInfo.Labels[k] = str
-				}
-			}
-		}
+	for k, v := range container.Config.Labels {
+		serviceInfo.Labels[k] = v
 	}
 
-	// Extract state information
-	if state, ok := container["State"].(map[string]interface{}); ok {
-		if status, ok := state["Status"].(string); ok {
-			serviceInfo.Status = status
-			serviceInfo.State = status
-		}
-
-		if health, ok := state["Health"].(map[string]interface{}); ok {
-			if healthStatus, ok := health["Status"].(string); ok {
-				serviceInfo.Health = healthStatus
-			}
-		}
-
-		// Extract timestamps
-		if createdStr, ok := state["StartedAt"].(string); ok && createdStr != "" {
-			if startedAt, err := time.Parse(time.RFC3339Nano, createdStr); err == nil {
-				serviceInfo.StartedAt = startedAt
-				if serviceInfo.Status == "running" {
-					serviceInfo.Uptime = formatUptime(time.Since(startedAt))
-				}
-			}
-		}
+	if container.State.Health != nil {
+		serviceInfo.Health = container.State.Health.Status
 	}
 
-	// Extract creation time
-	if createdStr, ok := container["Created"].(string); ok && createdStr != "" {
-		if created, err := time.Parse(time.RFC3339Nano, createdStr); err == nil {
-			serviceInfo.Created = created
+	// Extract timestamps
+	if startedAt, err := time.Parse(time.RFC3339Nano, container.State.StartedAt); err == nil {
+		serviceInfo.StartedAt = startedAt
+		if serviceInfo.Status == "running" {
+			serviceInfo.Uptime = formatUptime(time.Since(startedAt))
 		}
 	}
-
-	// Extract port mappings
-	if networkSettings, ok := container["NetworkSettings"].(map[string]interface{}); ok {
-		if ports, ok := networkSettings["Ports"].(map[string]interface{}); ok {
-			serviceInfo.Ports = extractPorts(ports)
-		}
-
-		// Extract networks
-		if networks, ok := networkSettings["Networks"].(map[string]interface{}); ok {
-			for networkName := range networks {
-				serviceInfo.Networks = append(serviceInfo.Networks, networkName)
-			}
-		}
+	if created, err := time.Parse(time.RFC3339Nano, container.Created); err == nil {
+		serviceInfo.Created = created
 	}
 
-	// Extract mounts
-	if mounts, ok := container["Mounts"].([]interface{}); ok {
-		serviceInfo.Mounts = extractMounts(mounts)
+	// Extract port mappings and networks
+	serviceInfo.Ports = extractPorts(container.NetworkSettings.Ports)
+	for networkName := range container.NetworkSettings.Networks {
+		serviceInfo.Networks = append(serviceInfo.Networks, networkName)
 	}
+	sort.Strings(serviceInfo.Networks)
+
+	serviceInfo.Mounts = extractMounts(container.Mounts)
 
 	// Extract project and service information from labels
-	if project, ok := serviceInfo.Labels["com.docker.compose.project"]; ok {
-		serviceInfo.Project = project
-	}
-	if service, ok := serviceInfo.Labels["com.docker.compose.service"]; ok {
-		serviceInfo.Service = service
-	}
+	serviceInfo.Project = serviceInfo.Labels["com.docker.compose.project"]
+	serviceInfo.Service = serviceInfo.Labels["com.docker.compose.service"]
 
 	// Generate service URL if applicable
 	serviceInfo.ServiceURL = c.generateServiceURL(serviceInfo)
@@ -271,66 +225,47 @@ func (c *Client) getServiceInfo(ctx context.Context, containerName string) (Serv
 	return serviceInfo, nil
 }
 
-// extractPorts extracts port mappings from Docker inspect output
-func extractPorts(ports map[string]interface{}) []PortMapping {
+// extractPorts extracts host port mappings from the inspected container ports
+func extractPorts(ports map[string][]dockerapi.PortBinding) []PortMapping {
 	var portMappings []PortMapping
 
 	for containerPort, bindings := range ports {
-		if bindingList, ok := bindings.([]interface{}); ok && len(bindingList) > 0 {
-			for _, binding := range bindingList {
-				if bindingMap, ok := binding.(map[string]interface{}); ok {
-					hostPort, _ := bindingMap["HostPort"].(string)
-					hostIP, _ := bindingMap["HostIp"].(string)
-
-					parts := strings.Split(containerPort, "/")
-					port := parts[0]
-					protocol := "tcp"
-					if len(parts) > 1 {
-						protocol = parts[1]
-					}
-
-					mapping := PortMapping{
-						ContainerPort: port,
-						HostPort:      hostPort,
-						Protocol:      protocol,
-					}
-
-					if hostIP != "" && hostIP != "0.0.0.0" {
-						mapping.HostPort = fmt.Sprintf("%s:%s", hostIP, hostPort)
-					}
-
-					portMappings = append(portMappings, mapping)
-				}
+		for _, binding := range bindings {
+			parts := strings.Split(containerPort, "/")
+			port := parts[0]
+			protocol := "tcp"
+			if len(parts) > 1 {
+				protocol = parts[1]
+			}
+
+			mapping := PortMapping{
+				ContainerPort: port,
+				HostPort:      binding.HostPort,
+				Protocol:      protocol,
 			}
+
+			if binding.HostIP != "" && binding.HostIP != "0.0.0.0" {
+				mapping.HostPort = fmt.Sprintf("%s:%s", binding.HostIP, binding.HostPort)
+			}
+
+			portMappings = append(portMappings, mapping)
 		}
 	}
 
 	return portMappings
 }
 
-// extractMounts extracts mount information from Docker inspect output
-func extractMounts(mounts []interface{}) []MountInfo {
+// extractMounts extracts mount information from the inspected container mounts
+func extractMounts(mounts []dockerapi.MountPoint) []MountInfo {
 	var mountInfo []MountInfo
 
 	for _, mount := range mounts {
-		if mountMap, ok := mount.(map[string]interface{}); ok {
-			info := MountInfo{}
-
-			if source, ok := mountMap["Source"].(string); ok {
-				info.Source = source
-			}
-			if destination, ok := mountMap["Destination"].(string); ok {
-				info.Destination = destination
-			}
-			if mountType, ok := mountMap["Type"].(string); ok {
-				info.Type = mountType
-			}
-			if mode, ok := mountMap["Mode"].(string); ok {
-				info.Mode = mode
-			}
-
-			mountInfo = append(mountInfo, info)
-		}
+		mountInfo = append(mountInfo, MountInfo{
+			Source:      mount.Source,
+			Destination: mount.Destination,
+			Type:        mount.Type,
+			Mode:        mount.Mode,
+		})
 	}
 
 	return mountInfo
@@ -414,22 +349,17 @@ func (c *Client) GetProjectServices(ctx context.Context, projectName string) ([]
 func (c *Client) ServiceExists(ctx context.Context, projectName, serviceName string) (bool, error) {
 	containerName := fmt.Sprintf("%s-%s-1", projectName, serviceName)
 
-	output, err := c.RunCommandOutput("docker", "ps", "-q", "--filter", fmt.Sprintf("name=%s", containerName))
-	if err != nil {
-		return false, err
-	}
-
-	return strings.TrimSpace(output) != "", nil
+	return c.IsContainerRunning(ctx, containerName)
 }
 
 // GetServiceStatus returns the status of a specific service
 func (c *Client) GetServiceStatus(ctx context.Context, projectName, serviceName string) (string, error) {
 	containerName := fmt.Sprintf("%s-%s-1", projectName, serviceName)
 
-	output, err := c.RunCommandOutput("docker", "inspect", "--format", "{{.State.Status}}", containerName)
+	container, err := c.api.ContainerInspect(ctx, containerName)
 	if err != nil {
 		return "not found", nil
 	}
 
-	return strings.TrimSpace(output), nil
+	return container.State.Status, nil
 }
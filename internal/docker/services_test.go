@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"phpier/internal/dockerapi"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -187,18 +189,13 @@ func TestGenerateServiceURL(t *testing.T) {
 func TestExtractPorts(t *testing.T) {
 	tests := []struct {
 		name     string
-		ports    map[string]interface{}
+		ports    map[string][]dockerapi.PortBinding
 		expected []PortMapping
 	}{
 		{
 			name: "single port mapping",
-			ports: map[string]interface{}{
-				"80/tcp": []interface{}{
-					map[string]interface{}{
-						"HostIp":   "0.0.0.0",
-						"HostPort": "8080",
-					},
-				},
+			ports: map[string][]dockerapi.PortBinding{
+				"80/tcp": {{HostIP: "0.0.0.0", HostPort: "8080"}},
 			},
 			expected: []PortMapping{
 				{
@@ -210,19 +207,9 @@ func TestExtractPorts(t *testing.T) {
 		},
 		{
 			name: "multiple port mappings",
-			ports: map[string]interface{}{
-				"80/tcp": []interface{}{
-					map[string]interface{}{
-						"HostIp":   "0.0.0.0",
-						"HostPort": "8080",
-					},
-				},
-				"443/tcp": []interface{}{
-					map[string]interface{}{
-						"HostIp":   "0.0.0.0",
-						"HostPort": "8443",
-					},
-				},
+			ports: map[string][]dockerapi.PortBinding{
+				"80/tcp":  {{HostIP: "0.0.0.0", HostPort: "8080"}},
+				"443/tcp": {{HostIP: "0.0.0.0", HostPort: "8443"}},
 			},
 			expected: []PortMapping{
 				{
@@ -237,9 +224,23 @@ func TestExtractPorts(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "unpublished port and host IP binding",
+			ports: map[string][]dockerapi.PortBinding{
+				"9000/tcp": nil,
+				"53/udp":   {{HostIP: "127.0.0.1", HostPort: "5353"}},
+			},
+			expected: []PortMapping{
+				{
+					ContainerPort: "53",
+					HostPort:      "127.0.0.1:5353",
+					Protocol:      "udp",
+				},
+			},
+		},
 		{
 			name:     "no port mappings",
-			ports:    map[string]interface{}{},
+			ports:    map[string][]dockerapi.PortBinding{},
 			expected: []PortMapping{},
 		},
 	}
@@ -255,18 +256,13 @@ func TestExtractPorts(t *testing.T) {
 func TestExtractMounts(t *testing.T) {
 	tests := []struct {
 		name     string
-		mounts   []interface{}
+		mounts   []dockerapi.MountPoint
 		expected []MountInfo
 	}{
 		{
 			name: "single mount",
-			mounts: []interface{}{
-				map[string]interface{}{
-					"Source":      "/host/path",
-					"Destination": "/container/path",
-					"Type":        "bind",
-					"Mode":        "rw",
-				},
+			mounts: []dockerapi.MountPoint{
+				{Source: "/host/path", Destination: "/container/path", Type: "bind", Mode: "rw"},
 			},
 			expected: []MountInfo{
 				{
@@ -279,19 +275,9 @@ func TestExtractMounts(t *testing.T) {
 		},
 		{
 			name: "multiple mounts",
-			mounts: []interface{}{
-				map[string]interface{}{
-					"Source":      "/host/path1",
-					"Destination": "/container/path1",
-					"Type":        "bind",
-					"Mode":        "rw",
-				},
-				map[string]interface{}{
-					"Source":      "/host/path2",
-					"Destination": "/container/path2",
-					"Type":        "volume",
-					"Mode":        "ro",
-				},
+			mounts: []dockerapi.MountPoint{
+				{Source: "/host/path1", Destination: "/container/path1", Type: "bind", Mode: "rw"},
+				{Source: "/host/path2", Destination: "/container/path2", Type: "volume", Mode: "ro"},
 			},
 			expected: []MountInfo{
 				{
@@ -310,7 +296,7 @@ func TestExtractMounts(t *testing.T) {
 		},
 		{
 			name:     "no mounts",
-			mounts:   []interface{}{},
+			mounts:   []dockerapi.MountPoint{},
 			expected: []MountInfo{},
 		},
 	}
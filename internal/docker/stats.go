@@ -0,0 +1,83 @@
+package docker
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"phpier/internal/dockerapi"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ContainerStats is the resource usage of a phpier container
+type ContainerStats struct {
+	Name    string           `json:"name"`
+	Project string           `json:"project"`
+	Service string           `json:"service"`
+	State   string           `json:"state"`
+	Stats   *dockerapi.Stats `json:"stats,omitempty"` // nil when the container isn't running
+}
+
+// StatsOptions selects the containers GetPhpierStats samples
+type StatsOptions struct {
+	Project string // Only this compose project ("phpier" for global services)
+	All     bool   // Include containers that aren't running
+}
+
+// GetPhpierStats samples the resource usage of phpier project containers (phpier.managed=true)
+// and global services, sorted by project and service. Each sample takes about a second, so
+// the containers are sampled side by side.
+func (c *Client) GetPhpierStats(ctx context.Context, options StatsOptions) ([]ContainerStats, error) {
+	projectFilter := "com.docker.compose.project"
+	if options.Project != "" {
+		projectFilter += "=" + options.Project
+	}
+	containers, err := c.api.ContainerList(ctx, dockerapi.ContainerListOptions{
+		All:     options.All,
+		Filters: dockerapi.Filters{"label": {projectFilter}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ContainerStats
+	for _, container := range containers {
+		project := container.Labels["com.docker.compose.project"]
+		if container.Labels["phpier.managed"] != "true" && project != "phpier" {
+			continue
+		}
+		results = append(results, ContainerStats{
+			Name:    container.Name(),
+			Project: project,
+			Service: container.Labels["com.docker.compose.service"],
+			State:   container.State,
+		})
+	}
+
+	var wg sync.WaitGroup
+	for i := range results {
+		if results[i].State != "running" {
+			continue
+		}
+		wg.Add(1)
+		go func(result *ContainerStats) {
+			defer wg.Done()
+			stats, err := c.api.ContainerStats(ctx, result.Name)
+			if err != nil {
+				logrus.Debugf("Failed to get stats of %s: %v", result.Name, err)
+				return
+			}
+			result.Stats = stats
+		}(&results[i])
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Project != results[j].Project {
+			return results[i].Project < results[j].Project
+		}
+		return results[i].Service < results[j].Service
+	})
+	return results, nil
+}
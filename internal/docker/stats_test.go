@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"phpier/internal/dockerapi"
+	"phpier/internal/runtime"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statsEngine lists a fixed set of containers and reports the same sample for each
+type statsEngine struct {
+	runtime.Engine
+	containers []dockerapi.Container
+	options    dockerapi.ContainerListOptions
+}
+
+func (e *statsEngine) ContainerList(ctx context.Context, options dockerapi.ContainerListOptions) ([]dockerapi.Container, error) {
+	e.options = options
+	return e.containers, nil
+}
+
+func (e *statsEngine) ContainerStats(ctx context.Context, container string) (*dockerapi.Stats, error) {
+	return &dockerapi.Stats{CPUPercent: 5, PIDs: uint64(len(container))}, nil
+}
+
+func TestGetPhpierStats(t *testing.T) {
+	engine := &statsEngine{containers: []dockerapi.Container{
+		{Names: []string{"/shop-app"}, State: "running", Labels: map[string]string{
+			"com.docker.compose.project": "shop", "com.docker.compose.service": "app", "phpier.managed": "true"}},
+		{Names: []string{"/phpier-redis"}, State: "running", Labels: map[string]string{
+			"com.docker.compose.project": "phpier", "com.docker.compose.service": "redis"}},
+		{Names: []string{"/phpier-mysql"}, State: "exited", Labels: map[string]string{
+			"com.docker.compose.project": "phpier", "com.docker.compose.service": "mysql"}},
+		{Names: []string{"/other-db-1"}, State: "running", Labels: map[string]string{
+			"com.docker.compose.project": "other", "com.docker.compose.service": "db"}},
+	}}
+	client := &Client{ctx: context.Background(), api: engine}
+
+	stats, err := client.GetPhpierStats(context.Background(), StatsOptions{All: true})
+	require.NoError(t, err)
+	assert.True(t, engine.options.All)
+	assert.Equal(t, []string{"com.docker.compose.project"}, engine.options.Filters["label"])
+
+	require.Len(t, stats, 3, "containers of other compose projects are left out")
+	assert.Equal(t, "phpier-mysql", stats[0].Name)
+	assert.Nil(t, stats[0].Stats, "stopped containers have no sample")
+	assert.Equal(t, "phpier-redis", stats[1].Name)
+	assert.Equal(t, uint64(len("phpier-redis")), stats[1].Stats.PIDs)
+	assert.Equal(t, "shop", stats[2].Project)
+	assert.Equal(t, 5.0, stats[2].Stats.CPUPercent)
+
+	_, err = client.GetPhpierStats(context.Background(), StatsOptions{Project: "shop"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"com.docker.compose.project=shop"}, engine.options.Filters["label"])
+}
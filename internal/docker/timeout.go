@@ -0,0 +1,131 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+
+	"phpier/internal/dockerapi"
+	"phpier/internal/errors"
+	"phpier/internal/runtime"
+)
+
+// interruptGrace is how long a command may take to exit after it was sent SIGINT before it is killed
+const interruptGrace = 10 * time.Second
+
+// withTimeout returns ctx bounded by timeout, or ctx unchanged when timeout is 0
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// interruption returns the error for an operation stopped because ctx ended, or nil while ctx is live
+func interruption(ctx context.Context, operation string, timeout time.Duration, cause error) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return errors.NewCommandTimeoutError(operation, timeout, cause)
+	case context.Canceled:
+		return errors.NewInterruptedError(operation)
+	}
+	return nil
+}
+
+// commandContext creates a command that is sent SIGINT rather than killed when ctx ends, so
+// compose can stop the containers it was starting. It is killed if it is still running after
+// interruptGrace.
+func commandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = interruptGrace
+	return cmd
+}
+
+// timeoutEngine bounds every engine request by the API timeout and reports requests that ran
+// out of time or were interrupted as phpier errors. Event streams are not bounded.
+type timeoutEngine struct {
+	engine  runtime.Engine
+	timeout time.Duration
+}
+
+// do runs request with a bounded context
+func (e *timeoutEngine) do(ctx context.Context, operation string, request func(ctx context.Context) error) error {
+	ctx, cancel := withTimeout(ctx, e.timeout)
+	defer cancel()
+
+	err := request(ctx)
+	if err != nil {
+		if ctxErr := interruption(ctx, operation, e.timeout, err); ctxErr != nil {
+			return ctxErr
+		}
+	}
+	return err
+}
+
+func (e *timeoutEngine) Ping(ctx context.Context) error {
+	return e.do(ctx, "ping", e.engine.Ping)
+}
+
+func (e *timeoutEngine) ContainerList(ctx context.Context, options dockerapi.ContainerListOptions) (containers []dockerapi.Container, err error) {
+	err = e.do(ctx, "list containers", func(ctx context.Context) error {
+		containers, err = e.engine.ContainerList(ctx, options)
+		return err
+	})
+	return containers, err
+}
+
+func (e *timeoutEngine) ContainerInspect(ctx context.Context, container string) (info *dockerapi.ContainerJSON, err error) {
+	err = e.do(ctx, "inspect container "+container, func(ctx context.Context) error {
+		info, err = e.engine.ContainerInspect(ctx, container)
+		return err
+	})
+	return info, err
+}
+
+func (e *timeoutEngine) ImageList(ctx context.Context, filters dockerapi.Filters) (images []dockerapi.Image, err error) {
+	err = e.do(ctx, "list images", func(ctx context.Context) error {
+		images, err = e.engine.ImageList(ctx, filters)
+		return err
+	})
+	return images, err
+}
+
+func (e *timeoutEngine) ImageInspect(ctx context.Context, ref string) (image *dockerapi.ImageInspect, err error) {
+	err = e.do(ctx, "inspect image "+ref, func(ctx context.Context) error {
+		image, err = e.engine.ImageInspect(ctx, ref)
+		return err
+	})
+	return image, err
+}
+
+func (e *timeoutEngine) NetworkInspect(ctx context.Context, network string) (info *dockerapi.Network, err error) {
+	err = e.do(ctx, "inspect network "+network, func(ctx context.Context) error {
+		info, err = e.engine.NetworkInspect(ctx, network)
+		return err
+	})
+	return info, err
+}
+
+func (e *timeoutEngine) Exec(ctx context.Context, container string, options dockerapi.ExecOptions) (result *dockerapi.ExecResult, err error) {
+	err = e.do(ctx, "exec in "+container, func(ctx context.Context) error {
+		result, err = e.engine.Exec(ctx, container, options)
+		return err
+	})
+	return result, err
+}
+
+func (e *timeoutEngine) ContainerStats(ctx context.Context, container string) (stats *dockerapi.Stats, err error) {
+	err = e.do(ctx, "stats of "+container, func(ctx context.Context) error {
+		stats, err = e.engine.ContainerStats(ctx, container)
+		return err
+	})
+	return stats, err
+}
+
+func (e *timeoutEngine) Events(ctx context.Context, options dockerapi.EventsOptions) (<-chan dockerapi.Event, <-chan error) {
+	return e.engine.Events(ctx, options)
+}
@@ -0,0 +1,75 @@
+package docker
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"phpier/internal/errors"
+	"phpier/internal/runtime"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hungEngine is an engine whose requests only return once their context ends
+type hungEngine struct {
+	runtime.Engine
+}
+
+func (hungEngine) Ping(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func errorType(t *testing.T, err error) errors.ErrorType {
+	t.Helper()
+
+	var phpierErr *errors.PhpierError
+	require.True(t, stderrors.As(err, &phpierErr), "expected a phpier error, got %v", err)
+	return phpierErr.Type
+}
+
+func TestTimeoutEngine(t *testing.T) {
+	engine := &timeoutEngine{engine: hungEngine{}, timeout: 50 * time.Millisecond}
+
+	err := engine.Ping(context.Background())
+	assert.Equal(t, errors.ErrorTypeCommandTimeout, errorType(t, err))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = engine.Ping(ctx)
+	assert.Equal(t, errors.ErrorTypeUserAborted, errorType(t, err))
+}
+
+func TestRunCommandTimeout(t *testing.T) {
+	client := &Client{ctx: context.Background()}
+
+	err := client.RunCommandTimeout(50*time.Millisecond, "sleep", "5")
+	assert.Equal(t, errors.ErrorTypeCommandTimeout, errorType(t, err))
+
+	err = client.RunCommandTimeout(time.Second, "false")
+	assert.Equal(t, errors.ErrorTypeCommandFailed, errorType(t, err))
+
+	assert.NoError(t, client.RunCommandTimeout(0, "true"))
+}
+
+func TestRunCommand_ForwardsInterrupt(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "interrupted")
+	ctx, cancel := context.WithCancel(context.Background())
+	client := &Client{ctx: ctx}
+
+	// The script records SIGINT and exits, as compose does after stopping what it started
+	script := `trap 'echo yes > "$0"; exit 130' INT; while :; do sleep 0.05; done`
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	err := client.RunCommand("sh", "-c", script, marker)
+	assert.Equal(t, errors.ErrorTypeUserAborted, errorType(t, err))
+
+	content, readErr := os.ReadFile(marker)
+	require.NoError(t, readErr, "the command should have been sent SIGINT")
+	assert.Equal(t, "yes\n", string(content))
+}
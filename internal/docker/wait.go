@@ -0,0 +1,175 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"phpier/internal/dockerapi"
+	"phpier/internal/errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// waitPollInterval is how often WaitHealthy re-checks the containers between events. Runtimes
+// without an event stream (nerdctl) rely on it alone.
+const waitPollInterval = 2 * time.Second
+
+// waitLogLines is how many log lines of a failed service WaitHealthy reports
+const waitLogLines = 20
+
+// ServiceHealth is the readiness of a compose service while waiting for it
+type ServiceHealth struct {
+	Service   string
+	Container string
+	State     string // running, restarting, exited, ...
+	Health    string // starting, healthy or unhealthy; empty without a healthcheck
+	Probe     string // Output of the last healthcheck probe when it failed
+}
+
+// Status describes the service in one word: its health while running, its state otherwise
+func (s ServiceHealth) Status() string {
+	if s.State == "running" && s.Health != "" {
+		return s.Health
+	}
+	return s.State
+}
+
+// Ready reports whether the service is healthy, or running when it has no healthcheck
+func (s ServiceHealth) Ready() bool {
+	return s.State == "running" && (s.Health == "" || s.Health == "healthy")
+}
+
+// Failed reports whether the service won't become ready without intervention
+func (s ServiceHealth) Failed() bool {
+	return s.Health == "unhealthy" || s.State == "exited" || s.State == "dead"
+}
+
+// WaitHealthy waits until every container of the compose project is ready, calling progress
+// whenever a service's status changes. It fails as soon as a service turns unhealthy or exits,
+// or once timeout (0 for no limit) passes, with the last log lines of the service in question.
+func (c *Client) WaitHealthy(ctx context.Context, project string, timeout time.Duration, progress func(ServiceHealth)) error {
+	ctx, cancel := withTimeout(ctx, timeout)
+	defer cancel()
+
+	// Subscribe before the first check so no change in between is missed
+	events, _ := c.WatchEvents(ctx, EventsOptions{Project: project})
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	reported := make(map[string]string)
+	var pending *ServiceHealth
+	for {
+		services, err := c.projectHealth(ctx, project)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		pending = nil
+		for i, service := range services {
+			if reported[service.Service] != service.Status() {
+				reported[service.Service] = service.Status()
+				progress(service)
+			}
+			if service.Failed() {
+				return c.unhealthyError(service, "is "+service.Status())
+			}
+			if !service.Ready() && pending == nil {
+				pending = &services[i]
+			}
+		}
+		if len(services) > 0 && pending == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+			}
+		case <-ticker.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	operation := fmt.Sprintf("waiting for %s", project)
+	if ctx.Err() == context.Canceled {
+		return errors.NewInterruptedError(operation)
+	}
+	if pending == nil {
+		return errors.NewCommandTimeoutError(operation, timeout, ctx.Err()).
+			WithSuggestion("Check that the services were started: 'phpier services'")
+	}
+	return c.unhealthyError(*pending, fmt.Sprintf("is still %s after %s", pending.Status(), timeout))
+}
+
+// projectHealth returns the readiness of every container of a compose project, sorted by service
+func (c *Client) projectHealth(ctx context.Context, project string) ([]ServiceHealth, error) {
+	containers, err := c.api.ContainerList(ctx, dockerapi.ContainerListOptions{
+		All:     true,
+		Filters: dockerapi.Filters{"label": {"com.docker.compose.project=" + project}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var services []ServiceHealth
+	for _, container := range containers {
+		info, err := c.api.ContainerInspect(ctx, container.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		service := ServiceHealth{
+			Service:   container.Labels["com.docker.compose.service"],
+			Container: container.Name(),
+			State:     info.State.Status,
+		}
+		if health := info.State.Health; health != nil {
+			service.Health = health.Status
+			if n := len(health.Log); n > 0 && health.Log[n-1].ExitCode != 0 {
+				service.Probe = strings.TrimSpace(health.Log[n-1].Output)
+			}
+		}
+		services = append(services, service)
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Service < services[j].Service
+	})
+	return services, nil
+}
+
+// unhealthyError reports a service that isn't ready along with its last log lines
+func (c *Client) unhealthyError(service ServiceHealth, reason string) error {
+	logTail := c.containerLogTail(service.Container, waitLogLines)
+	err := errors.NewServiceUnhealthyError(service.Service, service.Container, reason, logTail)
+	if service.Probe != "" {
+		err = err.WithContext("healthcheck", service.Probe)
+	}
+	return err
+}
+
+// containerLogTail returns the last lines a container logged on stdout and stderr, or an
+// empty string when they can't be read
+func (c *Client) containerLogTail(container string, lines int) string {
+	// The caller's context may have run out already; the logs are still worth fetching
+	ctx, cancel := withTimeout(c.ctx, c.timeouts.API)
+	defer cancel()
+
+	output, err := commandContext(ctx, c.CLI(), "logs", "--tail", strconv.Itoa(lines), container).CombinedOutput()
+	if err != nil {
+		logrus.Debugf("Failed to read logs of %s: %v", container, err)
+		return ""
+	}
+	return strings.TrimRight(string(output), "\n")
+}
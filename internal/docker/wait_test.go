@@ -0,0 +1,106 @@
+package docker
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"phpier/internal/dockerapi"
+	"phpier/internal/errors"
+	"phpier/internal/runtime"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healthEngine reports the containers of a project, each inspect of a container returning its
+// next health status (the last one repeats)
+type healthEngine struct {
+	replayEngine
+	containers []dockerapi.Container
+	health     map[string][]string
+}
+
+func (e *healthEngine) ContainerList(ctx context.Context, options dockerapi.ContainerListOptions) ([]dockerapi.Container, error) {
+	return e.containers, nil
+}
+
+func (e *healthEngine) ContainerInspect(ctx context.Context, container string) (*dockerapi.ContainerJSON, error) {
+	info := &dockerapi.ContainerJSON{}
+	info.State.Status = "running"
+
+	statuses := e.health[container]
+	if len(statuses) == 0 {
+		return info, nil
+	}
+	info.State.Health = &dockerapi.Health{Status: statuses[0]}
+	if statuses[0] == "unhealthy" {
+		info.State.Health.Log = []dockerapi.HealthProbe{{ExitCode: 1, Output: "mysqladmin: connect to server failed\n"}}
+	}
+	if len(statuses) > 1 {
+		e.health[container] = statuses[1:]
+	}
+	return info, nil
+}
+
+// cliRuntime is a runtime whose command line tool is a test script
+type cliRuntime struct {
+	runtime.Runtime
+	cli string
+}
+
+func (r cliRuntime) CLI() string {
+	return r.cli
+}
+
+func newHealthEngine(mysql ...string) *healthEngine {
+	project := map[string]string{"com.docker.compose.project": "phpier", "phpier.managed": "true"}
+	return &healthEngine{
+		replayEngine: replayEngine{events: []dockerapi.Event{containerEvent("health_status: healthy", project)}},
+		containers: []dockerapi.Container{
+			{ID: "m1", Names: []string{"/phpier-mysql"}, Labels: map[string]string{"com.docker.compose.service": "mysql"}},
+			{ID: "a1", Names: []string{"/phpier-adminer"}, Labels: map[string]string{"com.docker.compose.service": "adminer"}},
+		},
+		health: map[string][]string{"m1": mysql},
+	}
+}
+
+func TestWaitHealthy(t *testing.T) {
+	client := &Client{ctx: context.Background(), api: newHealthEngine("starting", "healthy")}
+
+	var progress []string
+	err := client.WaitHealthy(context.Background(), "phpier", time.Minute, func(s ServiceHealth) {
+		progress = append(progress, s.Service+" "+s.Status())
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"adminer running", "mysql starting", "mysql healthy"}, progress)
+}
+
+func TestWaitHealthy_Unhealthy(t *testing.T) {
+	dir := t.TempDir()
+	cli := filepath.Join(dir, "docker")
+	script := "#!/bin/sh\necho \"$@\"\necho '[ERROR] InnoDB: cannot allocate memory' >&2\n"
+	require.NoError(t, os.WriteFile(cli, []byte(script), 0o755))
+
+	client := &Client{ctx: context.Background(), api: newHealthEngine("starting", "unhealthy"), runtime: cliRuntime{cli: cli}}
+
+	err := client.WaitHealthy(context.Background(), "phpier", time.Minute, func(ServiceHealth) {})
+	var phpierErr *errors.PhpierError
+	require.True(t, stderrors.As(err, &phpierErr))
+	assert.Equal(t, errors.ErrorTypeServiceUnhealthy, phpierErr.Type)
+	assert.Contains(t, phpierErr.Message, "Service 'mysql' is unhealthy")
+	assert.Contains(t, phpierErr.Message, "logs --tail 20 phpier-mysql")
+	assert.Contains(t, phpierErr.Message, "[ERROR] InnoDB: cannot allocate memory")
+	assert.Equal(t, "mysqladmin: connect to server failed", phpierErr.Context["healthcheck"])
+}
+
+func TestWaitHealthy_Timeout(t *testing.T) {
+	client := &Client{ctx: context.Background(), api: newHealthEngine("starting"), runtime: cliRuntime{cli: "false"}}
+
+	err := client.WaitHealthy(context.Background(), "phpier", 100*time.Millisecond, func(ServiceHealth) {})
+	assert.Equal(t, errors.ErrorTypeServiceUnhealthy, errorType(t, err))
+	assert.Contains(t, err.Error(), "Service 'mysql' is still starting after 100ms")
+}
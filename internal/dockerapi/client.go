@@ -0,0 +1,213 @@
+package dockerapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultHost is the Engine API endpoint used when DOCKER_HOST is not set
+const DefaultHost = "unix:///var/run/docker.sock"
+
+// Client talks to the Docker Engine API over a unix socket or TCP
+type Client struct {
+	Host       string // The DOCKER_HOST style endpoint, e.g. unix:///var/run/docker.sock
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Filters are Engine API filters, e.g. {"label": {"com.docker.compose.project=shop"}}
+type Filters map[string][]string
+
+// APIError is a non-2xx response from the Engine API
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("docker API error (%d): %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 from the Engine API, e.g. a missing container or image
+func IsNotFound(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// NewClient creates a client for host, a DOCKER_HOST style endpoint (unix://, tcp:// or http://)
+func NewClient(host string) (*Client, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Docker host %q: %w", host, err)
+	}
+
+	// Streaming endpoints (events, logs) stay open, so the client itself has no timeout;
+	// callers bound requests with their context
+	transport := &http.Transport{}
+	client := &Client{Host: host, HTTPClient: &http.Client{Transport: transport}}
+
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+		// The host name is ignored when dialing the socket
+		client.BaseURL = "http://docker"
+	case "tcp", "http":
+		client.BaseURL = "http://" + u.Host
+	default:
+		return nil, fmt.Errorf("unsupported Docker host %q: only unix://, tcp:// and http:// endpoints are supported", host)
+	}
+
+	return client, nil
+}
+
+// NewClientFromEnv creates a client for DOCKER_HOST, falling back to the standard socket
+// and then to Docker Desktop's per-user socket
+func NewClientFromEnv() (*Client, error) {
+	return NewClient(ResolveHost())
+}
+
+// ResolveHost returns the Engine API endpoint from DOCKER_HOST or the first socket that exists
+func ResolveHost() string {
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		return host
+	}
+	if _, err := os.Stat(strings.TrimPrefix(DefaultHost, "unix://")); err == nil {
+		return DefaultHost
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		desktop := filepath.Join(home, ".docker", "run", "docker.sock")
+		if _, err := os.Stat(desktop); err == nil {
+			return "unix://" + desktop
+		}
+	}
+	return DefaultHost
+}
+
+// Ping checks that the daemon answers
+func (c *Client) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	resp, err := c.do(ctx, http.MethodGet, "/_ping", nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Version is the daemon version information
+type Version struct {
+	Version    string `json:"Version"`
+	APIVersion string `json:"ApiVersion"`
+	Os         string `json:"Os"`
+	Arch       string `json:"Arch"`
+}
+
+// ServerVersion returns the daemon version information
+func (c *Client) ServerVersion(ctx context.Context) (*Version, error) {
+	var version Version
+	if err := c.get(ctx, "/version", nil, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, target interface{}) error {
+	resp, err := c.do(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode Docker API response for %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Client) post(ctx context.Context, path string, query url.Values, body, target interface{}) error {
+	resp, err := c.do(ctx, http.MethodPost, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if target == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode Docker API response for %s: %w", path, err)
+	}
+	return nil
+}
+
+// do sends a request and returns the response for 2xx statuses; other statuses become an *APIError
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = strings.NewReader(string(data))
+	}
+
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Docker at %s: %w", c.Host, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		var message struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &message) != nil || message.Message == "" {
+			message.Message = strings.TrimSpace(string(data))
+		}
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: message.Message}
+	}
+
+	return resp, nil
+}
+
+// filtersQuery encodes filters the way the Engine API expects them
+func filtersQuery(query url.Values, filters Filters) url.Values {
+	if query == nil {
+		query = url.Values{}
+	}
+	if len(filters) == 0 {
+		return query
+	}
+	data, _ := json.Marshal(filters)
+	query.Set("filters", string(data))
+	return query
+}
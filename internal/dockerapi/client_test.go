@@ -0,0 +1,239 @@
+package dockerapi
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDaemon serves handler on a unix socket and returns a client connected to it
+func fakeDaemon(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("unix://" + socket)
+	require.NoError(t, err)
+	return client
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient("tcp://127.0.0.1:2375")
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:2375", client.BaseURL)
+
+	_, err = NewClient("ssh://user@host")
+	assert.Error(t, err)
+}
+
+func TestResolveHost(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "tcp://10.0.0.2:2375")
+	assert.Equal(t, "tcp://10.0.0.2:2375", ResolveHost())
+}
+
+func TestPing(t *testing.T) {
+	client := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/_ping", r.URL.Path)
+		w.Write([]byte("OK"))
+	}))
+	assert.NoError(t, client.Ping(context.Background()))
+}
+
+func TestContainerList(t *testing.T) {
+	client := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/containers/json", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("all"))
+
+		var filters Filters
+		require.NoError(t, json.Unmarshal([]byte(r.URL.Query().Get("filters")), &filters))
+		assert.Equal(t, Filters{"label": {"com.docker.compose.project=shop"}}, filters)
+
+		w.Write([]byte(`[{"Id":"abc","Names":["/shop-app-1"],"Image":"phpier-shop:8.3","State":"running",
+			"Status":"Up 2 hours (healthy)","Labels":{"com.docker.compose.service":"app"},
+			"Ports":[{"IP":"0.0.0.0","PrivatePort":80,"PublicPort":8080,"Type":"tcp"}],
+			"NetworkSettings":{"Networks":{"phpier_phpier_global":{"Aliases":["shop.internal"]}}}}]`))
+	}))
+
+	containers, err := client.ContainerList(context.Background(), ContainerListOptions{
+		All:     true,
+		Filters: Filters{"label": {"com.docker.compose.project=shop"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+	assert.Equal(t, "shop-app-1", containers[0].Name())
+	assert.Equal(t, "app", containers[0].Labels["com.docker.compose.service"])
+	assert.Equal(t, 8080, containers[0].Ports[0].PublicPort)
+	assert.Equal(t, []string{"shop.internal"}, containers[0].NetworkSettings.Networks["phpier_phpier_global"].Aliases)
+}
+
+func TestContainerInspect_NotFound(t *testing.T) {
+	client := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		writeJSON(w, map[string]string{"message": "No such container: missing"})
+	}))
+
+	_, err := client.ContainerInspect(context.Background(), "missing")
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+	assert.Contains(t, err.Error(), "No such container: missing")
+}
+
+func TestContainerInspect(t *testing.T) {
+	client := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/containers/shop-app-1/json", r.URL.Path)
+		w.Write([]byte(`{"Id":"abc","Name":"/shop-app-1","State":{"Status":"running","Running":true,
+			"StartedAt":"2026-01-02T03:04:05.123456789Z","Health":{"Status":"healthy"}},
+			"Config":{"Image":"phpier-shop:8.3","Labels":{"com.docker.compose.project":"shop"}},
+			"NetworkSettings":{"Ports":{"80/tcp":[{"HostIp":"0.0.0.0","HostPort":"8080"}]}}}`))
+	}))
+
+	info, err := client.ContainerInspect(context.Background(), "shop-app-1")
+	require.NoError(t, err)
+	assert.Equal(t, "running", info.State.Status)
+	assert.Equal(t, "healthy", info.State.Health.Status)
+	assert.Equal(t, "shop", info.Config.Labels["com.docker.compose.project"])
+	assert.Equal(t, "8080", info.NetworkSettings.Ports["80/tcp"][0].HostPort)
+}
+
+func TestImageList(t *testing.T) {
+	client := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/images/json", r.URL.Path)
+		assert.Contains(t, r.URL.Query().Get("filters"), `"reference":["phpier-*"]`)
+		w.Write([]byte(`[{"Id":"sha256:1","RepoTags":["phpier-shop:8.3"],"Created":1700000000,"Size":123456}]`))
+	}))
+
+	images, err := client.ImageList(context.Background(), Filters{"reference": {"phpier-*"}})
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	assert.Equal(t, []string{"phpier-shop:8.3"}, images[0].RepoTags)
+	assert.Equal(t, int64(123456), images[0].Size)
+}
+
+func TestNetworkInspect(t *testing.T) {
+	client := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/networks/phpier_phpier_global", r.URL.Path)
+		w.Write([]byte(`{"Name":"phpier_phpier_global","Containers":{"abc":{"Name":"shop-app-1","IPv4Address":"172.20.0.5/16"}}}`))
+	}))
+
+	network, err := client.NetworkInspect(context.Background(), "phpier_phpier_global")
+	require.NoError(t, err)
+	assert.Equal(t, "shop-app-1", network.Containers["abc"].Name)
+}
+
+func TestContainerStats(t *testing.T) {
+	client := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/containers/shop-app-1/stats", r.URL.Path)
+		assert.Equal(t, "false", r.URL.Query().Get("stream"))
+		w.Write([]byte(`{
+			"cpu_stats":{"cpu_usage":{"total_usage":3000000},"system_cpu_usage":20000000,"online_cpus":4},
+			"precpu_stats":{"cpu_usage":{"total_usage":1000000},"system_cpu_usage":10000000},
+			"memory_stats":{"usage":300,"limit":1000,"stats":{"inactive_file":100}},
+			"networks":{"eth0":{"rx_bytes":10,"tx_bytes":20},"eth1":{"rx_bytes":1,"tx_bytes":2}},
+			"blkio_stats":{"io_service_bytes_recursive":[{"op":"Read","value":4096},{"op":"write","value":512},{"op":"read","value":4}]},
+			"pids_stats":{"current":7}}`))
+	}))
+
+	stats, err := client.ContainerStats(context.Background(), "shop-app-1")
+	require.NoError(t, err)
+	assert.InDelta(t, 80.0, stats.CPUPercent, 0.001)
+	assert.Equal(t, &Stats{
+		CPUPercent: stats.CPUPercent, MemoryUsage: 200, MemoryLimit: 1000,
+		NetworkRx: 11, NetworkTx: 22, BlockRead: 4100, BlockWrite: 512, PIDs: 7,
+	}, stats)
+}
+
+func frame(stream byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+func TestExec(t *testing.T) {
+	client := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/containers/shop-app-1/exec":
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, []interface{}{"php", "-v"}, body["Cmd"])
+			assert.Equal(t, "www-data", body["User"])
+			w.WriteHeader(http.StatusCreated)
+			writeJSON(w, map[string]string{"Id": "exec1"})
+		case r.Method == http.MethodPost && r.URL.Path == "/exec/exec1/start":
+			w.Header().Set("Content-Type", "application/vnd.docker.multiplexed-stream")
+			w.Write(frame(1, "PHP 8.3.0\n"))
+			w.Write(frame(2, "warning\n"))
+			w.Write(frame(1, "Zend Engine\n"))
+		case r.URL.Path == "/exec/exec1/json":
+			writeJSON(w, map[string]interface{}{"ExitCode": 3, "Running": false})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+
+	result, err := client.Exec(context.Background(), "shop-app-1", ExecOptions{Cmd: []string{"php", "-v"}, User: "www-data"})
+	require.NoError(t, err)
+	assert.Equal(t, "PHP 8.3.0\nZend Engine\n", result.Stdout)
+	assert.Equal(t, "warning\n", result.Stderr)
+	assert.Equal(t, 3, result.ExitCode)
+}
+
+func TestEvents(t *testing.T) {
+	client := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/events", r.URL.Path)
+		assert.Equal(t, "1700000000", r.URL.Query().Get("since"))
+		w.Write([]byte(`{"Type":"container","Action":"start","Actor":{"ID":"abc","Attributes":{"name":"shop-app-1"}},"time":1700000001}` + "\n"))
+		w.Write([]byte(`{"Type":"container","Action":"die","Actor":{"ID":"abc","Attributes":{"exitCode":"137"}},"timeNano":1700000002000000000}` + "\n"))
+	}))
+
+	events, errs := client.Events(context.Background(), EventsOptions{Since: time.Unix(1700000000, 0)})
+
+	var received []Event
+	for event := range events {
+		received = append(received, event)
+	}
+	select {
+	case err := <-errs:
+		require.NoError(t, err)
+	default:
+	}
+
+	require.Len(t, received, 2)
+	assert.Equal(t, "start", received[0].Action)
+	assert.Equal(t, "shop-app-1", received[0].Actor.Attributes["name"])
+	assert.Equal(t, time.Unix(1700000002, 0), received[1].Timestamp())
+}
+
+func TestEvents_ConnectionError(t *testing.T) {
+	client, err := NewClient("unix://" + filepath.Join(t.TempDir(), "missing.sock"))
+	require.NoError(t, err)
+
+	events, errs := client.Events(context.Background(), EventsOptions{})
+	for range events {
+	}
+	err = <-errs
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "failed to reach Docker"))
+}
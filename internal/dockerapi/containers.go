@@ -0,0 +1,140 @@
+package dockerapi
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Container is a container as returned by the container list endpoint
+type Container struct {
+	ID              string            `json:"Id"`
+	Names           []string          `json:"Names"`
+	Image           string            `json:"Image"`
+	ImageID         string            `json:"ImageID"`
+	Command         string            `json:"Command"`
+	Created         int64             `json:"Created"`
+	State           string            `json:"State"`
+	Status          string            `json:"Status"`
+	Ports           []Port            `json:"Ports"`
+	Labels          map[string]string `json:"Labels"`
+	Mounts          []MountPoint      `json:"Mounts"`
+	NetworkSettings struct {
+		Networks map[string]EndpointSettings `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+// Name returns the container name without the leading slash
+func (c Container) Name() string {
+	if len(c.Names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// Port is a port exposed by a container; PublicPort is 0 when it isn't published
+type Port struct {
+	IP          string `json:"IP"`
+	PrivatePort int    `json:"PrivatePort"`
+	PublicPort  int    `json:"PublicPort"`
+	Type        string `json:"Type"`
+}
+
+// MountPoint is a volume or bind mount of a container
+type MountPoint struct {
+	Type        string `json:"Type"`
+	Name        string `json:"Name"`
+	Source      string `json:"Source"`
+	Destination string `json:"Destination"`
+	Mode        string `json:"Mode"`
+	RW          bool   `json:"RW"`
+}
+
+// EndpointSettings describes a container's attachment to a network
+type EndpointSettings struct {
+	NetworkID string   `json:"NetworkID"`
+	IPAddress string   `json:"IPAddress"`
+	Aliases   []string `json:"Aliases"`
+	DNSNames  []string `json:"DNSNames"`
+}
+
+// ContainerJSON is the detailed container state returned by the inspect endpoint
+type ContainerJSON struct {
+	ID      string `json:"Id"`
+	Name    string `json:"Name"`
+	Created string `json:"Created"`
+	Image   string `json:"Image"`
+	State   struct {
+		Status     string  `json:"Status"`
+		Running    bool    `json:"Running"`
+		ExitCode   int     `json:"ExitCode"`
+		StartedAt  string  `json:"StartedAt"`
+		FinishedAt string  `json:"FinishedAt"`
+		Health     *Health `json:"Health"`
+	} `json:"State"`
+	Config struct {
+		Image  string            `json:"Image"`
+		Labels map[string]string `json:"Labels"`
+		Env    []string          `json:"Env"`
+	} `json:"Config"`
+	NetworkSettings struct {
+		Ports    map[string][]PortBinding    `json:"Ports"`
+		Networks map[string]EndpointSettings `json:"Networks"`
+	} `json:"NetworkSettings"`
+	Mounts     []MountPoint `json:"Mounts"`
+	LogPath    string       `json:"LogPath"` // Host path of the container log kept by the json-file driver
+	HostConfig struct {
+		LogConfig struct {
+			Type   string            `json:"Type"`
+			Config map[string]string `json:"Config"`
+		} `json:"LogConfig"`
+	} `json:"HostConfig"`
+}
+
+// Health is the healthcheck state of a container
+type Health struct {
+	Status        string        `json:"Status"`
+	FailingStreak int           `json:"FailingStreak"`
+	Log           []HealthProbe `json:"Log"` // The most recent probes, oldest first
+}
+
+// HealthProbe is the result of one healthcheck run
+type HealthProbe struct {
+	ExitCode int    `json:"ExitCode"`
+	Output   string `json:"Output"`
+}
+
+// PortBinding is a host binding of a container port
+type PortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// ContainerListOptions selects the containers returned by ContainerList
+type ContainerListOptions struct {
+	All     bool // Include stopped containers
+	Filters Filters
+}
+
+// ContainerList returns the containers matching options
+func (c *Client) ContainerList(ctx context.Context, options ContainerListOptions) ([]Container, error) {
+	query := url.Values{}
+	if options.All {
+		query.Set("all", "1")
+	}
+
+	var containers []Container
+	if err := c.get(ctx, "/containers/json", filtersQuery(query, options.Filters), &containers); err != nil {
+		return nil, err
+	}
+	return containers, nil
+}
+
+// ContainerInspect returns the detailed state of the container with the given ID or name
+func (c *Client) ContainerInspect(ctx context.Context, container string) (*ContainerJSON, error) {
+	var info ContainerJSON
+	if err := c.get(ctx, "/containers/"+url.PathEscape(container)+"/json", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
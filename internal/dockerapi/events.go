@@ -0,0 +1,85 @@
+package dockerapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Event is a message from the daemon's event stream
+type Event struct {
+	Type     string `json:"Type"` // container, image, network, volume, ...
+	Action   string `json:"Action"`
+	Actor    Actor  `json:"Actor"`
+	Time     int64  `json:"time"`
+	TimeNano int64  `json:"timeNano"`
+}
+
+// Actor is the object an event is about; Attributes carry its name and labels
+type Actor struct {
+	ID         string            `json:"ID"`
+	Attributes map[string]string `json:"Attributes"`
+}
+
+// Timestamp returns when the event happened
+func (e Event) Timestamp() time.Time {
+	if e.TimeNano != 0 {
+		return time.Unix(0, e.TimeNano)
+	}
+	return time.Unix(e.Time, 0)
+}
+
+// EventsOptions selects the events returned by Events
+type EventsOptions struct {
+	Since   time.Time // Replay events since this time
+	Until   time.Time // Stop streaming at this time; zero streams until ctx is cancelled
+	Filters Filters
+}
+
+// Events streams daemon events until ctx is cancelled, Until is reached or the connection drops.
+// The events channel is closed when the stream ends; a non-nil error is sent on errs first.
+func (c *Client) Events(ctx context.Context, options EventsOptions) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errs := make(chan error, 1)
+
+	query := url.Values{}
+	if !options.Since.IsZero() {
+		query.Set("since", strconv.FormatInt(options.Since.Unix(), 10))
+	}
+	if !options.Until.IsZero() {
+		query.Set("until", strconv.FormatInt(options.Until.Unix(), 10))
+	}
+
+	go func() {
+		defer close(events)
+
+		resp, err := c.do(ctx, http.MethodGet, "/events", filtersQuery(query, options.Filters), nil)
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer resp.Body.Close()
+
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var event Event
+			if err := decoder.Decode(&event); err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					errs <- err
+				}
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, errs
+}
@@ -0,0 +1,93 @@
+package dockerapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ExecOptions describes a command to run in a running container
+type ExecOptions struct {
+	Cmd        []string
+	User       string
+	WorkingDir string
+	Env        []string
+}
+
+// ExecResult is the captured output and exit code of a finished exec
+type ExecResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Exec runs a command in a container without a TTY and waits for it to finish
+func (c *Client) Exec(ctx context.Context, container string, options ExecOptions) (*ExecResult, error) {
+	var created struct {
+		ID string `json:"Id"`
+	}
+	body := map[string]interface{}{
+		"Cmd":          options.Cmd,
+		"User":         options.User,
+		"WorkingDir":   options.WorkingDir,
+		"Env":          options.Env,
+		"AttachStdout": true,
+		"AttachStderr": true,
+	}
+	if err := c.post(ctx, "/containers/"+url.PathEscape(container)+"/exec", nil, body, &created); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, "/exec/"+created.ID+"/start", nil, map[string]bool{"Detach": false, "Tty": false})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var stdout, stderr bytes.Buffer
+	if err := Demux(resp.Body, &stdout, &stderr); err != nil {
+		return nil, fmt.Errorf("failed to read exec output: %w", err)
+	}
+
+	var inspect struct {
+		ExitCode int  `json:"ExitCode"`
+		Running  bool `json:"Running"`
+	}
+	if err := c.get(ctx, "/exec/"+created.ID+"/json", nil, &inspect); err != nil {
+		return nil, err
+	}
+
+	return &ExecResult{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: inspect.ExitCode}, nil
+}
+
+// Demux splits a multiplexed exec or logs stream (8-byte frame headers) into stdout and stderr
+func Demux(stream io.Reader, stdout, stderr io.Writer) error {
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(stream, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		var target io.Writer
+		switch header[0] {
+		case 0, 1:
+			target = stdout
+		case 2:
+			target = stderr
+		default:
+			return fmt.Errorf("unexpected stream type %d", header[0])
+		}
+
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.CopyN(target, stream, size); err != nil {
+			return err
+		}
+	}
+}
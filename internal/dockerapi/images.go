@@ -0,0 +1,38 @@
+package dockerapi
+
+import "context"
+
+// Image is an image as returned by the image list endpoint
+type Image struct {
+	ID       string            `json:"Id"`
+	RepoTags []string          `json:"RepoTags"`
+	Created  int64             `json:"Created"`
+	Size     int64             `json:"Size"`
+	Labels   map[string]string `json:"Labels"`
+}
+
+// ImageInspect is the detailed image information returned by the inspect endpoint
+type ImageInspect struct {
+	ID       string   `json:"Id"`
+	RepoTags []string `json:"RepoTags"`
+	Created  string   `json:"Created"`
+	Size     int64    `json:"Size"`
+}
+
+// ImageList returns the images matching filters, e.g. {"reference": {"phpier-*"}}
+func (c *Client) ImageList(ctx context.Context, filters Filters) ([]Image, error) {
+	var images []Image
+	if err := c.get(ctx, "/images/json", filtersQuery(nil, filters), &images); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// ImageInspect returns the details of the image with the given reference or ID
+func (c *Client) ImageInspect(ctx context.Context, ref string) (*ImageInspect, error) {
+	var image ImageInspect
+	if err := c.get(ctx, "/images/"+ref+"/json", nil, &image); err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
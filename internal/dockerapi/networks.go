@@ -0,0 +1,30 @@
+package dockerapi
+
+import (
+	"context"
+	"net/url"
+)
+
+// Network is a Docker network as returned by the inspect endpoint
+type Network struct {
+	ID         string                      `json:"Id"`
+	Name       string                      `json:"Name"`
+	Driver     string                      `json:"Driver"`
+	Labels     map[string]string           `json:"Labels"`
+	Containers map[string]NetworkContainer `json:"Containers"`
+}
+
+// NetworkContainer is a container attached to a network, keyed by container ID in Network
+type NetworkContainer struct {
+	Name        string `json:"Name"`
+	IPv4Address string `json:"IPv4Address"`
+}
+
+// NetworkInspect returns the network with the given name or ID and its attached containers
+func (c *Client) NetworkInspect(ctx context.Context, network string) (*Network, error) {
+	var info Network
+	if err := c.get(ctx, "/networks/"+url.PathEscape(network), nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
@@ -0,0 +1,106 @@
+package dockerapi
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Stats is a resource usage sample of a container
+type Stats struct {
+	CPUPercent  float64 // Share of one CPU, so up to 100 times the number of CPUs
+	MemoryUsage uint64  // Bytes in use, without the reclaimable page cache
+	MemoryLimit uint64  // Memory limit of the container, or the host memory without one
+	NetworkRx   uint64
+	NetworkTx   uint64
+	BlockRead   uint64
+	BlockWrite  uint64
+	PIDs        uint64
+}
+
+// statsJSON is the part of the stats endpoint response phpier reads
+type statsJSON struct {
+	CPUStats    cpuStats `json:"cpu_stats"`
+	PreCPUStats cpuStats `json:"precpu_stats"`
+	MemoryStats struct {
+		Usage uint64            `json:"usage"`
+		Limit uint64            `json:"limit"`
+		Stats map[string]uint64 `json:"stats"`
+	} `json:"memory_stats"`
+	Networks map[string]struct {
+		RxBytes uint64 `json:"rx_bytes"`
+		TxBytes uint64 `json:"tx_bytes"`
+	} `json:"networks"`
+	BlkioStats struct {
+		IoServiceBytesRecursive []struct {
+			Op    string `json:"op"`
+			Value uint64 `json:"value"`
+		} `json:"io_service_bytes_recursive"`
+	} `json:"blkio_stats"`
+	PidsStats struct {
+		Current uint64 `json:"current"`
+	} `json:"pids_stats"`
+}
+
+type cpuStats struct {
+	CPUUsage struct {
+		TotalUsage  uint64   `json:"total_usage"`
+		PercpuUsage []uint64 `json:"percpu_usage"`
+	} `json:"cpu_usage"`
+	SystemUsage uint64 `json:"system_cpu_usage"`
+	OnlineCPUs  uint64 `json:"online_cpus"`
+}
+
+// ContainerStats samples the resource usage of a container. The daemon takes two readings
+// about a second apart to work out the CPU usage, so the request takes that long.
+func (c *Client) ContainerStats(ctx context.Context, container string) (*Stats, error) {
+	var raw statsJSON
+	query := url.Values{"stream": {"false"}}
+	if err := c.get(ctx, "/containers/"+url.PathEscape(container)+"/stats", query, &raw); err != nil {
+		return nil, err
+	}
+	stats := raw.stats()
+	return &stats, nil
+}
+
+// stats works out the usage figures the way 'docker stats' does
+func (s statsJSON) stats() Stats {
+	stats := Stats{
+		MemoryUsage: s.MemoryStats.Usage,
+		MemoryLimit: s.MemoryStats.Limit,
+		PIDs:        s.PidsStats.Current,
+	}
+
+	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(s.CPUStats.SystemUsage) - float64(s.PreCPUStats.SystemUsage)
+	cpus := float64(s.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(s.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		stats.CPUPercent = cpuDelta / systemDelta * cpus * 100
+	}
+
+	// The page cache is reclaimable, so leave it out (cgroup v1 and v2 name it differently)
+	for _, key := range []string{"total_inactive_file", "inactive_file"} {
+		if cache, ok := s.MemoryStats.Stats[key]; ok && cache < stats.MemoryUsage {
+			stats.MemoryUsage -= cache
+			break
+		}
+	}
+
+	for _, network := range s.Networks {
+		stats.NetworkRx += network.RxBytes
+		stats.NetworkTx += network.TxBytes
+	}
+
+	for _, entry := range s.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			stats.BlockRead += entry.Value
+		case "write":
+			stats.BlockWrite += entry.Value
+		}
+	}
+	return stats
+}
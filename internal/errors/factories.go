@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Docker-related error factories
 
@@ -25,6 +28,47 @@ func NewDockerComposeNotFoundError() *PhpierError {
 		WithSuggestion("For newer Docker installations, try 'docker compose' instead of 'docker-compose'")
 }
 
+// NewRuntimeNotFoundError creates a not found error for a container runtime other than Docker
+func NewRuntimeNotFoundError(runtime string) *PhpierError {
+	if runtime == "docker" {
+		return NewDockerNotFoundError()
+	}
+	return NewPhpierError(ErrorTypeDockerNotFound, fmt.Sprintf("%s is not installed or not found in PATH", runtime)).
+		WithSuggestion(fmt.Sprintf("Install %s, or set 'runtime' in ~/.phpier/config.yaml to the runtime you use", runtime))
+}
+
+// NewRuntimeNotRunningError creates a not running error for a container runtime other than Docker
+func NewRuntimeNotRunningError(runtime, socket string) *PhpierError {
+	switch runtime {
+	case "podman":
+		err := NewPhpierError(ErrorTypeDockerNotRunning, "Podman API socket is not reachable").
+			WithSuggestion("Enable the socket: 'systemctl --user enable --now podman.socket' (rootful: 'sudo systemctl enable --now podman.socket')")
+		if socket != "" {
+			err = err.WithSuggestion(fmt.Sprintf("phpier expects it at %s; set DOCKER_HOST if it lives elsewhere", socket))
+		}
+		return err
+	case "nerdctl":
+		return NewPhpierError(ErrorTypeDockerNotRunning, "containerd is not running").
+			WithSuggestion("Start containerd (rootless: 'containerd-rootless-setuptool.sh install')").
+			WithSuggestion("Check with 'nerdctl info'")
+	default:
+		return NewDockerNotRunningError()
+	}
+}
+
+// NewRuntimeComposeNotFoundError creates a compose not found error for a container runtime other than Docker
+func NewRuntimeComposeNotFoundError(runtime string) *PhpierError {
+	if runtime == "podman" {
+		return NewPhpierError(ErrorTypeDockerComposeError, "No compose implementation found for Podman").
+			WithSuggestion("Install podman-compose (e.g. 'sudo dnf install podman-compose'), or docker-compose for 'podman compose'")
+	}
+	if runtime == "nerdctl" {
+		return NewPhpierError(ErrorTypeDockerComposeError, "'nerdctl compose' is not available").
+			WithSuggestion("Upgrade nerdctl; compose support is built in since v0.10")
+	}
+	return NewDockerComposeNotFoundError()
+}
+
 // NewContainerNotFoundError creates a container not found error
 func NewContainerNotFoundError(containerName string) *PhpierError {
 	return NewPhpierError(ErrorTypeContainerNotFound, fmt.Sprintf("Container '%s' not found", containerName)).
@@ -50,6 +94,20 @@ func NewBuildFailedError(service string, cause error) *PhpierError {
 		WithSuggestion("Try rebuilding with: 'phpier build --no-cache'")
 }
 
+// NewServiceUnhealthyError creates an error for a service that failed to become healthy.
+// logTail, the service's last log lines, is appended to the message when not empty.
+func NewServiceUnhealthyError(service, container, reason, logTail string) *PhpierError {
+	message := fmt.Sprintf("Service '%s' %s", service, reason)
+	if logTail != "" {
+		message += "\n\nLast log lines of " + service + ":\n" + logTail
+	}
+	return NewPhpierError(ErrorTypeServiceUnhealthy, message).
+		WithContext("service", service).
+		WithContext("container", container).
+		WithSuggestion(fmt.Sprintf("Check the full logs: 'docker logs %s'", container)).
+		WithSuggestion("Check service status and health: 'phpier services'")
+}
+
 // Configuration-related error factories
 
 // NewInvalidConfigError creates an invalid configuration error
@@ -156,6 +214,21 @@ func NewCommandFailedError(command string, args []string, cause error) *PhpierEr
 		WithSuggestion("Ensure all required dependencies are installed")
 }
 
+// NewCommandTimeoutError creates an error for a command or runtime request that ran out of time
+func NewCommandTimeoutError(command string, timeout time.Duration, cause error) *PhpierError {
+	return WrapError(ErrorTypeCommandTimeout, fmt.Sprintf("Timed out after %s: %s", timeout, command), cause).
+		WithContext("command", command).
+		WithContext("timeout", timeout.String()).
+		WithSuggestion("Check that the container runtime responds, e.g. 'docker info'").
+		WithSuggestion("Raise the limit under 'timeouts' in ~/.phpier/config.yaml or with --command-timeout")
+}
+
+// NewInterruptedError creates an error for a command stopped by Ctrl-C or SIGTERM
+func NewInterruptedError(command string) *PhpierError {
+	return NewPhpierError(ErrorTypeUserAborted, fmt.Sprintf("Interrupted: %s", command)).
+		WithContext("command", command)
+}
+
 // NewCommandNotFoundError creates a command not found error
 func NewCommandNotFoundError(command string) *PhpierError {
 	return NewPhpierError(ErrorTypeCommandNotFound, fmt.Sprintf("Command not found: %s", command)).
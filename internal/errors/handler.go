@@ -1,6 +1,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"os"
 
@@ -27,7 +28,7 @@ const (
 func GetExitCode(errorType ErrorType) ExitCode {
 	switch errorType {
 	case ErrorTypeDockerNotFound, ErrorTypeDockerNotRunning, ErrorTypeDockerComposeError,
-		ErrorTypeContainerNotFound, ErrorTypeContainerNotRunning, ErrorTypeBuildFailed:
+		ErrorTypeContainerNotFound, ErrorTypeContainerNotRunning, ErrorTypeBuildFailed, ErrorTypeServiceUnhealthy:
 		return ExitCodeDockerError
 
 	case ErrorTypeInvalidConfig, ErrorTypeConfigNotFound, ErrorTypeConfigCorrupted:
@@ -77,7 +78,7 @@ func (h *ErrorHandler) Handle(err error) {
 	var exitCode ExitCode = ExitCodeGeneralError
 
 	// Check if it's a PhpierError
-	if devErr, ok := err.(*PhpierError); ok {
+	if devErr, ok := asPhpierError(err); ok {
 		h.displayPhpierError(devErr)
 		exitCode = GetExitCode(devErr.Type)
 	} else {
@@ -96,7 +97,7 @@ func (h *ErrorHandler) HandleWithoutExit(err error) ExitCode {
 	var exitCode ExitCode = ExitCodeGeneralError
 
 	// Check if it's a PhpierError
-	if devErr, ok := err.(*PhpierError); ok {
+	if devErr, ok := asPhpierError(err); ok {
 		h.displayPhpierError(devErr)
 		exitCode = GetExitCode(devErr.Type)
 	} else {
@@ -106,6 +107,20 @@ func (h *ErrorHandler) HandleWithoutExit(err error) ExitCode {
 	return exitCode
 }
 
+// asPhpierError returns err as a PhpierError. Timeouts and interruptions are also found inside
+// wrapped errors, so they keep their exit code whichever command wrapped them.
+func asPhpierError(err error) (*PhpierError, bool) {
+	if devErr, ok := err.(*PhpierError); ok {
+		return devErr, true
+	}
+
+	var wrapped *PhpierError
+	if stderrors.As(err, &wrapped) && (wrapped.Type == ErrorTypeCommandTimeout || wrapped.Type == ErrorTypeUserAborted) {
+		return wrapped, true
+	}
+	return nil, false
+}
+
 // displayPhpierError displays a PhpierError with formatting
 func (h *ErrorHandler) displayPhpierError(err *PhpierError) {
 	if h.colored {
@@ -22,6 +22,7 @@ const (
 	ErrorTypeContainerNotFound   ErrorType = "container_not_found"
 	ErrorTypeContainerNotRunning ErrorType = "container_not_running"
 	ErrorTypeBuildFailed         ErrorType = "build_failed"
+	ErrorTypeServiceUnhealthy    ErrorType = "service_unhealthy"
 
 	// File system errors
 	ErrorTypeFileNotFound    ErrorType = "file_not_found"
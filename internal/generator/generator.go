@@ -1,11 +1,21 @@
 package generator
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	goruntime "runtime"
+	"sort"
+	"strings"
 
+	"phpier/internal/certs"
 	"phpier/internal/config"
+	"phpier/internal/dns"
+	"phpier/internal/routes"
+	"phpier/internal/runtime"
 	"phpier/internal/templates"
 
 	"github.com/sirupsen/logrus"
@@ -15,8 +25,20 @@ import (
 func GenerateProjectFiles(engine *templates.Engine, projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig) error {
 	// Note: docker-compose.yml is now generated directly in project root by init command
 
+	// Collect Dockerfile snippets from .phpier/Dockerfile.d
+	hooks, err := LoadDockerfileHooks(".phpier/Dockerfile.d")
+	if err != nil {
+		return err
+	}
+
+	// Prepare the shared base image the project Dockerfile builds on
+	baseImage, err := PrepareBaseImage(engine, projectCfg, hooks)
+	if err != nil {
+		return err
+	}
+
 	// Generate Dockerfile for the project
-	dockerfile, err := engine.RenderPHPDockerfile(projectCfg)
+	dockerfile, err := engine.RenderPHPDockerfile(projectCfg, hooks, baseImage.Tag)
 	if err != nil {
 		return fmt.Errorf("failed to render Dockerfile: %w", err)
 	}
@@ -24,15 +46,22 @@ func GenerateProjectFiles(engine *templates.Engine, projectCfg *config.ProjectCo
 		return err
 	}
 
+	if err := globalCfg.Logging.Validate(); err != nil {
+		return err
+	}
+	maxBytes, backups := supervisorLogLimits(globalCfg.Logging)
+
 	// Generate Nginx, Supervisor, and PHP configs
 	// Note: These templates might need to be created or updated to work with the new config structure
-	supervisorConf := `[supervisord]
+	supervisorConf := fmt.Sprintf(`[supervisord]
 nodaemon=true
 user=root
 # Move PID file to proper location
 pidfile=/var/run/supervisor/supervisord.pid
 # Move log files to proper location
 logfile=/var/log/supervisor/supervisord.log
+logfile_maxbytes=%[1]d
+logfile_backups=%[2]d
 childlogdir=/var/log/supervisor
 loglevel=info
 silent=false
@@ -51,14 +80,18 @@ password=supervisor
 [rpcinterface:supervisor]
 supervisor.rpcinterface_factory = supervisor.rpcinterface:make_main_rpcinterface
 
-# PHP-FPM program
+# PHP-FPM program; the PHP error log is created first so workers running as www-data can write it
 [program:php-fpm]
-command=/usr/local/sbin/php-fpm --nodaemonize --fpm-config /usr/local/etc/php-fpm.conf
+command=/bin/sh -c "touch /var/log/php/errors.log && chmod 0666 /var/log/php/errors.log && exec /usr/local/sbin/php-fpm --nodaemonize --fpm-config /usr/local/etc/php-fpm.conf"
 autostart=true
 autorestart=true
 priority=5
 stdout_logfile=/var/log/supervisor/php-fpm.log
 stderr_logfile=/var/log/supervisor/php-fpm-error.log
+stdout_logfile_maxbytes=%[1]d
+stdout_logfile_backups=%[2]d
+stderr_logfile_maxbytes=%[1]d
+stderr_logfile_backups=%[2]d
 user=root
 killasgroup=true
 stopasgroup=true
@@ -71,12 +104,25 @@ autorestart=true
 priority=10
 stdout_logfile=/var/log/supervisor/nginx.log
 stderr_logfile=/var/log/supervisor/nginx-error.log
+stdout_logfile_maxbytes=%[1]d
+stdout_logfile_backups=%[2]d
+stderr_logfile_maxbytes=%[1]d
+stderr_logfile_backups=%[2]d
 user=root
 killasgroup=true
-stopasgroup=true`
+stopasgroup=true`, maxBytes, backups)
+	if globalCfg.Logging.MaxBytes() > 0 {
+		supervisorConf += supervisorLogRotateProgram
+	}
+	if projectCfg.DevServer.Enabled {
+		supervisorConf += supervisorDevServerProgram(projectCfg, maxBytes, backups)
+	}
 	if err := WriteFile(".phpier/docker/supervisor/supervisord.conf", supervisorConf); err != nil {
 		return err
 	}
+	if err := WriteFile(".phpier/docker/logrotate.sh", logRotateScript(globalCfg.Logging)); err != nil {
+		return err
+	}
 
 	// Generate PHP configuration
 	phpIni, err := engine.RenderPHPConfig()
@@ -108,12 +154,321 @@ stopasgroup=true`
 	return nil
 }
 
+// supervisorLogLimits returns the size supervisor rotates its logs at and how many rotated
+// logs it keeps; 0 and 0 leave them unbounded
+func supervisorLogLimits(logging config.LoggingConfig) (int64, int) {
+	maxBytes := logging.MaxBytes()
+	if maxBytes == 0 {
+		return 0, 0
+	}
+	return maxBytes, logging.MaxFiles - 1
+}
+
+// supervisorLogRotateProgram runs the rotation of the nginx and PHP logs (supervisor rotates
+// its own)
+const supervisorLogRotateProgram = `
+
+# Rotation of the nginx and PHP logs under /var/log (logging in ~/.phpier/config.yaml)
+[program:logrotate]
+command=/usr/local/bin/phpier-logrotate
+autostart=true
+autorestart=true
+priority=1
+stdout_logfile=NONE
+stderr_logfile=NONE
+user=root`
+
+// logRotateScript returns the script that rotates the nginx and PHP logs once they reach the
+// configured size. Logs are copied and truncated in place, so nginx and php-fpm keep writing
+// to the files they have open.
+func logRotateScript(logging config.LoggingConfig) string {
+	if logging.MaxBytes() == 0 {
+		return "#!/bin/sh\n# Logs are unbounded: logging.max_size is empty in ~/.phpier/config.yaml\nexit 0\n"
+	}
+	return fmt.Sprintf(`#!/bin/sh
+# Rotates the nginx and PHP logs once they reach %[1]s, keeping %[2]d rotated copies.
+# Generated by phpier from logging in ~/.phpier/config.yaml.
+
+max_size=%[3]d
+keep=%[2]d
+
+while true; do
+    for log in /var/log/nginx/*.log /var/log/php/*.log; do
+        [ -f "$log" ] || continue
+        [ "$(stat -c %%s "$log")" -gt "$max_size" ] || continue
+
+        i=$keep
+        while [ "$i" -gt 1 ]; do
+            [ -f "$log.$((i - 1))" ] && mv -f "$log.$((i - 1))" "$log.$i"
+            i=$((i - 1))
+        done
+        [ "$keep" -gt 0 ] && cp -p "$log" "$log.1"
+        : > "$log"
+    done
+    sleep 300
+done
+`, logging.MaxSize, logging.MaxFiles-1, logging.MaxBytes())
+}
+
+// supervisorDevServerProgram returns the supervisor program for the frontend dev server.
+// It doesn't start with the container; 'phpier dev' starts and stops it.
+func supervisorDevServerProgram(projectCfg *config.ProjectConfig, maxBytes int64, backups int) string {
+	return fmt.Sprintf(`
+
+# Frontend dev server (started with 'phpier dev')
+[program:devserver]
+command=/bin/sh -c "%[1]s"
+directory=/var/www/html
+autostart=false
+autorestart=true
+priority=20
+stdout_logfile=/var/log/supervisor/devserver.log
+stderr_logfile=/var/log/supervisor/devserver-error.log
+stdout_logfile_maxbytes=%[2]d
+stdout_logfile_backups=%[3]d
+stderr_logfile_maxbytes=%[2]d
+stderr_logfile_backups=%[3]d
+user=phpier
+environment=HOME="/home/phpier"
+killasgroup=true
+stopasgroup=true`, devServerCommand(projectCfg), maxBytes, backups)
+}
+
+// devServerCommand escapes the dev server command for a quoted supervisor command line,
+// where % starts a supervisor expansion
+func devServerCommand(projectCfg *config.ProjectConfig) string {
+	command := strings.ReplaceAll(projectCfg.DevServerCommand(), "%", "%%")
+	return strings.ReplaceAll(command, `"`, `\"`)
+}
+
+// GenerateProjectCompose renders the project's .phpier.yml, including the x-phpier settings block.
+// Plaintext basic auth passwords are replaced by bcrypt hashes before rendering, so only hashes are stored.
+func GenerateProjectCompose(engine *templates.Engine, projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig) error {
+	dockerCompose, err := RenderProjectCompose(engine, projectCfg, globalCfg)
+	if err != nil {
+		return err
+	}
+	if err := WriteFile(".phpier.yml", dockerCompose); err != nil {
+		return err
+	}
+
+	// Traefik can't read labels without a Docker API, so route the app through a file instead
+	if r, err := runtime.Detect(globalCfg.RuntimePreference()); err == nil && r.Name() == runtime.Nerdctl {
+		return GenerateRoute(engine, ProjectRoute(projectCfg, globalCfg), globalCfg)
+	}
+	return nil
+}
+
+// RenderProjectCompose validates the project settings and renders the .phpier.yml that
+// GenerateProjectCompose writes, without writing it
+func RenderProjectCompose(engine *templates.Engine, projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig) (string, error) {
+	if err := projectCfg.ValidateMiddlewares(); err != nil {
+		return "", err
+	}
+	if err := projectCfg.Resources.Validate("resources"); err != nil {
+		return "", err
+	}
+	if err := projectCfg.DevServer.Validate(); err != nil {
+		return "", err
+	}
+	if err := projectCfg.Middlewares.BasicAuth.Hash(); err != nil {
+		return "", err
+	}
+
+	dockerCompose, err := engine.RenderProjectDockerCompose(projectCfg, globalCfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to render .phpier.yml: %w", err)
+	}
+	return dockerCompose, nil
+}
+
+// ProjectRoute returns the file-provider route to a project's app container, used on runtimes
+// whose containers Traefik can't discover
+func ProjectRoute(projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig) *routes.Route {
+	return &routes.Route{
+		Name:  projectCfg.Name,
+		Hosts: projectCfg.Domains(globalCfg.Traefik.Domain),
+		URL:   "http://" + projectCfg.Name + "-app",
+	}
+}
+
+// LoadDockerfileHooks reads *.dockerfile snippets from dir and groups them by hook point.
+// A snippet belongs to a hook when its file name is the hook name or starts with "<hook>-",
+// e.g. after-system-deps-oracle.dockerfile. Snippets for the same hook are joined in name order.
+func LoadDockerfileHooks(dir string) (map[string]string, error) {
+	hooks := make(map[string]string)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.dockerfile"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Dockerfile snippets in %s: %w", dir, err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".dockerfile")
+
+		hook := ""
+		for _, candidate := range templates.DockerfileHooks {
+			if name == candidate || strings.HasPrefix(name, candidate+"-") {
+				hook = candidate
+				break
+			}
+		}
+		if hook == "" {
+			logrus.Warnf("Ignoring Dockerfile snippet %s: name must start with one of %s", file, strings.Join(templates.DockerfileHooks, ", "))
+			continue
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Dockerfile snippet %s: %w", file, err)
+		}
+
+		snippet := strings.TrimSpace(string(content))
+		if snippet == "" {
+			continue
+		}
+		if hook != templates.HookFinal {
+			if err := checkBaseImageSnippet(snippet); err != nil {
+				return nil, fmt.Errorf("Dockerfile snippet %s: %w", file, err)
+			}
+		}
+		if hooks[hook] != "" {
+			hooks[hook] += "\n"
+		}
+		hooks[hook] += snippet
+		logrus.Debugf("Including Dockerfile snippet %s at hook %s", file, hook)
+	}
+
+	return hooks, nil
+}
+
+// checkBaseImageSnippet rejects instructions that read from the build context in a snippet
+// for the shared base image, which is built from ~/.phpier/images rather than the project:
+// COPY and ADD, except COPY --from and ADD of a URL
+func checkBaseImageSnippet(snippet string) error {
+	continued := false
+	for _, line := range strings.Split(snippet, "\n") {
+		line = strings.TrimSpace(line)
+		startsInstruction := !continued && line != "" && !strings.HasPrefix(line, "#")
+		continued = strings.HasSuffix(line, "\\")
+		if !startsInstruction {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		instruction := strings.ToUpper(fields[0])
+		if instruction != "COPY" && instruction != "ADD" {
+			continue
+		}
+		external := false
+		for _, arg := range fields[1:] {
+			if instruction == "COPY" && strings.HasPrefix(arg, "--from=") ||
+				instruction == "ADD" && (strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://")) {
+				external = true
+				break
+			}
+		}
+		if !external {
+			return fmt.Errorf("%s reads from the build context, but this hook runs in the shared base image, "+
+				"which is built without the project files; move it to a final-*.dockerfile snippet", instruction)
+		}
+	}
+	return nil
+}
+
+// BaseImage describes a shared phpier-base image and where its build context lives.
+type BaseImage struct {
+	Tag        string
+	ContextDir string
+}
+
+// PrepareBaseImage renders the shared base Dockerfile for a project and writes its build context
+// to ~/.phpier/images/<php>-<hash>. The hash covers the rendered Dockerfile and any CA certificates,
+// so projects with the same PHP version, extensions and tools resolve to the same base image.
+func PrepareBaseImage(engine *templates.Engine, projectCfg *config.ProjectConfig, hooks map[string]string) (*BaseImage, error) {
+	dockerfile, err := engine.RenderPHPBaseDockerfile(projectCfg, hooks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render base Dockerfile: %w", err)
+	}
+
+	certs, err := readCACertificates(projectCfg.Build.CACertificates)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(dockerfile))
+	names := make([]string, 0, len(certs))
+	for name := range certs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hash.Write([]byte(name))
+		hash.Write([]byte(certs[name]))
+	}
+
+	version := fmt.Sprintf("%s-%s", projectCfg.PHP, hex.EncodeToString(hash.Sum(nil))[:12])
+	imagesDir, err := config.BaseImagesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	base := &BaseImage{
+		Tag:        "phpier-base:" + version,
+		ContextDir: filepath.Join(imagesDir, version),
+	}
+
+	if err := WriteFile(filepath.Join(base.ContextDir, "Dockerfile"), dockerfile); err != nil {
+		return nil, err
+	}
+	certsDir := filepath.Join(base.ContextDir, "certs")
+	if err := os.MkdirAll(certsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", certsDir, err)
+	}
+	for _, name := range names {
+		if err := WriteFile(filepath.Join(certsDir, name), certs[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	logrus.Debugf("Prepared base image %s in %s", base.Tag, base.ContextDir)
+	return base, nil
+}
+
+// readCACertificates reads host CA certificates keyed by their .crt file name for update-ca-certificates.
+func readCACertificates(paths []string) (map[string]string, error) {
+	certs := make(map[string]string)
+	for _, cert := range paths {
+		content, err := os.ReadFile(expandHome(cert))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate %s: %w", cert, err)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(cert), filepath.Ext(cert)) + ".crt"
+		certs[name] = string(content)
+	}
+	return certs, nil
+}
+
+// expandHome expands a leading ~ in path to the user's home directory.
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
 // GenerateGlobalFiles generates all necessary files for the global services stack.
 func GenerateGlobalFiles(engine *templates.Engine, globalCfg *config.GlobalConfig) error {
-	// Generate docker-compose.yml for the global stack
-	dockerCompose, err := engine.RenderGlobalDockerCompose(globalCfg)
+	globalCfg, err := prepareGlobalConfig(globalCfg)
 	if err != nil {
-		return fmt.Errorf("failed to render global docker-compose.yml: %w", err)
+		return err
 	}
 
 	home, err := os.UserHomeDir()
@@ -121,32 +476,337 @@ func GenerateGlobalFiles(engine *templates.Engine, globalCfg *config.GlobalConfi
 		return fmt.Errorf("failed to get user home directory: %w", err)
 	}
 
-	globalPath := filepath.Join(home, ".phpier")
-	if err := WriteFile(filepath.Join(globalPath, "docker-compose.yml"), dockerCompose); err != nil {
+	// Generate docker-compose.yml and the static Traefik configuration
+	files, err := renderGlobalStaticFiles(engine, globalCfg)
+	if err != nil {
 		return err
 	}
+	for name, content := range files {
+		if err := WriteFile(filepath.Join(home, ".phpier", name), content); err != nil {
+			return err
+		}
+	}
+
+	if globalCfg.DNS.Enabled {
+		if err := stageDNSBinary(filepath.Join(home, ".phpier", "dns")); err != nil {
+			return err
+		}
+	}
+
+	return generateTraefikDynamicFiles(engine, globalCfg)
+}
+
+// stageDNSBinary copies the running phpier binary into dir, which the dns container mounts
+// to run 'phpier dns serve'.
+func stageDNSBinary(dir string) error {
+	if goruntime.GOOS != "linux" {
+		return fmt.Errorf("the dns container runs the phpier binary and needs a Linux build of phpier; on %s, disable dns.enabled and run 'phpier dns serve' on the host instead", goruntime.GOOS)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the phpier binary: %w", err)
+	}
+	source, err := os.Open(executable)
+	if err != nil {
+		return fmt.Errorf("failed to open the phpier binary: %w", err)
+	}
+	defer source.Close()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	// Write to a temporary file first, so a running container never sees a half-written binary
+	target, err := os.CreateTemp(dir, ".phpier-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file in %s: %w", dir, err)
+	}
+	defer os.Remove(target.Name())
+
+	if _, err := io.Copy(target, source); err != nil {
+		target.Close()
+		return fmt.Errorf("failed to copy the phpier binary to %s: %w", dir, err)
+	}
+	if err := target.Close(); err != nil {
+		return fmt.Errorf("failed to write the phpier binary to %s: %w", dir, err)
+	}
+	if err := os.Chmod(target.Name(), 0755); err != nil {
+		return fmt.Errorf("failed to make the phpier binary executable: %w", err)
+	}
+	if err := os.Rename(target.Name(), filepath.Join(dir, "phpier")); err != nil {
+		return fmt.Errorf("failed to install the phpier binary in %s: %w", dir, err)
+	}
+
+	logrus.Debugf("Copied %s to %s for the dns container", executable, dir)
+	return nil
+}
+
+// GlobalStaticFiles renders the global files whose changes only apply when containers are
+// recreated (docker-compose.yml and traefik/traefik.yml), keyed by their path under ~/.phpier.
+func GlobalStaticFiles(engine *templates.Engine, globalCfg *config.GlobalConfig) (map[string]string, error) {
+	globalCfg, err := prepareGlobalConfig(globalCfg)
+	if err != nil {
+		return nil, err
+	}
+	return renderGlobalStaticFiles(engine, globalCfg)
+}
+
+func renderGlobalStaticFiles(engine *templates.Engine, globalCfg *config.GlobalConfig) (map[string]string, error) {
+	var dnsAnswer string
+	if globalCfg.DNS.Enabled {
+		// "lan" is resolved here, on the host; inside the container it would be the container's address
+		ip, err := dns.AnswerIP(globalCfg.DNS.IP)
+		if err != nil {
+			return nil, err
+		}
+		dnsAnswer = ip.String()
+	}
+
+	dockerCompose, err := engine.RenderGlobalDockerCompose(globalCfg, dnsAnswer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render global docker-compose.yml: %w", err)
+	}
 
-	// Generate Traefik configuration
 	traefikConfig, err := engine.RenderTraefikConfig(globalCfg)
 	if err != nil {
-		return fmt.Errorf("failed to render traefik config: %w", err)
+		return nil, fmt.Errorf("failed to render traefik config: %w", err)
 	}
-	if err := WriteFile(filepath.Join(globalPath, "traefik", "traefik.yml"), traefikConfig); err != nil {
+
+	return map[string]string{
+		"docker-compose.yml":                    dockerCompose,
+		filepath.Join("traefik", "traefik.yml"): traefikConfig,
+	}, nil
+}
+
+// GenerateTraefikDynamicFiles renders the Traefik files that are picked up without a restart:
+// routers and middlewares in traefik/dynamic/api.yml and the default certificate.
+func GenerateTraefikDynamicFiles(engine *templates.Engine, globalCfg *config.GlobalConfig) error {
+	globalCfg, err := prepareGlobalConfig(globalCfg)
+	if err != nil {
 		return err
 	}
+	return generateTraefikDynamicFiles(engine, globalCfg)
+}
+
+func generateTraefikDynamicFiles(engine *templates.Engine, globalCfg *config.GlobalConfig) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get user home directory: %w", err)
+	}
 
-	// Generate Traefik dynamic configuration
 	traefikDynamic, err := engine.RenderTraefikDynamicConfig(globalCfg)
 	if err != nil {
 		return fmt.Errorf("failed to render traefik dynamic config: %w", err)
 	}
-	if err := WriteFile(filepath.Join(globalPath, "traefik", "dynamic", "api.yml"), traefikDynamic); err != nil {
+	if err := WriteFile(filepath.Join(home, ".phpier", "traefik", "dynamic", "api.yml"), traefikDynamic); err != nil {
 		return err
 	}
 
+	// Issue the default wildcard certificate for global tools and unknown hosts
+	if err := GenerateGlobalCertificate(engine, globalCfg); err != nil {
+		return fmt.Errorf("failed to generate TLS certificate: %w", err)
+	}
+
 	return nil
 }
 
+// prepareGlobalConfig validates globalCfg and returns a copy ready for rendering:
+// dashboard passwords are hashed and the access log path is made absolute.
+func prepareGlobalConfig(globalCfg *config.GlobalConfig) (*config.GlobalConfig, error) {
+	if err := globalCfg.Traefik.Validate(); err != nil {
+		return nil, err
+	}
+	if err := globalCfg.ValidateResources(); err != nil {
+		return nil, err
+	}
+	if err := globalCfg.Services.Databases.Validate(); err != nil {
+		return nil, err
+	}
+	if !runtime.ValidName(globalCfg.RuntimePreference()) {
+		return nil, fmt.Errorf("invalid runtime %q: must be one of auto, %s", globalCfg.RuntimePreference(), strings.Join(runtime.Names, ", "))
+	}
+
+	prepared := *globalCfg
+	if len(globalCfg.Traefik.DashboardAuth) > 0 {
+		prepared.Traefik.DashboardAuth = make(config.BasicAuthUsers, len(globalCfg.Traefik.DashboardAuth))
+		for user, password := range globalCfg.Traefik.DashboardAuth {
+			prepared.Traefik.DashboardAuth[user] = password
+		}
+		if err := prepared.Traefik.DashboardAuth.Hash(); err != nil {
+			return nil, err
+		}
+	}
+
+	if path := prepared.Traefik.AccessLog.Path; path != "" {
+		absPath, err := filepath.Abs(expandHome(path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve traefik.access_log.path: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(absPath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create access log directory: %w", err)
+		}
+		prepared.Traefik.AccessLog.Path = absPath
+	}
+
+	return &prepared, nil
+}
+
+// GenerateGlobalCertificate issues the default certificate for the global domain and the global
+// tool hosts and registers it as Traefik's default certificate in traefik/dynamic/tls.yml.
+func GenerateGlobalCertificate(engine *templates.Engine, globalCfg *config.GlobalConfig) error {
+	domain := globalCfg.Traefik.Domain
+	hosts := append([]string{domain}, globalCfg.ToolHosts()...)
+	// Browsers reject a wildcard directly under a single label such as localhost or test, so
+	// projects and routes get certificates for their own hosts instead
+	if strings.Contains(domain, ".") {
+		hosts = append(hosts, "*."+domain)
+	}
+	hosts = append(hosts, "localhost", "127.0.0.1", "::1")
+	return generateCertificate(engine, "default", hosts, "tls.yml", true)
+}
+
+// projectCertPrefix keeps project certificates apart from the default certificate and route
+// certificates, whatever the project is called
+const projectCertPrefix = "project-"
+
+// GenerateProjectCertificate issues a certificate for the project's domain and its subdomains and
+// registers it in traefik/dynamic/tls-project-<project>.yml. Traefik picks it up without a restart.
+func GenerateProjectCertificate(engine *templates.Engine, projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig) error {
+	domain := projectCfg.Name + "." + globalCfg.Traefik.Domain
+	hosts := []string{domain, "*." + domain}
+	name := projectCertPrefix + projectCfg.Name
+	if err := generateCertificate(engine, name, hosts, "tls-"+name+".yml", false); err != nil {
+		return err
+	}
+	return removeLegacyProjectCertificate(projectCfg.Name)
+}
+
+// removeLegacyProjectCertificate deletes the files of a project certificate issued under the bare
+// project name. Names that belong to the default or a route certificate are left alone.
+func removeLegacyProjectCertificate(project string) error {
+	if project == "default" || strings.HasPrefix(project, routes.FilePrefix) {
+		return nil
+	}
+
+	dir, err := TraefikDynamicDir()
+	if err != nil {
+		return err
+	}
+	certsDir, err := certs.DefaultCertsDir()
+	if err != nil {
+		return err
+	}
+
+	paths := []string{
+		filepath.Join(dir, "tls-"+project+".yml"),
+		filepath.Join(certsDir, project+".pem"),
+		filepath.Join(certsDir, project+"-key.pem"),
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// TraefikDynamicDir returns the directory Traefik's file provider watches (~/.phpier/traefik/dynamic)
+func TraefikDynamicDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".phpier", "traefik", "dynamic"), nil
+}
+
+// GenerateRoute writes the dynamic Traefik file for a route to an upstream URL and issues
+// a certificate for its hosts. Traefik picks both up without a restart.
+func GenerateRoute(engine *templates.Engine, route *routes.Route, globalCfg *config.GlobalConfig) error {
+	if err := route.Validate(); err != nil {
+		return err
+	}
+
+	dir, err := TraefikDynamicDir()
+	if err != nil {
+		return err
+	}
+
+	routeConfig, err := engine.RenderTraefikRouteConfig(route, globalCfg)
+	if err != nil {
+		return fmt.Errorf("failed to render route %s: %w", route.Name, err)
+	}
+
+	certName := routes.CertName(route.Name)
+	if err := generateCertificate(engine, certName, route.Hosts, "tls-"+certName+".yml", false); err != nil {
+		return fmt.Errorf("failed to issue certificate for route %s: %w", route.Name, err)
+	}
+
+	return WriteFile(filepath.Join(dir, routes.FileName(route.Name)), routeConfig)
+}
+
+// RemoveRoute deletes a route's dynamic Traefik files and its certificate
+func RemoveRoute(name string) error {
+	dir, err := TraefikDynamicDir()
+	if err != nil {
+		return err
+	}
+	certsDir, err := certs.DefaultCertsDir()
+	if err != nil {
+		return err
+	}
+
+	certName := routes.CertName(name)
+	paths := []string{
+		filepath.Join(dir, routes.FileName(name)),
+		filepath.Join(dir, "tls-"+certName+".yml"),
+		filepath.Join(certsDir, certName+".pem"),
+		filepath.Join(certsDir, certName+"-key.pem"),
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// generateCertificate issues a certificate signed by the phpier CA and writes the matching dynamic Traefik file
+func generateCertificate(engine *templates.Engine, name string, hosts []string, dynamicFile string, isDefault bool) error {
+	caDir, err := certs.DefaultCADir()
+	if err != nil {
+		return err
+	}
+	certsDir, err := certs.DefaultCertsDir()
+	if err != nil {
+		return err
+	}
+
+	ca, err := certs.EnsureCA(caDir)
+	if err != nil {
+		return err
+	}
+
+	issued, err := ca.Issue(certsDir, name, hosts)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := engine.RenderTraefikTLSConfig([]templates.TLSCertificate{{
+		CertFile: "/etc/traefik/certs/" + filepath.Base(issued.CertFile),
+		KeyFile:  "/etc/traefik/certs/" + filepath.Base(issued.KeyFile),
+		Default:  isDefault,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to render TLS config: %w", err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return WriteFile(filepath.Join(home, ".phpier", "traefik", "dynamic", dynamicFile), tlsConfig)
+}
+
 // CreateProjectDirectories creates the directory structure for a new project.
 func CreateProjectDirectories() error {
 	dirs := []string{
@@ -155,6 +815,7 @@ func CreateProjectDirectories() error {
 		".phpier/docker/php",
 		".phpier/docker/nginx",
 		".phpier/docker/supervisor",
+		".phpier/Dockerfile.d",
 		".phpier/logs",
 		".phpier/logs/nginx",
 		".phpier/logs/php",
@@ -270,6 +931,7 @@ func CreateGlobalDirectories() error {
 		filepath.Join(home, ".phpier"),
 		filepath.Join(home, ".phpier", "traefik"),
 		filepath.Join(home, ".phpier", "traefik", "dynamic"),
+		filepath.Join(home, ".phpier", "certs"),
 	}
 
 	for _, dir := range dirs {
@@ -0,0 +1,204 @@
+package hosts
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+const (
+	// BeginMarker starts the phpier managed block in the hosts file
+	BeginMarker = "# BEGIN phpier - managed by 'phpier hosts sync', do not edit"
+	// EndMarker ends the phpier managed block in the hosts file
+	EndMarker = "# END phpier"
+
+	// BackupSuffix is appended to the hosts file path for the backup taken before each write
+	BackupSuffix = ".phpier.bak"
+)
+
+// Entry maps a group of host names to an IP address
+type Entry struct {
+	IP      string
+	Hosts   []string
+	Comment string
+}
+
+// DefaultPath returns the system hosts file path
+func DefaultPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("SystemRoot"), "System32", "drivers", "etc", "hosts")
+	}
+	return "/etc/hosts"
+}
+
+// RenderBlock renders entries as a delimited phpier block
+func RenderBlock(entries []Entry) string {
+	var b strings.Builder
+	b.WriteString(BeginMarker + "\n")
+	for _, entry := range entries {
+		if len(entry.Hosts) == 0 {
+			continue
+		}
+		if entry.Comment != "" {
+			b.WriteString("# " + entry.Comment + "\n")
+		}
+		b.WriteString(entry.IP + "\t" + strings.Join(entry.Hosts, " ") + "\n")
+	}
+	b.WriteString(EndMarker + "\n")
+	return b.String()
+}
+
+// ReplaceBlock returns content with the phpier block replaced by block, or appended when missing.
+// Everything outside the block is left untouched.
+func ReplaceBlock(content, block string) string {
+	before, after, found := splitBlock(content)
+	if !found {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if content != "" && !strings.HasSuffix(content, "\n\n") {
+			content += "\n"
+		}
+		return content + block
+	}
+	return before + block + after
+}
+
+// RemoveBlock returns content without the phpier block
+func RemoveBlock(content string) string {
+	before, after, found := splitBlock(content)
+	if !found {
+		return content
+	}
+	// Drop the blank separator line added in front of the block
+	if strings.HasSuffix(before, "\n\n") && after == "" {
+		before = strings.TrimSuffix(before, "\n")
+	}
+	return before + after
+}
+
+// ManagedHosts returns the host names listed in the phpier block, sorted
+func ManagedHosts(content string) []string {
+	var result []string
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == BeginMarker:
+			inBlock = true
+		case trimmed == EndMarker:
+			inBlock = false
+		case inBlock && trimmed != "" && !strings.HasPrefix(trimmed, "#"):
+			fields := strings.Fields(trimmed)
+			result = append(result, fields[1:]...)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// HasBlock reports whether content contains a phpier block
+func HasBlock(content string) bool {
+	_, _, found := splitBlock(content)
+	return found
+}
+
+// splitBlock returns the content before and after the phpier block
+func splitBlock(content string) (string, string, bool) {
+	start := strings.Index(content, BeginMarker)
+	if start == -1 {
+		return content, "", false
+	}
+	end := strings.Index(content[start:], EndMarker)
+	if end == -1 {
+		return content, "", false
+	}
+	end += start + len(EndMarker)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return content[:start], content[end:], true
+}
+
+// WriteAtomic backs up the hosts file at path and replaces it with content.
+// The new content is written to a temporary file next to it and renamed into place.
+func WriteAtomic(path, content string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	if err := copyFile(path, path+BackupSuffix, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".hosts.phpier-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		// Bind-mounted hosts files (e.g. inside containers) can't be replaced by rename
+		if writeErr := os.WriteFile(path, []byte(content), info.Mode().Perm()); writeErr != nil {
+			return fmt.Errorf("failed to replace %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// Writable reports whether the current user can write the hosts file and its directory
+func Writable(path string) bool {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return false
+	}
+	file.Close()
+
+	probe, err := os.CreateTemp(filepath.Dir(path), ".hosts.phpier-probe-*")
+	if err != nil {
+		return false
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return true
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
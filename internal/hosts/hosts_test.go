@@ -0,0 +1,68 @@
+package hosts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const systemHosts = "127.0.0.1\tlocalhost\n::1\tlocalhost\n"
+
+func testEntries() []Entry {
+	return []Entry{
+		{IP: "127.0.0.1", Hosts: []string{"phpier-traefik.test", "phpier-mailpit.test"}, Comment: "global tools"},
+		{IP: "127.0.0.1", Hosts: []string{"shop.test"}, Comment: "shop"},
+	}
+}
+
+func TestReplaceBlock_AppendsAndReplaces(t *testing.T) {
+	block := RenderBlock(testEntries())
+
+	updated := ReplaceBlock(systemHosts, block)
+	assert.Contains(t, updated, systemHosts)
+	assert.Contains(t, updated, BeginMarker)
+	assert.Equal(t, []string{"phpier-mailpit.test", "phpier-traefik.test", "shop.test"}, ManagedHosts(updated))
+
+	// Running again with the same block is a no-op
+	assert.Equal(t, updated, ReplaceBlock(updated, block))
+
+	// A changed block replaces the old one in place
+	changed := ReplaceBlock(updated+"10.0.0.1\tnas\n", RenderBlock([]Entry{{IP: "127.0.0.1", Hosts: []string{"blog.test"}}}))
+	assert.Equal(t, []string{"blog.test"}, ManagedHosts(changed))
+	assert.Contains(t, changed, "10.0.0.1\tnas\n", "entries after the block are preserved")
+}
+
+func TestRemoveBlock(t *testing.T) {
+	updated := ReplaceBlock(systemHosts, RenderBlock(testEntries()))
+
+	assert.Equal(t, systemHosts, RemoveBlock(updated))
+	assert.False(t, HasBlock(RemoveBlock(updated)))
+	assert.Equal(t, systemHosts, RemoveBlock(systemHosts), "content without a block is unchanged")
+}
+
+func TestWriteAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts")
+	require.NoError(t, os.WriteFile(path, []byte(systemHosts), 0644))
+
+	updated := ReplaceBlock(systemHosts, RenderBlock(testEntries()))
+	require.NoError(t, WriteAtomic(path, updated))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, updated, string(content))
+
+	backup, err := os.ReadFile(path + BackupSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, systemHosts, string(backup), "the previous content is backed up")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm(), "permissions are preserved")
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "no temporary files are left behind")
+}
@@ -0,0 +1,271 @@
+// Package logfiles reads the log files the app container writes to .phpier/logs on the host.
+package logfiles
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Dir is the project directory the app container's /var/log/{nginx,php,supervisor} are mounted in
+const Dir = ".phpier/logs"
+
+// File is a log file the app container writes
+type File struct {
+	Name        string // Name used by 'phpier logs --file'
+	Path        string // Path relative to Dir
+	Description string
+}
+
+// Files are the log files 'phpier logs --file' can show
+var Files = []File{
+	{Name: "nginx-access", Path: "nginx/access.log", Description: "Nginx access log"},
+	{Name: "nginx-error", Path: "nginx/error.log", Description: "Nginx error log"},
+	{Name: "php-fpm", Path: "php/php-fpm.log", Description: "PHP-FPM master and pool log"},
+	{Name: "php-errors", Path: "php/errors.log", Description: "PHP errors, warnings and notices"},
+	{Name: "supervisor", Path: "supervisor/supervisord.log", Description: "Supervisor process log"},
+}
+
+// Lookup returns the log file with the given name
+func Lookup(name string) (File, bool) {
+	for _, file := range Files {
+		if file.Name == name {
+			return file, true
+		}
+	}
+	return File{}, false
+}
+
+// Names returns the names of all log files
+func Names() []string {
+	names := make([]string, len(Files))
+	for i, file := range Files {
+		names[i] = file.Name
+	}
+	return names
+}
+
+// HostPath returns the path of the file below the project directory projectDir
+func (f File) HostPath(projectDir string) string {
+	return filepath.Join(projectDir, Dir, filepath.FromSlash(f.Path))
+}
+
+// pollInterval is how often a followed file is checked for new lines
+var pollInterval = 250 * time.Millisecond
+
+// tailChunk is how much of a file is read at a time while looking for its last lines
+const tailChunk = 64 * 1024
+
+// TailOptions selects the lines Tail reads
+type TailOptions struct {
+	Lines  int    // Start this many lines from the end; 0 reads the whole file
+	Follow bool   // Keep reading lines as they are written, until ctx is cancelled
+	Idle   func() // Called whenever a followed file has no more lines for now
+}
+
+// Tail calls line for each line of the file at path. When following, a file that is truncated
+// is read again from the start, and one that is rotated (replaced by a new file) is reopened.
+func Tail(ctx context.Context, path string, options TailOptions, line func(string)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { file.Close() }()
+
+	if options.Lines > 0 {
+		offset, err := lastLinesOffset(file, options.Lines)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	reader := bufio.NewReader(file)
+	var partial string
+	for {
+		text, err := reader.ReadString('\n')
+		if err == nil {
+			line(partial + strings.TrimRight(text, "\r\n"))
+			partial = ""
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+		// A line without its newline yet; the rest follows when the writer finishes it
+		partial += text
+
+		if !options.Follow {
+			if partial != "" {
+				line(partial)
+			}
+			return nil
+		}
+		if options.Idle != nil {
+			options.Idle()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(pollInterval):
+		}
+
+		reopened, truncated, err := checkReplaced(path, file)
+		if err != nil {
+			return err
+		}
+		if reopened != nil {
+			// Whatever the old file had left has been read, so start on the new one
+			file.Close()
+			file = reopened
+		}
+		if reopened != nil || truncated {
+			reader.Reset(file)
+			partial = ""
+		}
+	}
+}
+
+// checkReplaced returns the newly opened file at path when it is no longer file (file was
+// rotated), or rewinds file and reports true when it was truncated
+func checkReplaced(path string, file *os.File) (*os.File, bool, error) {
+	current, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		// Rotated and not created again yet
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	opened, err := file.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !os.SameFile(current, opened) {
+		reopened, err := os.Open(path)
+		return reopened, false, err
+	}
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, false, err
+	}
+	if current.Size() < offset {
+		_, err := file.Seek(0, io.SeekStart)
+		return nil, err == nil, err
+	}
+	return nil, false, nil
+}
+
+// lastLinesOffset returns the offset of the start of the last n lines of file
+func lastLinesOffset(file *os.File, n int) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	end := info.Size()
+	buf := make([]byte, tailChunk)
+	newlines := 0
+	for end > 0 {
+		start := end - tailChunk
+		if start < 0 {
+			start = 0
+		}
+		chunk := buf[:end-start]
+		if _, err := file.ReadAt(chunk, start); err != nil && err != io.EOF {
+			return 0, err
+		}
+		for i := len(chunk) - 1; i >= 0; i-- {
+			if chunk[i] != '\n' {
+				continue
+			}
+			// The newline ending the file doesn't start a line
+			if start+int64(i) == info.Size()-1 {
+				continue
+			}
+			newlines++
+			if newlines == n {
+				return start + int64(i) + 1, nil
+			}
+		}
+		end = start
+	}
+	return 0, nil
+}
+
+// lineTimePatterns match the timestamps the app container's log files start their lines with
+var lineTimePatterns = []struct {
+	pattern *regexp.Regexp
+	layout  string
+}{
+	// PHP error log and PHP-FPM: [01-May-2024 10:00:00 UTC] or [01-May-2024 10:00:00]
+	{regexp.MustCompile(`^\[(\d{2}-[A-Za-z]{3}-\d{4} \d{2}:\d{2}:\d{2})(?: ([^\]]+))?\]`), "02-Jan-2006 15:04:05"},
+	// Nginx access log: ... [01/May/2024:10:00:00 +0000] ...
+	{regexp.MustCompile(`\[(\d{2}/[A-Za-z]{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4})\]`), "02/Jan/2006:15:04:05 -0700"},
+	// Nginx error log: 2024/05/01 10:00:00 [error] ...
+	{regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2})`), "2006/01/02 15:04:05"},
+	// Supervisor: 2024-05-01 10:00:00,123 INFO ...
+	{regexp.MustCompile(`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}),\d+`), "2006-01-02 15:04:05"},
+}
+
+// LineTime returns the time a log line starts with, if it starts with one of the formats of
+// the app container's log files. Times without a zone are taken as UTC, the container's zone.
+func LineTime(line string) (time.Time, bool) {
+	for _, p := range lineTimePatterns {
+		match := p.pattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		location := time.UTC
+		if len(match) > 2 && match[2] != "" {
+			if loc, err := time.LoadLocation(match[2]); err == nil {
+				location = loc
+			}
+		}
+		if t, err := time.ParseInLocation(p.layout, match[1], location); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ParseSince parses a --since value: a duration before now such as 1h, an RFC 3339 time or a date
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use a duration such as 1h or a time such as 2024-05-01T10:00:00Z", value)
+}
+
+// SinceFilter passes the lines written at or after a time. Lines without a timestamp, such as
+// stack traces, go with the line before them.
+type SinceFilter struct {
+	Since    time.Time
+	previous bool
+}
+
+// Match reports whether line passes the filter
+func (f *SinceFilter) Match(line string) bool {
+	if f.Since.IsZero() {
+		return true
+	}
+	if t, ok := LineTime(line); ok {
+		f.previous = !t.Before(f.Since)
+	}
+	return f.previous
+}
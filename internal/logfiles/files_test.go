@@ -0,0 +1,154 @@
+package logfiles
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	file, ok := Lookup("php-errors")
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join("/srv/shop", ".phpier", "logs", "php", "errors.log"), file.HostPath("/srv/shop"))
+
+	_, ok = Lookup("mysql")
+	assert.False(t, ok)
+}
+
+func TestTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	require.NoError(t, os.WriteFile(path, []byte("one\ntwo\nthree\nfour\n"), 0o644))
+
+	var lines []string
+	collect := func(line string) { lines = append(lines, line) }
+
+	require.NoError(t, Tail(context.Background(), path, TailOptions{Lines: 2}, collect))
+	assert.Equal(t, []string{"three", "four"}, lines)
+
+	lines = nil
+	require.NoError(t, Tail(context.Background(), path, TailOptions{}, collect))
+	assert.Equal(t, []string{"one", "two", "three", "four"}, lines)
+
+	lines = nil
+	require.NoError(t, Tail(context.Background(), path, TailOptions{Lines: 10}, collect))
+	assert.Len(t, lines, 4)
+}
+
+func TestLastLinesOffset_LargeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.log")
+	line := make([]byte, 999)
+	for i := range line {
+		line[i] = 'x'
+	}
+	var content []byte
+	for i := 0; i < 200; i++ {
+		content = append(append(content, line...), '\n')
+	}
+	require.NoError(t, os.WriteFile(path, content, 0o644))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	offset, err := lastLinesOffset(file, 150)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50*1000), offset)
+}
+
+func TestTail_Follow(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+	defer func() { pollInterval = 250 * time.Millisecond }()
+
+	path := filepath.Join(t.TempDir(), "error.log")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o644))
+
+	var mu sync.Mutex
+	var lines []string
+	received := func(n int) func() bool {
+		return func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(lines) >= n
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Tail(ctx, path, TailOptions{Follow: true}, func(line string) {
+			mu.Lock()
+			lines = append(lines, line)
+			mu.Unlock()
+		})
+	}()
+	require.Eventually(t, received(1), time.Second, 5*time.Millisecond)
+
+	// Appended lines, including one written in two parts
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	file.WriteString("appended\nhalf")
+	time.Sleep(30 * time.Millisecond)
+	file.WriteString(" a line\n")
+	file.Close()
+	require.Eventually(t, received(3), time.Second, 5*time.Millisecond)
+
+	// Rotated: the file is moved away and a new one is created
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, os.WriteFile(path, []byte("rotated\n"), 0o644))
+	require.Eventually(t, received(4), time.Second, 5*time.Millisecond)
+
+	// Truncated in place
+	require.NoError(t, os.WriteFile(path, []byte("new\n"), 0o644))
+	require.Eventually(t, received(5), time.Second, 5*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.Equal(t, []string{"old", "appended", "half a line", "rotated", "new"}, lines)
+}
+
+func TestLineTime(t *testing.T) {
+	want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for _, line := range []string{
+		`[01-May-2024 10:00:00 UTC] PHP Warning:  Undefined variable $x`,
+		`[01-May-2024 10:00:00] NOTICE: fpm is running, pid 1`,
+		`172.18.0.3 - - [01/May/2024:12:00:00 +0200] "GET / HTTP/1.1" 200 612`,
+		`2024/05/01 10:00:00 [error] 31#31: *1 open() failed`,
+		`2024-05-01 10:00:00,123 INFO success: nginx entered RUNNING state`,
+	} {
+		got, ok := LineTime(line)
+		require.True(t, ok, line)
+		assert.True(t, want.Equal(got), line)
+	}
+
+	_, ok := LineTime("#0 {main}")
+	assert.False(t, ok)
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	since, err := ParseSince("1h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-time.Hour), since)
+
+	since, err = ParseSince("2024-04-30T08:00:00Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 4, 30, 8, 0, 0, 0, time.UTC), since.UTC())
+
+	_, err = ParseSince("yesterday", now)
+	assert.Error(t, err)
+}
+
+func TestSinceFilter(t *testing.T) {
+	filter := &SinceFilter{Since: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+	assert.False(t, filter.Match("[01-May-2024 09:59:59 UTC] PHP Fatal error:  Uncaught Exception: old"))
+	assert.False(t, filter.Match("#0 {main}"), "trace lines go with their entry")
+	assert.True(t, filter.Match("[01-May-2024 10:00:00 UTC] PHP Fatal error:  Uncaught Exception: new"))
+	assert.True(t, filter.Match("#0 {main}"))
+}
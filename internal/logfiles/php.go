@@ -0,0 +1,166 @@
+package logfiles
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PHPError is an entry of the PHP error log, with the stack trace logged after it
+type PHPError struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"` // As PHP logs it: Fatal error, Warning, Notice, Deprecated, ...
+	Message string    `json:"message"`
+	File    string    `json:"file,omitempty"`
+	Line    int       `json:"line,omitempty"`
+	Trace   []string  `json:"trace,omitempty"`
+}
+
+// Severity maps the PHP level to the levels 'phpier logs --level' filters by
+func (e PHPError) Severity() string {
+	switch e.Level {
+	case "Fatal error", "Parse error", "Recoverable fatal error", "Core error", "Compile error":
+		return "critical"
+	case "Warning", "Core warning", "Compile warning":
+		return "warning"
+	case "Notice", "Deprecated", "Strict Standards":
+		return "notice"
+	case "":
+		return "info"
+	default:
+		return "error"
+	}
+}
+
+// Location returns the file and line the error was raised at, e.g. /var/www/html/index.php:3
+func (e PHPError) Location() string {
+	if e.File == "" {
+		return ""
+	}
+	return e.File + ":" + strconv.Itoa(e.Line)
+}
+
+var (
+	// phpEntryPattern matches the first line of an entry: [01-May-2024 10:00:00 UTC] PHP Warning:  ...
+	phpEntryPattern = regexp.MustCompile(`^\[[^\]]+\] (?:PHP ([A-Za-z ]+?):\s+)?(.*)$`)
+	// phpTracePattern matches the stack trace lines PHP logs as entries of their own
+	phpTracePattern = regexp.MustCompile(`^\[[^\]]+\] PHP (?:Stack trace:|\s+\d+\.)`)
+	// phpLocationPattern finds where an error was raised: "in /app/a.php on line 5" or,
+	// for uncaught exceptions, "in /app/a.php:5"
+	phpLocationPattern = regexp.MustCompile(` in (\S+?)(?: on line |:)(\d+)\s*$`)
+)
+
+// PHPErrorParser groups the lines of a PHP error log into entries. Lines are added one at a
+// time; an entry is complete when the next one starts, or when the log is flushed.
+type PHPErrorParser struct {
+	current *PHPError
+}
+
+// Add adds the next line of the log and returns the entry it completes, if any
+func (p *PHPErrorParser) Add(line string) *PHPError {
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+
+	t, timestamped := LineTime(line)
+	if !timestamped || phpTracePattern.MatchString(line) {
+		// Part of the stack trace of the current entry
+		if p.current == nil {
+			p.current = &PHPError{Message: line}
+			return nil
+		}
+		if timestamped {
+			line = line[strings.Index(line, "]")+2:]
+		}
+		p.current.Trace = append(p.current.Trace, line)
+		return nil
+	}
+
+	completed := p.Flush()
+	entry := &PHPError{Time: t, Message: line}
+	if match := phpEntryPattern.FindStringSubmatch(line); match != nil {
+		entry.Level = match[1]
+		entry.Message = match[2]
+	}
+	if match := phpLocationPattern.FindStringSubmatchIndex(entry.Message); match != nil {
+		entry.File = entry.Message[match[2]:match[3]]
+		entry.Line, _ = strconv.Atoi(entry.Message[match[4]:match[5]])
+		entry.Message = entry.Message[:match[0]]
+	}
+	p.current = entry
+	return completed
+}
+
+// Flush returns the entry being read, if any, as complete
+func (p *PHPErrorParser) Flush() *PHPError {
+	completed := p.current
+	p.current = nil
+	return completed
+}
+
+// ParsePHPErrors groups the lines of a PHP error log into entries
+func ParsePHPErrors(lines []string) []PHPError {
+	var parser PHPErrorParser
+	var entries []PHPError
+	for _, line := range lines {
+		if entry := parser.Add(line); entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+	if entry := parser.Flush(); entry != nil {
+		entries = append(entries, *entry)
+	}
+	return entries
+}
+
+// PHPErrorSummary counts the occurrences of one distinct PHP error
+type PHPErrorSummary struct {
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+	File      string    `json:"file,omitempty"`
+	Line      int       `json:"line,omitempty"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// SummarizePHPErrors counts the distinct errors (same level, message and location) among
+// entries, most frequent first
+func SummarizePHPErrors(entries []PHPError) []PHPErrorSummary {
+	index := make(map[string]int)
+	var summaries []PHPErrorSummary
+	for _, entry := range entries {
+		key := entry.Level + "\x00" + entry.Message + "\x00" + entry.Location()
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(summaries)
+			summaries = append(summaries, PHPErrorSummary{
+				Level:     entry.Level,
+				Message:   entry.Message,
+				File:      entry.File,
+				Line:      entry.Line,
+				FirstSeen: entry.Time,
+			})
+			i = len(summaries) - 1
+		}
+
+		summary := &summaries[i]
+		summary.Count++
+		if entry.Time.Before(summary.FirstSeen) {
+			summary.FirstSeen = entry.Time
+		}
+		if entry.Time.After(summary.LastSeen) {
+			summary.LastSeen = entry.Time
+		}
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Count != summaries[j].Count {
+			return summaries[i].Count > summaries[j].Count
+		}
+		return summaries[i].LastSeen.After(summaries[j].LastSeen)
+	})
+	return summaries
+}
@@ -0,0 +1,71 @@
+package logfiles
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const phpErrorLog = `[01-May-2024 10:00:00 UTC] PHP Fatal error:  Uncaught Exception: boom in /var/www/html/index.php:3
+Stack trace:
+#0 /var/www/html/public/index.php(12): run()
+#1 {main}
+  thrown in /var/www/html/index.php on line 3
+[01-May-2024 10:00:05 UTC] PHP Warning:  Undefined variable $total in /var/www/html/cart.php on line 41
+[01-May-2024 10:00:05 UTC] PHP Stack trace:
+[01-May-2024 10:00:05 UTC] PHP   1. {main}() /var/www/html/cart.php:0
+[01-May-2024 10:01:00 UTC] PHP Warning:  Undefined variable $total in /var/www/html/cart.php on line 41
+[01-May-2024 10:02:00 UTC] Payment webhook rejected
+`
+
+func TestParsePHPErrors(t *testing.T) {
+	entries := ParsePHPErrors(strings.Split(phpErrorLog, "\n"))
+	require.Len(t, entries, 4)
+
+	fatal := entries[0]
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), fatal.Time.UTC())
+	assert.Equal(t, "Fatal error", fatal.Level)
+	assert.Equal(t, "Uncaught Exception: boom", fatal.Message)
+	assert.Equal(t, "/var/www/html/index.php:3", fatal.Location())
+	assert.Equal(t, "critical", fatal.Severity())
+	assert.Len(t, fatal.Trace, 4)
+	assert.Equal(t, "#0 /var/www/html/public/index.php(12): run()", fatal.Trace[1])
+
+	warning := entries[1]
+	assert.Equal(t, "Warning", warning.Level)
+	assert.Equal(t, "Undefined variable $total", warning.Message)
+	assert.Equal(t, 41, warning.Line)
+	assert.Equal(t, []string{"PHP Stack trace:", "PHP   1. {main}() /var/www/html/cart.php:0"}, warning.Trace)
+
+	custom := entries[3]
+	assert.Empty(t, custom.Level, "error_log() messages have no level")
+	assert.Equal(t, "Payment webhook rejected", custom.Message)
+	assert.Equal(t, "info", custom.Severity())
+}
+
+func TestPHPErrorParser_Flush(t *testing.T) {
+	var parser PHPErrorParser
+	assert.Nil(t, parser.Add("[01-May-2024 10:00:00 UTC] PHP Parse error:  syntax error in /var/www/html/a.php on line 2"))
+
+	entry := parser.Flush()
+	require.NotNil(t, entry)
+	assert.Equal(t, "Parse error", entry.Level)
+	assert.Nil(t, parser.Flush())
+}
+
+func TestSummarizePHPErrors(t *testing.T) {
+	summaries := SummarizePHPErrors(ParsePHPErrors(strings.Split(phpErrorLog, "\n")))
+	require.Len(t, summaries, 3)
+
+	assert.Equal(t, "Undefined variable $total", summaries[0].Message)
+	assert.Equal(t, 2, summaries[0].Count)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 5, 0, time.UTC), summaries[0].FirstSeen.UTC())
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 1, 0, 0, time.UTC), summaries[0].LastSeen.UTC())
+
+	// Ties are ordered by when they were last seen
+	assert.Equal(t, "Payment webhook rejected", summaries[1].Message)
+	assert.Equal(t, "Uncaught Exception: boom", summaries[2].Message)
+}
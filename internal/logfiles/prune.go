@@ -0,0 +1,84 @@
+package logfiles
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// rotatedPattern matches rotated copies of logs: access.log.1, supervisord.log.3, errors.log.2.gz
+var rotatedPattern = regexp.MustCompile(`\.log\.\d+(\.gz)?$`)
+
+// PruneResult is the disk usage of a project's log files and what pruning them reclaimed
+type PruneResult struct {
+	Size      int64    // Size of all log files before pruning
+	Files     int      // Number of log files
+	Reclaimed int64    // Bytes freed, or that would be freed on a dry run
+	Skipped   []string // Logs that couldn't be truncated, relative to Dir (e.g. owned by root)
+}
+
+// PruneOptions selects what Prune does
+type PruneOptions struct {
+	DryRun   bool // Only report what would be reclaimed
+	Truncate bool // Also truncate the current logs, which the containers are still writing to
+}
+
+// Prune removes the rotated logs under the project's Dir and, with options.Truncate, truncates
+// the current ones
+func Prune(projectDir string, options PruneOptions) (PruneResult, error) {
+	var result PruneResult
+	root := filepath.Join(projectDir, Dir)
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rotated := rotatedPattern.MatchString(entry.Name())
+		if !rotated && !strings.HasSuffix(entry.Name(), ".log") {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		result.Files++
+		result.Size += info.Size()
+		if !rotated && (info.Size() == 0 || !options.Truncate) {
+			return nil
+		}
+
+		if !options.DryRun {
+			if rotated {
+				err = os.Remove(path)
+			} else {
+				// Truncated rather than removed: nginx and php-fpm keep writing to the open file
+				err = os.Truncate(path, 0)
+			}
+			if os.IsPermission(err) {
+				rel, _ := filepath.Rel(root, path)
+				result.Skipped = append(result.Skipped, filepath.ToSlash(rel))
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		result.Reclaimed += info.Size()
+		return nil
+	})
+	return result, err
+}
+
+// ContainerPath returns where the app container sees a log under Dir, e.g. nginx/access.log
+// is /var/log/nginx/access.log
+func ContainerPath(rel string) string {
+	return "/var/log/" + rel
+}
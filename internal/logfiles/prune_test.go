@@ -0,0 +1,61 @@
+package logfiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrune(t *testing.T) {
+	projectDir := t.TempDir()
+	write := func(rel, content string) string {
+		path := filepath.Join(projectDir, Dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	access := write("nginx/access.log", "0123456789")
+	rotated := write("nginx/access.log.1", "01234")
+	compressed := write("php/errors.log.2.gz", "012")
+	empty := write("php/php-fpm.log", "")
+	other := write("supervisor/notes.txt", "keep me")
+
+	result, err := Prune(projectDir, PruneOptions{DryRun: true, Truncate: true})
+	require.NoError(t, err)
+	assert.Equal(t, PruneResult{Size: 18, Files: 4, Reclaimed: 18}, result)
+	assert.FileExists(t, rotated, "a dry run changes nothing")
+
+	result, err = Prune(projectDir, PruneOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, PruneResult{Size: 18, Files: 4, Reclaimed: 8}, result)
+	assert.NoFileExists(t, rotated)
+	info, err := os.Stat(access)
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, info.Size(), "current logs are only truncated when asked to")
+
+	write("nginx/access.log.1", "01234")
+	result, err = Prune(projectDir, PruneOptions{Truncate: true})
+	require.NoError(t, err)
+	assert.Equal(t, PruneResult{Size: 15, Files: 3, Reclaimed: 15}, result)
+
+	info, err = os.Stat(access)
+	require.NoError(t, err)
+	assert.Zero(t, info.Size(), "current logs are truncated, not removed")
+	assert.NoFileExists(t, rotated)
+	assert.NoFileExists(t, compressed)
+	assert.FileExists(t, empty)
+	assert.FileExists(t, other, "only logs are pruned")
+}
+
+func TestPrune_NoLogs(t *testing.T) {
+	result, err := Prune(t.TempDir(), PruneOptions{Truncate: true})
+	require.NoError(t, err)
+	assert.Equal(t, PruneResult{}, result)
+}
+
+func TestContainerPath(t *testing.T) {
+	assert.Equal(t, "/var/log/nginx/access.log", ContainerPath("nginx/access.log"))
+}
@@ -0,0 +1,201 @@
+package ports
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"phpier/internal/config"
+)
+
+// Binding is a host port the global stack publishes
+type Binding struct {
+	Service  string // Compose service publishing the port
+	Key      string // Global config key holding the port
+	Host     string // Host address the port is bound on
+	Port     int
+	Protocol string // tcp or udp
+
+	set func(*config.GlobalConfig, int)
+}
+
+// String returns the binding as host:port/protocol
+func (b Binding) String() string {
+	return fmt.Sprintf("%s/%s", net.JoinHostPort(b.Host, strconv.Itoa(b.Port)), b.Protocol)
+}
+
+// Assign stores port in the config key the binding was read from
+func (b Binding) Assign(globalCfg *config.GlobalConfig, port int) {
+	b.set(globalCfg, port)
+}
+
+// GlobalBindings returns every host port the global stack will publish with globalCfg
+func GlobalBindings(globalCfg *config.GlobalConfig) []Binding {
+	traefik := func(key string, port int, set func(*config.GlobalConfig, int)) Binding {
+		return Binding{Service: "traefik", Key: key, Host: "0.0.0.0", Port: port, Protocol: "tcp", set: set}
+	}
+
+	bindings := []Binding{
+		traefik("traefik.port", globalCfg.Traefik.Port, func(g *config.GlobalConfig, p int) { g.Traefik.Port = p }),
+		traefik("traefik.ssl_port", globalCfg.Traefik.SSLPort, func(g *config.GlobalConfig, p int) { g.Traefik.SSLPort = p }),
+	}
+	if globalCfg.Traefik.ExposesAPIPort() {
+		bindings = append(bindings, traefik("traefik.dashboard_port", globalCfg.Traefik.DashboardPort, func(g *config.GlobalConfig, p int) { g.Traefik.DashboardPort = p }))
+	}
+
+	// Databases and Redis are published by Traefik in proxy mode, by the services otherwise
+	publisher := func(service string) string {
+		if globalCfg.Traefik.TCPProxy() {
+			return "traefik"
+		}
+		return service
+	}
+
+	for _, instance := range globalCfg.GetEnabledDatabases() {
+		name := instance.Name
+		bindings = append(bindings, Binding{Service: publisher(name), Key: fmt.Sprintf("services.databases[%s].port", name), Host: "0.0.0.0", Port: instance.Port, Protocol: "tcp",
+			set: func(g *config.GlobalConfig, p int) {
+				if instance, ok := g.Services.Databases.Get(name); ok {
+					instance.Port = p
+					g.SetDatabaseService(instance)
+				}
+			}})
+	}
+	if globalCfg.Services.Cache.Redis.Enabled {
+		bindings = append(bindings, Binding{Service: publisher("redis"), Key: "services.cache.redis.port", Host: "0.0.0.0", Port: globalCfg.Services.Cache.Redis.Port, Protocol: "tcp",
+			set: func(g *config.GlobalConfig, p int) { g.Services.Cache.Redis.Port = p }})
+	}
+
+	if globalCfg.Services.Tools.Mailpit.Enabled {
+		bindings = append(bindings,
+			Binding{Service: "mailpit", Key: "services.tools.mailpit.smtp_port", Host: "0.0.0.0", Port: globalCfg.Services.Tools.Mailpit.SMTPPort, Protocol: "tcp",
+				set: func(g *config.GlobalConfig, p int) { g.Services.Tools.Mailpit.SMTPPort = p }},
+			Binding{Service: "mailpit", Key: "services.tools.mailpit.ui_port", Host: "0.0.0.0", Port: globalCfg.Services.Tools.Mailpit.UIPort, Protocol: "tcp",
+				set: func(g *config.GlobalConfig, p int) { g.Services.Tools.Mailpit.UIPort = p }},
+		)
+	}
+
+	if globalCfg.DNS.Enabled {
+		host := "127.0.0.1"
+		if globalCfg.DNS.IP == "lan" {
+			host = "0.0.0.0"
+		}
+		// The responder only answers over UDP, so only the UDP port is published
+		bindings = append(bindings, Binding{Service: "dns", Key: "dns.port", Host: host, Port: globalCfg.DNS.Port, Protocol: "udp",
+			set: func(g *config.GlobalConfig, p int) { g.DNS.Port = p }})
+	}
+
+	return bindings
+}
+
+// Duplicates returns ports that more than one config key publishes, with the keys using them
+func Duplicates(bindings []Binding) map[int][]string {
+	keys := make(map[string][]string)
+	for _, b := range bindings {
+		id := fmt.Sprintf("%d/%s", b.Port, b.Protocol)
+		if !contains(keys[id], b.Key) {
+			keys[id] = append(keys[id], b.Key)
+		}
+	}
+
+	result := make(map[int][]string)
+	for _, b := range bindings {
+		id := fmt.Sprintf("%d/%s", b.Port, b.Protocol)
+		if len(keys[id]) > 1 {
+			result[b.Port] = keys[id]
+		}
+	}
+	return result
+}
+
+// Available reports whether the binding's port can be bound on the host.
+// Permission errors (e.g. ports below 1024 for regular users) don't count as conflicts,
+// since Docker binds the port on our behalf.
+func Available(b Binding) bool {
+	addr := net.JoinHostPort(b.Host, strconv.Itoa(b.Port))
+	if b.Protocol == "udp" {
+		conn, err := net.ListenPacket("udp", addr)
+		if err != nil {
+			return errors.Is(err, os.ErrPermission)
+		}
+		conn.Close()
+		return true
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Is(err, os.ErrPermission)
+	}
+	listener.Close()
+	return true
+}
+
+// FindFree returns the first port above b.Port that is available and not in taken
+func FindFree(b Binding, taken map[int]bool) (int, error) {
+	for port := b.Port + 1; port <= 65535; port++ {
+		if taken[port] {
+			continue
+		}
+		candidate := b
+		candidate.Port = port
+		if Available(candidate) {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no free port found above %d", b.Port)
+}
+
+// ProcessHolding describes the host process listening on port, or returns "" when unknown
+func ProcessHolding(port int, protocol string) string {
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+
+	args := []string{"-nP", fmt.Sprintf("-i%s:%d", strings.ToUpper(protocol), port), "-Fpc"}
+	if protocol == "tcp" {
+		args = append(args, "-sTCP:LISTEN")
+	}
+	// lsof exits non-zero when nothing matches or for processes it can't inspect
+	output, _ := exec.Command("lsof", args...).Output()
+	return parseLsof(string(output))
+}
+
+// parseLsof turns lsof -F pc output into "command (pid N)" descriptions
+func parseLsof(output string) string {
+	var holders []string
+	var pid string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) < 2 {
+			continue
+		}
+		switch line[0] {
+		case 'p':
+			pid = line[1:]
+		case 'c':
+			holder := fmt.Sprintf("%s (pid %s)", line[1:], pid)
+			if !contains(holders, holder) {
+				holders = append(holders, holder)
+			}
+		}
+	}
+	sort.Strings(holders)
+	return strings.Join(holders, ", ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
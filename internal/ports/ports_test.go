@@ -0,0 +1,88 @@
+package ports
+
+import (
+	"net"
+	"testing"
+
+	"phpier/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testGlobalConfig() *config.GlobalConfig {
+	globalCfg := &config.GlobalConfig{}
+	globalCfg.Traefik = config.TraefikConfig{Port: 80, SSLPort: 443, Dashboard: true, DashboardPort: 8080, TCPMode: config.TCPModeDirect}
+	globalCfg.Services.Databases = config.DatabasesConfig{{Name: "mysql", Engine: "mysql", Enabled: true, Port: 3306}}
+	globalCfg.Services.Cache.Redis = config.CacheServiceConfig{Enabled: true, Port: 6379}
+	globalCfg.Services.Tools.Mailpit = config.MailpitConfig{Enabled: true, SMTPPort: 1026, UIPort: 8026}
+	return globalCfg
+}
+
+func TestGlobalBindings(t *testing.T) {
+	globalCfg := testGlobalConfig()
+
+	var keys []string
+	for _, b := range GlobalBindings(globalCfg) {
+		keys = append(keys, b.Key)
+	}
+	assert.Equal(t, []string{
+		"traefik.port", "traefik.ssl_port", "traefik.dashboard_port",
+		"services.databases[mysql].port", "services.cache.redis.port",
+		"services.tools.mailpit.smtp_port", "services.tools.mailpit.ui_port",
+	}, keys)
+
+	globalCfg.Traefik.TCPMode = config.TCPModeProxy
+	for _, b := range GlobalBindings(globalCfg) {
+		if b.Key == "services.databases[mysql].port" {
+			assert.Equal(t, "traefik", b.Service, "traefik publishes databases in proxy mode")
+		}
+	}
+
+	globalCfg.DNS = config.DNSConfig{Enabled: true, Port: 5354}
+	var dns []string
+	for _, b := range GlobalBindings(globalCfg) {
+		if b.Service == "dns" {
+			dns = append(dns, b.Protocol)
+		}
+	}
+	assert.Equal(t, []string{"udp"}, dns, "the responder is only published over UDP")
+}
+
+func TestBindingAssign(t *testing.T) {
+	globalCfg := testGlobalConfig()
+	for _, b := range GlobalBindings(globalCfg) {
+		if b.Key == "services.tools.mailpit.ui_port" {
+			b.Assign(globalCfg, 8027)
+		}
+	}
+	assert.Equal(t, 8027, globalCfg.Services.Tools.Mailpit.UIPort)
+}
+
+func TestDuplicates(t *testing.T) {
+	globalCfg := testGlobalConfig()
+	assert.Empty(t, Duplicates(GlobalBindings(globalCfg)))
+
+	globalCfg.Services.Cache.Redis.Port = 3306
+	assert.Equal(t, map[int][]string{3306: {"services.databases[mysql].port", "services.cache.redis.port"}}, Duplicates(GlobalBindings(globalCfg)))
+}
+
+func TestAvailableAndFindFree(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	held := Binding{Host: "127.0.0.1", Port: port, Protocol: "tcp"}
+	assert.False(t, Available(held))
+
+	free, err := FindFree(held, map[int]bool{port + 1: true})
+	require.NoError(t, err)
+	assert.Greater(t, free, port+1, "ports already taken by other bindings are skipped")
+}
+
+func TestParseLsof(t *testing.T) {
+	output := "p812\ncmysqld\np901\ncnginx\np812\ncmysqld\n"
+	assert.Equal(t, "mysqld (pid 812), nginx (pid 901)", parseLsof(output))
+	assert.Equal(t, "", parseLsof(""))
+}
@@ -0,0 +1,145 @@
+package routes
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FilePrefix starts the name of every route file in the Traefik dynamic directory
+const FilePrefix = "route-"
+
+var (
+	namePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	hostPattern = regexp.MustCompile("Host\\(`([^`]+)`\\)")
+)
+
+// Route sends requests for Hosts to an upstream URL outside phpier's containers
+type Route struct {
+	Name  string
+	Hosts []string
+	URL   string
+}
+
+// Validate checks the route name, hosts and upstream URL
+func (r Route) Validate() error {
+	if !namePattern.MatchString(r.Name) {
+		return fmt.Errorf("invalid route name %q: use lowercase letters, digits and dashes", r.Name)
+	}
+	if len(r.Hosts) == 0 {
+		return fmt.Errorf("route %s has no hosts", r.Name)
+	}
+	for _, host := range r.Hosts {
+		if host == "" || strings.ContainsAny(host, "`/: ") {
+			return fmt.Errorf("invalid host %q for route %s", host, r.Name)
+		}
+	}
+
+	upstream, err := url.Parse(r.URL)
+	if err != nil || (upstream.Scheme != "http" && upstream.Scheme != "https") || upstream.Host == "" {
+		return fmt.Errorf("invalid upstream %q for route %s: use http://host:port or https://host:port", r.URL, r.Name)
+	}
+	return nil
+}
+
+// RouterName returns the Traefik router and service name of the route
+func (r Route) RouterName() string {
+	return FilePrefix + r.Name
+}
+
+// HostRule returns the Traefik rule matching the route's hosts
+func (r Route) HostRule() string {
+	rules := make([]string, 0, len(r.Hosts))
+	for _, host := range r.Hosts {
+		rules = append(rules, "Host(`"+host+"`)")
+	}
+	return strings.Join(rules, " || ")
+}
+
+// CertName returns the certificate name of the route called name. Project certificates are
+// issued as project-<project>, so a project can't take over a route's certificate files.
+func CertName(name string) string {
+	return FilePrefix + name
+}
+
+// FileName returns the dynamic configuration file name for the route called name
+func FileName(name string) string {
+	return FilePrefix + name + ".yml"
+}
+
+// dynamicFile is the part of a route's dynamic configuration file that List reads back
+type dynamicFile struct {
+	HTTP struct {
+		Routers map[string]struct {
+			Rule string `yaml:"rule"`
+		} `yaml:"routers"`
+		Services map[string]struct {
+			LoadBalancer struct {
+				Servers []struct {
+					URL string `yaml:"url"`
+				} `yaml:"servers"`
+			} `yaml:"loadBalancer"`
+		} `yaml:"services"`
+	} `yaml:"http"`
+}
+
+// Parse reads a route back from its dynamic configuration file content
+func Parse(name string, content []byte) (Route, error) {
+	var file dynamicFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return Route{}, fmt.Errorf("failed to parse route %s: %w", name, err)
+	}
+
+	route := Route{Name: name}
+	router, ok := file.HTTP.Routers[route.RouterName()]
+	if !ok {
+		return Route{}, fmt.Errorf("route %s has no router %s", name, route.RouterName())
+	}
+	for _, match := range hostPattern.FindAllStringSubmatch(router.Rule, -1) {
+		route.Hosts = append(route.Hosts, match[1])
+	}
+
+	if service, ok := file.HTTP.Services[route.RouterName()]; ok && len(service.LoadBalancer.Servers) > 0 {
+		route.URL = service.LoadBalancer.Servers[0].URL
+	}
+	return route, nil
+}
+
+// List returns the routes defined in dir, sorted by name
+func List(dir string) ([]Route, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, FilePrefix+"*.yml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Route
+	for _, path := range paths {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), FilePrefix), ".yml")
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		route, err := Parse(name, content)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, route)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// Exists reports whether dir holds a route called name
+func Exists(dir, name string) bool {
+	_, err := os.Stat(filepath.Join(dir, FileName(name)))
+	return err == nil
+}
@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteValidate(t *testing.T) {
+	valid := Route{Name: "api", Hosts: []string{"api.localhost"}, URL: "http://host.docker.internal:8081"}
+	assert.NoError(t, valid.Validate())
+
+	for name, route := range map[string]Route{
+		"uppercase name": {Name: "API", Hosts: valid.Hosts, URL: valid.URL},
+		"no hosts":       {Name: "api", URL: valid.URL},
+		"host with port": {Name: "api", Hosts: []string{"api.localhost:80"}, URL: valid.URL},
+		"no scheme":      {Name: "api", Hosts: valid.Hosts, URL: "host.docker.internal:8081"},
+		"tcp scheme":     {Name: "api", Hosts: valid.Hosts, URL: "tcp://host.docker.internal:8081"},
+	} {
+		assert.Error(t, route.Validate(), name)
+	}
+}
+
+func TestHostRule(t *testing.T) {
+	route := Route{Name: "docs", Hosts: []string{"docs.localhost", "help.localhost"}}
+	assert.Equal(t, "Host(`docs.localhost`) || Host(`help.localhost`)", route.HostRule())
+	assert.Equal(t, "route-docs", route.RouterName())
+	assert.Equal(t, "route-docs", CertName(route.Name))
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	content := `http:
+  routers:
+    route-api:
+      rule: "Host(` + "`api.localhost`" + `) || Host(` + "`api.test`" + `)"
+      service: route-api
+    route-api-secure:
+      rule: "Host(` + "`api.localhost`" + `)"
+      service: route-api
+  services:
+    route-api:
+      loadBalancer:
+        servers:
+          - url: "http://host.docker.internal:8081"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FileName("api")), []byte(content), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api.yml"), []byte("http: {}\n"), 0644))
+
+	list, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, list, 1, "only route files are listed")
+	assert.Equal(t, Route{Name: "api", Hosts: []string{"api.localhost", "api.test"}, URL: "http://host.docker.internal:8081"}, list[0])
+
+	assert.True(t, Exists(dir, "api"))
+	assert.False(t, Exists(dir, "web"))
+}
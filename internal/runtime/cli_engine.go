@@ -0,0 +1,369 @@
+package runtime
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"phpier/internal/dockerapi"
+
+	"github.com/sirupsen/logrus"
+)
+
+// cliEngine answers queries through a Docker-compatible CLI for runtimes without an Engine API
+// socket (nerdctl). It relies on the CLI's docker-compatible inspect output and applies list
+// filters itself.
+type cliEngine struct {
+	cli string
+}
+
+func (e *cliEngine) run(ctx context.Context, args ...string) ([]byte, error) {
+	logrus.Debugf("Executing: %s %s", e.cli, strings.Join(args, " "))
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.cli, args...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if strings.Contains(strings.ToLower(message), "no such") || strings.Contains(message, "not found") {
+			return nil, &dockerapi.APIError{StatusCode: http.StatusNotFound, Message: message}
+		}
+		return nil, fmt.Errorf("%s %s failed: %w: %s", e.cli, strings.Join(args, " "), err, message)
+	}
+	return output, nil
+}
+
+func (e *cliEngine) Ping(ctx context.Context) error {
+	_, err := e.run(ctx, "info", "--format", "{{.ServerVersion}}")
+	return err
+}
+
+func (e *cliEngine) ContainerList(ctx context.Context, options dockerapi.ContainerListOptions) ([]dockerapi.Container, error) {
+	args := []string{"ps", "-q"}
+	if options.All {
+		args = append(args, "-a")
+	}
+	output, err := e.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	ids := strings.Fields(string(output))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	infos, err := e.inspectContainers(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []dockerapi.Container
+	for _, info := range infos {
+		container := containerSummary(info)
+		if matchesFilters(container, options.Filters) {
+			containers = append(containers, container)
+		}
+	}
+	return containers, nil
+}
+
+func (e *cliEngine) ContainerInspect(ctx context.Context, container string) (*dockerapi.ContainerJSON, error) {
+	infos, err := e.inspectContainers(ctx, container)
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return nil, &dockerapi.APIError{StatusCode: http.StatusNotFound, Message: "No such container: " + container}
+	}
+	return &infos[0], nil
+}
+
+func (e *cliEngine) inspectContainers(ctx context.Context, containers ...string) ([]dockerapi.ContainerJSON, error) {
+	output, err := e.run(ctx, append([]string{"container", "inspect"}, containers...)...)
+	if err != nil {
+		return nil, err
+	}
+	var infos []dockerapi.ContainerJSON
+	if err := json.Unmarshal(output, &infos); err != nil {
+		return nil, fmt.Errorf("failed to decode %s inspect output: %w", e.cli, err)
+	}
+	return infos, nil
+}
+
+// containerSummary converts inspect output to the shape of a container list entry
+func containerSummary(info dockerapi.ContainerJSON) dockerapi.Container {
+	container := dockerapi.Container{
+		ID:     info.ID,
+		Names:  []string{"/" + strings.TrimPrefix(info.Name, "/")},
+		Image:  info.Config.Image,
+		State:  info.State.Status,
+		Status: info.State.Status,
+		Labels: info.Config.Labels,
+		Mounts: info.Mounts,
+	}
+	if container.Image == "" {
+		container.Image = info.Image
+	}
+	if created, err := time.Parse(time.RFC3339Nano, info.Created); err == nil {
+		container.Created = created.Unix()
+	}
+	container.NetworkSettings.Networks = info.NetworkSettings.Networks
+
+	for containerPort, bindings := range info.NetworkSettings.Ports {
+		private, protocol := containerPort, "tcp"
+		if parts := strings.SplitN(containerPort, "/", 2); len(parts) == 2 {
+			private, protocol = parts[0], parts[1]
+		}
+		privatePort, _ := strconv.Atoi(private)
+		for _, binding := range bindings {
+			publicPort, _ := strconv.Atoi(binding.HostPort)
+			container.Ports = append(container.Ports, dockerapi.Port{IP: binding.HostIP, PrivatePort: privatePort, PublicPort: publicPort, Type: protocol})
+		}
+	}
+	return container
+}
+
+// matchesFilters applies the container list filters phpier uses (label, name, status, network, publish)
+func matchesFilters(container dockerapi.Container, filters dockerapi.Filters) bool {
+	for _, label := range filters["label"] {
+		key, value, hasValue := strings.Cut(label, "=")
+		actual, exists := container.Labels[key]
+		if !exists || (hasValue && actual != value) {
+			return false
+		}
+	}
+	if names := filters["name"]; len(names) > 0 && !anyMatch(names, func(name string) bool {
+		return strings.Contains(container.Name(), name)
+	}) {
+		return false
+	}
+	if statuses := filters["status"]; len(statuses) > 0 && !anyMatch(statuses, func(status string) bool {
+		return container.State == status
+	}) {
+		return false
+	}
+	if networks := filters["network"]; len(networks) > 0 && !anyMatch(networks, func(network string) bool {
+		_, attached := container.NetworkSettings.Networks[network]
+		return attached
+	}) {
+		return false
+	}
+	if published := filters["publish"]; len(published) > 0 && !anyMatch(published, func(port string) bool {
+		for _, p := range container.Ports {
+			if strconv.Itoa(p.PublicPort) == port {
+				return true
+			}
+		}
+		return false
+	}) {
+		return false
+	}
+	return true
+}
+
+func anyMatch(values []string, match func(string) bool) bool {
+	for _, value := range values {
+		if match(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// cliImage is a line of "images --format '{{json .}}'"
+type cliImage struct {
+	Repository string `json:"Repository"`
+	Tag        string `json:"Tag"`
+	ID         string `json:"ID"`
+	CreatedAt  string `json:"CreatedAt"`
+	Size       string `json:"Size"`
+}
+
+func (e *cliEngine) ImageList(ctx context.Context, filters dockerapi.Filters) ([]dockerapi.Image, error) {
+	output, err := e.run(ctx, "images", "--format", "{{json .}}")
+	if err != nil {
+		return nil, err
+	}
+	return parseCLIImages(output, filters["reference"])
+}
+
+// parseCLIImages groups image lines by ID, keeping the tags that match one of references
+func parseCLIImages(output []byte, references []string) ([]dockerapi.Image, error) {
+	var images []dockerapi.Image
+	byID := make(map[string]int)
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry cliImage
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode image list: %w", err)
+		}
+		if entry.Repository == "" || entry.Repository == "<none>" {
+			continue
+		}
+		ref := entry.Repository + ":" + entry.Tag
+		if len(references) > 0 && !anyMatch(references, func(pattern string) bool {
+			repoMatch, _ := path.Match(pattern, entry.Repository)
+			refMatch, _ := path.Match(pattern, ref)
+			return repoMatch || refMatch
+		}) {
+			continue
+		}
+
+		if i, exists := byID[entry.ID]; exists {
+			images[i].RepoTags = append(images[i].RepoTags, ref)
+			continue
+		}
+		image := dockerapi.Image{ID: entry.ID, RepoTags: []string{ref}, Size: parseSize(entry.Size)}
+		if created, err := time.Parse("2006-01-02 15:04:05 -0700 MST", entry.CreatedAt); err == nil {
+			image.Created = created.Unix()
+		}
+		byID[entry.ID] = len(images)
+		images = append(images, image)
+	}
+	return images, scanner.Err()
+}
+
+// parseSize parses human-readable sizes such as "512B", "1.5 MiB" or "230MB"
+func parseSize(size string) int64 {
+	size = strings.ReplaceAll(strings.TrimSpace(size), " ", "")
+	units := []struct {
+		suffix string
+		factor float64
+	}{
+		{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+		{"kB", 1e3}, {"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12}, {"B", 1},
+	}
+	for _, unit := range units {
+		if strings.HasSuffix(size, unit.suffix) {
+			value, err := strconv.ParseFloat(strings.TrimSuffix(size, unit.suffix), 64)
+			if err != nil {
+				return 0
+			}
+			return int64(value * unit.factor)
+		}
+	}
+	value, _ := strconv.ParseInt(size, 10, 64)
+	return value
+}
+
+func (e *cliEngine) ImageInspect(ctx context.Context, ref string) (*dockerapi.ImageInspect, error) {
+	output, err := e.run(ctx, "image", "inspect", ref)
+	if err != nil {
+		return nil, err
+	}
+	var images []dockerapi.ImageInspect
+	if err := json.Unmarshal(output, &images); err != nil {
+		return nil, fmt.Errorf("failed to decode %s image inspect output: %w", e.cli, err)
+	}
+	if len(images) == 0 {
+		return nil, &dockerapi.APIError{StatusCode: http.StatusNotFound, Message: "No such image: " + ref}
+	}
+	return &images[0], nil
+}
+
+func (e *cliEngine) NetworkInspect(ctx context.Context, network string) (*dockerapi.Network, error) {
+	output, err := e.run(ctx, "network", "inspect", network)
+	if err != nil {
+		return nil, err
+	}
+	var networks []dockerapi.Network
+	if err := json.Unmarshal(output, &networks); err != nil {
+		return nil, fmt.Errorf("failed to decode %s network inspect output: %w", e.cli, err)
+	}
+	if len(networks) == 0 {
+		return nil, &dockerapi.APIError{StatusCode: http.StatusNotFound, Message: "No such network: " + network}
+	}
+	return &networks[0], nil
+}
+
+func (e *cliEngine) Exec(ctx context.Context, container string, options dockerapi.ExecOptions) (*dockerapi.ExecResult, error) {
+	args := []string{"exec"}
+	if options.User != "" {
+		args = append(args, "--user", options.User)
+	}
+	if options.WorkingDir != "" {
+		args = append(args, "--workdir", options.WorkingDir)
+	}
+	for _, env := range options.Env {
+		args = append(args, "--env", env)
+	}
+	args = append(args, container)
+	args = append(args, options.Cmd...)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.cli, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	result := &dockerapi.ExecResult{}
+	if err := cmd.Run(); err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return nil, err
+		}
+		result.ExitCode = exitErr.ExitCode()
+	}
+	result.Stdout, result.Stderr = stdout.String(), stderr.String()
+	return result, nil
+}
+
+// cliStats is the output of "stats --no-stream --format '{{json .}}'"
+type cliStats struct {
+	CPUPerc  string `json:"CPUPerc"`
+	MemUsage string `json:"MemUsage"`
+	NetIO    string `json:"NetIO"`
+	BlockIO  string `json:"BlockIO"`
+	PIDs     string `json:"PIDs"`
+}
+
+func (e *cliEngine) ContainerStats(ctx context.Context, container string) (*dockerapi.Stats, error) {
+	output, err := e.run(ctx, "stats", "--no-stream", "--format", "{{json .}}", container)
+	if err != nil {
+		return nil, err
+	}
+	var entry cliStats
+	if err := json.Unmarshal(bytes.TrimSpace(output), &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode %s stats output: %w", e.cli, err)
+	}
+	return parseCLIStats(entry), nil
+}
+
+// parseCLIStats converts the human-readable figures of the stats command, such as
+// "12.5MiB / 1GiB" for memory, back into numbers
+func parseCLIStats(entry cliStats) *dockerapi.Stats {
+	pair := func(value string) (uint64, uint64) {
+		first, second, _ := strings.Cut(value, "/")
+		return uint64(parseSize(first)), uint64(parseSize(second))
+	}
+
+	stats := &dockerapi.Stats{}
+	stats.CPUPercent, _ = strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(entry.CPUPerc), "%"), 64)
+	stats.MemoryUsage, stats.MemoryLimit = pair(entry.MemUsage)
+	stats.NetworkRx, stats.NetworkTx = pair(entry.NetIO)
+	stats.BlockRead, stats.BlockWrite = pair(entry.BlockIO)
+	stats.PIDs, _ = strconv.ParseUint(strings.TrimSpace(entry.PIDs), 10, 64)
+	return stats
+}
+
+// Events isn't available: nerdctl reports containerd events, not Docker's event stream
+func (e *cliEngine) Events(ctx context.Context, options dockerapi.EventsOptions) (<-chan dockerapi.Event, <-chan error) {
+	events := make(chan dockerapi.Event)
+	errs := make(chan error, 1)
+	errs <- fmt.Errorf("%s does not provide a Docker-compatible event stream", e.cli)
+	close(events)
+	return events, errs
+}
@@ -0,0 +1,272 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"phpier/internal/dockerapi"
+)
+
+// Runtime names accepted in the global config (runtime:) and PHPIER_RUNTIME
+const (
+	Auto    = "auto"
+	Docker  = "docker"
+	Podman  = "podman"
+	Nerdctl = "nerdctl"
+)
+
+// Names lists the runtimes phpier supports, in auto-detection order
+var Names = []string{Docker, Podman, Nerdctl}
+
+// Runtime is a container runtime phpier drives: its CLI, its compose implementation and
+// the API it answers queries on
+type Runtime interface {
+	// Name returns docker, podman or nerdctl
+	Name() string
+	// CLI returns the runtime's command line tool
+	CLI() string
+	// ComposeCommand returns the compose command prefix, e.g. [docker compose] or [podman-compose]
+	ComposeCommand() []string
+	// Socket returns the host path of the runtime's Docker-compatible API socket, or an empty
+	// string when it has none (nerdctl)
+	Socket() string
+	// Rootless reports whether containers run in a user namespace owned by the current user
+	Rootless() bool
+	// Engine returns the client used to query containers, images, networks, exec and events
+	Engine() (Engine, error)
+}
+
+// Engine queries a runtime. The Docker Engine API client implements it for Docker and Podman.
+type Engine interface {
+	Ping(ctx context.Context) error
+	ContainerList(ctx context.Context, options dockerapi.ContainerListOptions) ([]dockerapi.Container, error)
+	ContainerInspect(ctx context.Context, container string) (*dockerapi.ContainerJSON, error)
+	ImageList(ctx context.Context, filters dockerapi.Filters) ([]dockerapi.Image, error)
+	ImageInspect(ctx context.Context, ref string) (*dockerapi.ImageInspect, error)
+	NetworkInspect(ctx context.Context, network string) (*dockerapi.Network, error)
+	Exec(ctx context.Context, container string, options dockerapi.ExecOptions) (*dockerapi.ExecResult, error)
+	ContainerStats(ctx context.Context, container string) (*dockerapi.Stats, error)
+	Events(ctx context.Context, options dockerapi.EventsOptions) (<-chan dockerapi.Event, <-chan error)
+}
+
+// ValidName reports whether name is auto or a supported runtime
+func ValidName(name string) bool {
+	if name == "" || name == Auto {
+		return true
+	}
+	for _, known := range Names {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	detectMu sync.Mutex
+	detected = make(map[string]Runtime)
+)
+
+// Detect returns the runtime for preference. With auto (or empty), the first runtime whose CLI
+// is installed wins, in the order docker, podman, nerdctl; a docker CLI that is Podman's
+// compatibility wrapper counts as Podman. Docker is assumed when none is installed, so
+// templates can still be rendered. Results are cached per preference.
+func Detect(preference string) (Runtime, error) {
+	if !ValidName(preference) {
+		return nil, fmt.Errorf("unknown container runtime %q (use auto, %s)", preference, strings.Join(Names, ", "))
+	}
+	if preference == "" {
+		preference = Auto
+	}
+
+	detectMu.Lock()
+	defer detectMu.Unlock()
+	if r, ok := detected[preference]; ok {
+		return r, nil
+	}
+
+	name := preference
+	if name == Auto {
+		name = detectName()
+	}
+
+	var r Runtime
+	switch name {
+	case Podman:
+		r = newPodman()
+	case Nerdctl:
+		r = newNerdctl()
+	default:
+		r = newDocker()
+	}
+	detected[preference] = r
+	return r, nil
+}
+
+// detectName picks the runtime for auto
+func detectName() string {
+	// DOCKER_HOST pointing at a Podman socket means Podman, whatever the CLI is called
+	if strings.Contains(os.Getenv("DOCKER_HOST"), "podman") {
+		return Podman
+	}
+	if _, err := exec.LookPath("docker"); err == nil {
+		if isPodmanWrapper() {
+			return Podman
+		}
+		return Docker
+	}
+	for _, name := range []string{Podman, Nerdctl} {
+		if _, err := exec.LookPath(name); err == nil {
+			return name
+		}
+	}
+	return Docker
+}
+
+// isPodmanWrapper reports whether the docker CLI is podman-docker's wrapper script
+func isPodmanWrapper() bool {
+	output, err := exec.Command("docker", "--version").Output()
+	return err == nil && strings.Contains(strings.ToLower(string(output)), "podman")
+}
+
+// Check verifies that the runtime's CLI is installed, engine answers and compose is available
+func Check(ctx context.Context, r Runtime, engine Engine) error {
+	if _, err := exec.LookPath(r.CLI()); err != nil {
+		return fmt.Errorf("%s is not installed or not found in PATH", r.CLI())
+	}
+
+	if err := engine.Ping(ctx); err != nil {
+		return &NotRunningError{Runtime: r.Name(), Err: err}
+	}
+
+	if len(r.ComposeCommand()) == 0 {
+		return &ComposeNotFoundError{Runtime: r.Name()}
+	}
+	return nil
+}
+
+// NotRunningError means the runtime's CLI is installed but its engine doesn't answer
+type NotRunningError struct {
+	Runtime string
+	Err     error
+}
+
+func (e *NotRunningError) Error() string {
+	return fmt.Sprintf("%s is not running: %v", e.Runtime, e.Err)
+}
+
+func (e *NotRunningError) Unwrap() error {
+	return e.Err
+}
+
+// ComposeNotFoundError means no compose implementation was found for the runtime
+type ComposeNotFoundError struct {
+	Runtime string
+}
+
+func (e *ComposeNotFoundError) Error() string {
+	return fmt.Sprintf("no compose implementation found for %s", e.Runtime)
+}
+
+// firstCompose returns the first compose command that answers to "version"
+func firstCompose(candidates ...[]string) []string {
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate[0]); err != nil {
+			continue
+		}
+		args := append(append([]string{}, candidate[1:]...), "version")
+		if exec.Command(candidate[0], args...).Run() == nil {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// backend implements Runtime for the CLIs phpier knows
+type backend struct {
+	name     string
+	cli      string
+	host     string // Engine API endpoint; empty when queries go through the CLI
+	rootless bool
+
+	composeOnce sync.Once
+	compose     []string
+	candidates  [][]string
+}
+
+func (b *backend) Name() string   { return b.name }
+func (b *backend) CLI() string    { return b.cli }
+func (b *backend) Rootless() bool { return b.rootless }
+
+func (b *backend) ComposeCommand() []string {
+	b.composeOnce.Do(func() {
+		b.compose = firstCompose(b.candidates...)
+	})
+	return b.compose
+}
+
+func (b *backend) Socket() string {
+	if strings.HasPrefix(b.host, "unix://") {
+		return strings.TrimPrefix(b.host, "unix://")
+	}
+	return ""
+}
+
+func (b *backend) Engine() (Engine, error) {
+	if b.host == "" {
+		return &cliEngine{cli: b.cli}, nil
+	}
+	return dockerapi.NewClient(b.host)
+}
+
+func newDocker() *backend {
+	host := dockerapi.ResolveHost()
+	return &backend{
+		name: Docker,
+		cli:  "docker",
+		host: host,
+		// Rootless Docker listens on a socket under the user's runtime directory
+		rootless:   strings.HasPrefix(strings.TrimPrefix(host, "unix://"), "/run/user/"),
+		candidates: [][]string{{"docker", "compose"}, {"docker-compose"}},
+	}
+}
+
+func newPodman() *backend {
+	rootless := os.Geteuid() != 0
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		host = "unix://" + podmanSocket(rootless)
+	}
+	return &backend{
+		name:       Podman,
+		cli:        "podman",
+		host:       host,
+		rootless:   rootless,
+		candidates: [][]string{{"podman", "compose"}, {"podman-compose"}},
+	}
+}
+
+// podmanSocket returns the path of Podman's Docker-compatible API socket (podman.socket)
+func podmanSocket(rootless bool) string {
+	if !rootless {
+		return "/run/podman/podman.sock"
+	}
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = fmt.Sprintf("/run/user/%d", os.Geteuid())
+	}
+	return dir + "/podman/podman.sock"
+}
+
+func newNerdctl() *backend {
+	return &backend{
+		name:       Nerdctl,
+		cli:        "nerdctl",
+		rootless:   os.Geteuid() != 0,
+		candidates: [][]string{{"nerdctl", "compose"}},
+	}
+}
@@ -0,0 +1,189 @@
+package runtime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"phpier/internal/dockerapi"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCLIs puts executable scripts named after the given CLIs on an otherwise empty PATH and
+// clears the detection cache. Scripts can only use shell builtins.
+func fakeCLIs(t *testing.T, scripts map[string]string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, script := range scripts {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0755))
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv("DOCKER_HOST", "")
+
+	detectMu.Lock()
+	detected = make(map[string]Runtime)
+	detectMu.Unlock()
+}
+
+func TestDetect_Auto(t *testing.T) {
+	tests := []struct {
+		name     string
+		clis     map[string]string
+		expected string
+	}{
+		{"docker", map[string]string{"docker": "echo Docker version 27.0.3", "podman": "exit 0"}, Docker},
+		{"podman wrapper", map[string]string{"docker": "echo podman version 5.2.0"}, Podman},
+		{"podman", map[string]string{"podman": "exit 0", "nerdctl": "exit 0"}, Podman},
+		{"nerdctl", map[string]string{"nerdctl": "exit 0"}, Nerdctl},
+		{"nothing installed", map[string]string{}, Docker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeCLIs(t, tt.clis)
+
+			r, err := Detect(Auto)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, r.Name())
+		})
+	}
+}
+
+func TestDetect_PodmanDockerHost(t *testing.T) {
+	fakeCLIs(t, map[string]string{"docker": "echo Docker version 27.0.3"})
+	t.Setenv("DOCKER_HOST", "unix:///run/user/1000/podman/podman.sock")
+
+	r, err := Detect("")
+	require.NoError(t, err)
+	assert.Equal(t, Podman, r.Name())
+	assert.Equal(t, "/run/user/1000/podman/podman.sock", r.Socket())
+}
+
+func TestDetect_Explicit(t *testing.T) {
+	fakeCLIs(t, map[string]string{"docker": "echo Docker version 27.0.3"})
+
+	r, err := Detect(Nerdctl)
+	require.NoError(t, err)
+	assert.Equal(t, Nerdctl, r.Name())
+	assert.Empty(t, r.Socket())
+
+	_, err = Detect("lxc")
+	assert.Error(t, err)
+}
+
+func TestComposeCommand(t *testing.T) {
+	fakeCLIs(t, map[string]string{
+		// podman compose fails without an external provider, podman-compose answers
+		"podman":         `[ "$1" = "compose" ] && exit 125; exit 0`,
+		"podman-compose": "exit 0",
+	})
+
+	r, err := Detect(Podman)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"podman-compose"}, r.ComposeCommand())
+}
+
+func TestValidName(t *testing.T) {
+	for _, name := range []string{"", Auto, Docker, Podman, Nerdctl} {
+		assert.True(t, ValidName(name), name)
+	}
+	assert.False(t, ValidName("containerd"))
+}
+
+func TestMatchesFilters(t *testing.T) {
+	container := dockerapi.Container{
+		Names:  []string{"/shop-app-1"},
+		State:  "running",
+		Labels: map[string]string{"com.docker.compose.project": "shop"},
+		Ports:  []dockerapi.Port{{PrivatePort: 80, PublicPort: 8080, Type: "tcp"}},
+	}
+	container.NetworkSettings.Networks = map[string]dockerapi.EndpointSettings{"phpier_phpier": {}}
+
+	assert.True(t, matchesFilters(container, nil))
+	assert.True(t, matchesFilters(container, dockerapi.Filters{"label": {"com.docker.compose.project"}}))
+	assert.True(t, matchesFilters(container, dockerapi.Filters{"label": {"com.docker.compose.project=shop"}}))
+	assert.False(t, matchesFilters(container, dockerapi.Filters{"label": {"com.docker.compose.project=blog"}}))
+	assert.True(t, matchesFilters(container, dockerapi.Filters{"name": {"shop-app"}}))
+	assert.False(t, matchesFilters(container, dockerapi.Filters{"status": {"exited"}}))
+	assert.True(t, matchesFilters(container, dockerapi.Filters{"network": {"phpier_phpier"}}))
+	assert.True(t, matchesFilters(container, dockerapi.Filters{"publish": {"8080"}}))
+	assert.False(t, matchesFilters(container, dockerapi.Filters{"publish": {"80"}}))
+}
+
+func TestParseCLIImages(t *testing.T) {
+	output := []byte(`{"Repository":"phpier-base","Tag":"8.3-abc123","ID":"1f2e3d","CreatedAt":"2024-05-01 10:00:00 +0000 UTC","Size":"512.5 MiB"}
+{"Repository":"phpier-shop","Tag":"8.3","ID":"9a8b7c","CreatedAt":"2024-05-02 10:00:00 +0000 UTC","Size":"600MB"}
+{"Repository":"phpier-base","Tag":"latest","ID":"1f2e3d","CreatedAt":"2024-05-01 10:00:00 +0000 UTC","Size":"512.5 MiB"}
+{"Repository":"<none>","Tag":"<none>","ID":"dangling","CreatedAt":"","Size":"1kB"}
+`)
+
+	images, err := parseCLIImages(output, []string{"phpier-base"})
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	assert.Equal(t, "1f2e3d", images[0].ID)
+	assert.Equal(t, []string{"phpier-base:8.3-abc123", "phpier-base:latest"}, images[0].RepoTags)
+	assert.Equal(t, int64(537395200), images[0].Size)
+	assert.NotZero(t, images[0].Created)
+
+	images, err = parseCLIImages(output, []string{"phpier-*"})
+	require.NoError(t, err)
+	assert.Len(t, images, 2)
+}
+
+func TestParseSize(t *testing.T) {
+	assert.Equal(t, int64(512), parseSize("512B"))
+	assert.Equal(t, int64(1536), parseSize("1.5 KiB"))
+	assert.Equal(t, int64(230000000), parseSize("230MB"))
+	assert.Equal(t, int64(0), parseSize("n/a"))
+}
+
+func TestParseCLIStats(t *testing.T) {
+	stats := parseCLIStats(cliStats{CPUPerc: "12.50%", MemUsage: "64MiB / 1GiB", NetIO: "1.5kB / 300B", BlockIO: "0B / 2MB", PIDs: "9"})
+	assert.Equal(t, &dockerapi.Stats{
+		CPUPercent: 12.5, MemoryUsage: 64 << 20, MemoryLimit: 1 << 30,
+		NetworkRx: 1500, NetworkTx: 300, BlockRead: 0, BlockWrite: 2000000, PIDs: 9,
+	}, stats)
+}
+
+func TestCLIEngine_ContainerList(t *testing.T) {
+	fakeCLIs(t, map[string]string{"nerdctl": `case "$1" in
+ps) echo abc123; echo def456 ;;
+container) printf '%s' '[{"Id":"abc123","Name":"shop-app-1","Created":"2024-05-01T10:00:00Z","State":{"Status":"running"},
+  "Config":{"Image":"phpier-shop:8.3","Labels":{"com.docker.compose.project":"shop"}},
+  "NetworkSettings":{"Ports":{"80/tcp":[{"HostIp":"0.0.0.0","HostPort":"8080"}]}}},
+ {"Id":"def456","Name":"other","State":{"Status":"exited"},"Config":{"Image":"alpine"}}]' ;;
+esac`})
+
+	engine := &cliEngine{cli: "nerdctl"}
+	containers, err := engine.ContainerList(context.Background(), dockerapi.ContainerListOptions{
+		All:     true,
+		Filters: dockerapi.Filters{"label": {"com.docker.compose.project=shop"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+	assert.Equal(t, "shop-app-1", containers[0].Name())
+	assert.Equal(t, "running", containers[0].State)
+	assert.Equal(t, []dockerapi.Port{{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"}}, containers[0].Ports)
+}
+
+func TestCLIEngine_NotFound(t *testing.T) {
+	fakeCLIs(t, map[string]string{"nerdctl": `echo "no such container: $3" >&2; exit 1`})
+
+	engine := &cliEngine{cli: "nerdctl"}
+	_, err := engine.ContainerInspect(context.Background(), "missing")
+	assert.True(t, dockerapi.IsNotFound(err))
+}
+
+func TestCLIEngine_Exec(t *testing.T) {
+	fakeCLIs(t, map[string]string{"nerdctl": `echo "$@"; exit 3`})
+
+	engine := &cliEngine{cli: "nerdctl"}
+	result, err := engine.Exec(context.Background(), "shop-app-1", dockerapi.ExecOptions{Cmd: []string{"php", "-v"}, User: "www-data"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.ExitCode)
+	assert.Equal(t, "exec --user www-data shop-app-1 php -v\n", result.Stdout)
+}
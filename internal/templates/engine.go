@@ -5,10 +5,16 @@ import (
 	"fmt"
 	"io/fs"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
 	"phpier/internal/config"
+	"phpier/internal/routes"
+	"phpier/internal/runtime"
+
+	"gopkg.in/yaml.v3"
 )
 
 //go:embed files
@@ -20,10 +26,33 @@ type Engine struct {
 	funcMap   template.FuncMap
 }
 
+// Dockerfile hook points where project snippets from .phpier/Dockerfile.d are included
+const (
+	HookAfterSystemDeps = "after-system-deps"
+	HookAfterExtensions = "after-extensions"
+	HookFinal           = "final"
+)
+
+// DockerfileHooks lists the supported Dockerfile hook points in build order
+var DockerfileHooks = []string{HookAfterSystemDeps, HookAfterExtensions, HookFinal}
+
+// TLSCertificate is a certificate/key pair as seen from inside the Traefik container
+type TLSCertificate struct {
+	CertFile string
+	KeyFile  string
+	Default  bool
+}
+
 // TemplateData represents data passed to templates
 type TemplateData struct {
-	Project *config.ProjectConfig
-	Global  *config.GlobalConfig
+	Project      *config.ProjectConfig
+	Global       *config.GlobalConfig
+	Hooks        map[string]string
+	BaseImage    string
+	Certificates []TLSCertificate
+	Route        *routes.Route
+	Runtime      runtime.Runtime
+	DNSAnswer    string // Address the DNS responder container answers *.<domain> with
 }
 
 // NewEngine creates a new template engine
@@ -57,34 +86,66 @@ func (e *Engine) Render(templateName string, data *TemplateData) (string, error)
 
 // RenderProjectDockerCompose renders the docker-compose.yml for a project
 func (e *Engine) RenderProjectDockerCompose(projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig) (string, error) {
+	r, err := runtime.Detect(globalCfg.RuntimePreference())
+	if err != nil {
+		return "", err
+	}
 	data := &TemplateData{
 		Project: projectCfg,
 		Global:  globalCfg,
+		Runtime: r,
 	}
 	return e.Render("docker-compose/project.yml", data)
 }
 
-// RenderGlobalDockerCompose renders the docker-compose.yml for the global services
-func (e *Engine) RenderGlobalDockerCompose(globalCfg *config.GlobalConfig) (string, error) {
+// RenderGlobalDockerCompose renders the docker-compose.yml for the global services.
+// dnsAnswer is the address the DNS responder answers with when dns.enabled is set.
+func (e *Engine) RenderGlobalDockerCompose(globalCfg *config.GlobalConfig, dnsAnswer string) (string, error) {
+	r, err := runtime.Detect(globalCfg.RuntimePreference())
+	if err != nil {
+		return "", err
+	}
 	data := &TemplateData{
-		Global: globalCfg,
+		Global:    globalCfg,
+		Runtime:   r,
+		DNSAnswer: dnsAnswer,
 	}
 	return e.Render("docker-compose/global.yml", data)
 }
 
-// RenderPHPDockerfile renders the appropriate PHP Dockerfile based on version
-func (e *Engine) RenderPHPDockerfile(projectCfg *config.ProjectConfig) (string, error) {
+// RenderPHPBaseDockerfile renders the shared base image Dockerfile for the project's PHP version.
+// Hooks that run before the project layers (after-system-deps, after-extensions) are part of the base.
+func (e *Engine) RenderPHPBaseDockerfile(projectCfg *config.ProjectConfig, hooks map[string]string) (string, error) {
 	templateName := e.selectPHPDockerfileTemplate(projectCfg.PHP)
 	data := &TemplateData{
 		Project: projectCfg,
+		Hooks: map[string]string{
+			HookAfterSystemDeps: hooks[HookAfterSystemDeps],
+			HookAfterExtensions: hooks[HookAfterExtensions],
+		},
 	}
 	return e.Render(templateName, data)
 }
 
+// RenderPHPDockerfile renders the project Dockerfile that builds FROM the shared base image.
+func (e *Engine) RenderPHPDockerfile(projectCfg *config.ProjectConfig, hooks map[string]string, baseImage string) (string, error) {
+	data := &TemplateData{
+		Project:   projectCfg,
+		Hooks:     map[string]string{HookFinal: hooks[HookFinal]},
+		BaseImage: baseImage,
+	}
+	return e.Render("dockerfiles/project.Dockerfile", data)
+}
+
 // RenderTraefikConfig renders the traefik.yml configuration
 func (e *Engine) RenderTraefikConfig(globalCfg *config.GlobalConfig) (string, error) {
+	r, err := runtime.Detect(globalCfg.RuntimePreference())
+	if err != nil {
+		return "", err
+	}
 	data := &TemplateData{
-		Global: globalCfg,
+		Global:  globalCfg,
+		Runtime: r,
 	}
 	return e.Render("configs/traefik.yml", data)
 }
@@ -97,6 +158,23 @@ func (e *Engine) RenderTraefikDynamicConfig(globalCfg *config.GlobalConfig) (str
 	return e.Render("configs/traefik-dynamic.yml", data)
 }
 
+// RenderTraefikTLSConfig renders a dynamic Traefik configuration file listing TLS certificates
+func (e *Engine) RenderTraefikTLSConfig(certificates []TLSCertificate) (string, error) {
+	data := &TemplateData{
+		Certificates: certificates,
+	}
+	return e.Render("configs/traefik-tls.yml", data)
+}
+
+// RenderTraefikRouteConfig renders the dynamic Traefik configuration file for a route to an upstream URL
+func (e *Engine) RenderTraefikRouteConfig(route *routes.Route, globalCfg *config.GlobalConfig) (string, error) {
+	data := &TemplateData{
+		Global: globalCfg,
+		Route:  route,
+	}
+	return e.Render("configs/traefik-route.yml", data)
+}
+
 // RenderPHPConfig renders the php.ini configuration
 func (e *Engine) RenderPHPConfig() (string, error) {
 	data := &TemplateData{}
@@ -183,6 +261,17 @@ func createFuncMap() template.FuncMap {
 			}
 			return false
 		},
+		"databaseEngineEnabled": func(engine string, globalCfg *config.GlobalConfig) bool {
+			if globalCfg == nil {
+				return false
+			}
+			for _, instance := range globalCfg.GetEnabledDatabases() {
+				if instance.Engine == engine {
+					return true
+				}
+			}
+			return false
+		},
 		"default": func(defaultValue interface{}, value interface{}) interface{} {
 			if value == nil || value == "" {
 				return defaultValue
@@ -190,9 +279,12 @@ func createFuncMap() template.FuncMap {
 			return value
 		},
 		"getHostRule": func(projectCfg *config.ProjectConfig, globalCfg *config.GlobalConfig) string {
-			// Auto-generate domain from project name + global domain
-			domain := projectCfg.Name + "." + globalCfg.Traefik.Domain
-			return "Host(`" + domain + "`)"
+			// Auto-generate domains from project name + global domain
+			var rules []string
+			for _, domain := range projectCfg.Domains(globalCfg.Traefik.Domain) {
+				rules = append(rules, "Host(`"+domain+"`)")
+			}
+			return strings.Join(rules, " || ")
 		},
 		"resolveNodeVersion": func(nodeVersion string) string {
 			switch nodeVersion {
@@ -213,5 +305,94 @@ func createFuncMap() template.FuncMap {
 		"split": func(s, sep string) []string {
 			return strings.Split(s, sep)
 		},
+		"toYaml": func(value interface{}) (string, error) {
+			var buf strings.Builder
+			encoder := yaml.NewEncoder(&buf)
+			encoder.SetIndent(2)
+			if err := encoder.Encode(value); err != nil {
+				return "", err
+			}
+			return strings.TrimSuffix(buf.String(), "\n"), nil
+		},
+		"indent": func(spaces int, s string) string {
+			pad := strings.Repeat(" ", spaces)
+			return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+		},
+		"hook": func(name string, data *TemplateData) string {
+			if data == nil || data.Hooks[name] == "" {
+				return ""
+			}
+			return "\n\n# Project hook: " + name + " (.phpier/Dockerfile.d)\n" + strings.TrimSpace(data.Hooks[name])
+		},
+		"basicAuthUsers": func(users map[string]string) string {
+			// user:hash pairs, with $ escaped so Compose doesn't interpolate the hashes
+			entries := make([]string, 0, len(users))
+			for user, hash := range users {
+				entries = append(entries, user+":"+strings.ReplaceAll(hash, "$", "$$"))
+			}
+			sort.Strings(entries)
+			return strings.Join(entries, ",")
+		},
+		"containerLogging": func(logging config.LoggingConfig, r runtime.Runtime) map[string]interface{} {
+			// Compose's logging block capping the container log, or nil when it is unbounded
+			if logging.MaxBytes() == 0 {
+				return nil
+			}
+			options := map[string]interface{}{"max-size": logging.MaxSize}
+			// Podman's json-file driver only knows the size limit
+			if r == nil || r.Name() != "podman" {
+				options["max-file"] = strconv.Itoa(logging.MaxFiles)
+			}
+			return map[string]interface{}{"logging": map[string]interface{}{
+				"driver":  "json-file",
+				"options": options,
+			}}
+		},
+		"deployResources": func(resources config.ResourcesConfig) map[string]interface{} {
+			// Compose's deploy.resources block, or nil when nothing is limited or reserved
+			limits := map[string]interface{}{}
+			if resources.CPUs != "" {
+				limits["cpus"] = resources.CPUs
+			}
+			if resources.Memory != "" {
+				limits["memory"] = resources.Memory
+			}
+			if resources.PIDs > 0 {
+				limits["pids"] = resources.PIDs
+			}
+			reservations := map[string]interface{}{}
+			if resources.Reservations.CPUs != "" {
+				reservations["cpus"] = resources.Reservations.CPUs
+			}
+			if resources.Reservations.Memory != "" {
+				reservations["memory"] = resources.Reservations.Memory
+			}
+
+			block := map[string]interface{}{}
+			if len(limits) > 0 {
+				block["limits"] = limits
+			}
+			if len(reservations) > 0 {
+				block["reservations"] = reservations
+			}
+			if len(block) == 0 {
+				return nil
+			}
+			return map[string]interface{}{"deploy": map[string]interface{}{"resources": block}}
+		},
+		"upper": strings.ToUpper,
+		"base":  filepath.Base,
+		"dir":   filepath.Dir,
+		"join": func(elems []string, sep string) string {
+			return strings.Join(elems, sep)
+		},
+		"sortedKeys": func(m map[string]string) []string {
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			return keys
+		},
 	}
 }
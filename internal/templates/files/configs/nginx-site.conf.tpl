@@ -11,6 +11,17 @@ server {
     add_header X-Frame-Options DENY;
     add_header X-XSS-Protection "1; mode=block";
 
+    # php-fpm ping for the container healthcheck, only answered inside the container
+    location = /fpm-ping {
+        allow 127.0.0.1;
+        deny all;
+        access_log off;
+        fastcgi_pass 127.0.0.1:9000;
+        include fastcgi_params;
+        fastcgi_param SCRIPT_NAME /fpm-ping;
+        fastcgi_param SCRIPT_FILENAME /fpm-ping;
+    }
+
     # PHP handling
     location ~ \.php$ {
         fastcgi_split_path_info ^(.+\.php)(/.+)$;
@@ -16,7 +16,7 @@ display_errors = On
 display_startup_errors = On
 error_reporting = E_ALL
 log_errors = On
-error_log = /var/log/php_errors.log
+error_log = /var/log/php/errors.log
 
 ; Development settings
 html_errors = On
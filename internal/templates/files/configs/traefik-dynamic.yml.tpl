@@ -5,6 +5,25 @@
 http:
   # Middlewares for common functionality
   middlewares:
+    # Redirect plain HTTP requests to HTTPS (enabled with traefik.https_redirect)
+    redirect-to-https:
+      redirectScheme:
+        scheme: https
+        permanent: false
+{{- if ne .Global.Traefik.SSLPort 443}}
+        port: "{{.Global.Traefik.SSLPort}}"
+{{- end}}
+{{- if .Global.Traefik.DashboardAuth}}
+
+    # Basic auth for the dashboard and API (traefik.dashboard_auth)
+    dashboard-auth:
+      basicAuth:
+        users:
+{{- range $user := sortedKeys .Global.Traefik.DashboardAuth}}
+          - "{{$user}}:{{index $.Global.Traefik.DashboardAuth $user}}"
+{{- end}}
+{{- end}}
+
     # Security headers
     security-headers:
       headers:
@@ -45,9 +64,45 @@ http:
 
   # Static routers (most routing will be automatic via Docker labels)
   routers:
-    # Traefik dashboard (development only)
+{{- if .Global.Traefik.Dashboard}}
+    # Traefik dashboard and API on the dashboard port
+    dashboard:
+      rule: "PathPrefix(`/api`) || PathPrefix(`/dashboard`)"
+      service: api@internal
+      entryPoints:
+        - traefik
+{{- if .Global.Traefik.DashboardAuth}}
+      middlewares:
+        - dashboard-auth
+{{- end}}
+
+    # Traefik dashboard on traefik.<domain>
     api:
       rule: "Host(`traefik.{{.Global.Traefik.Domain}}`)"
       service: api@internal
       entryPoints:
-        - web
\ No newline at end of file
+        - web
+{{- if or .Global.Traefik.HTTPSRedirect .Global.Traefik.DashboardAuth}}
+      middlewares:
+{{- if .Global.Traefik.HTTPSRedirect}}
+        - redirect-to-https
+{{- end}}
+{{- if .Global.Traefik.DashboardAuth}}
+        - dashboard-auth
+{{- end}}
+{{- end}}
+
+    api-secure:
+      rule: "Host(`traefik.{{.Global.Traefik.Domain}}`)"
+      service: api@internal
+      entryPoints:
+        - websecure
+{{- if .Global.Traefik.DashboardAuth}}
+      middlewares:
+        - dashboard-auth
+{{- end}}
+      tls: {}
+{{- else}}
+    # The dashboard is disabled (traefik.dashboard: false)
+    {}
+{{- end}}
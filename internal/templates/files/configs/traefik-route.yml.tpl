@@ -0,0 +1,28 @@
+# Route {{.Route.Name}}: {{join .Route.Hosts ", "}} -> {{.Route.URL}}
+# Generated by 'phpier route add' - remove with 'phpier route remove {{.Route.Name}}'
+
+http:
+  routers:
+    {{.Route.RouterName}}:
+      rule: "{{.Route.HostRule}}"
+      service: {{.Route.RouterName}}
+      entryPoints:
+        - web
+{{- if .Global.Traefik.HTTPSRedirect}}
+      middlewares:
+        - redirect-to-https
+{{- end}}
+
+    {{.Route.RouterName}}-secure:
+      rule: "{{.Route.HostRule}}"
+      service: {{.Route.RouterName}}
+      entryPoints:
+        - websecure
+      tls: {}
+
+  services:
+    {{.Route.RouterName}}:
+      loadBalancer:
+        passHostHeader: true
+        servers:
+          - url: "{{.Route.URL}}"
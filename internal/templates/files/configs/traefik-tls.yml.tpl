@@ -0,0 +1,18 @@
+# TLS certificates issued by the phpier local certificate authority
+# Generated by phpier
+
+tls:
+  certificates:
+{{- range .Certificates}}
+    - certFile: {{.CertFile}}
+      keyFile: {{.KeyFile}}
+{{- end}}
+{{- range .Certificates}}
+{{- if .Default}}
+  stores:
+    default:
+      defaultCertificate:
+        certFile: {{.CertFile}}
+        keyFile: {{.KeyFile}}
+{{- end}}
+{{- end}}
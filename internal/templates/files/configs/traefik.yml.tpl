@@ -6,27 +6,52 @@ global:
   checkNewVersion: false
   sendAnonymousUsage: false
 
-# Entry points
+# Entry points (container ports; the host ports are mapped in docker-compose.yml)
 entryPoints:
   web:
-    address: ":{{.Global.Traefik.Port}}"
+    address: ":80"
   websecure:
-    address: ":{{.Global.Traefik.SSLPort}}"
+    address: ":443"
+  # TCP entrypoints for databases and Redis (published on the host when traefik.tcp_mode is proxy)
+{{- range .Global.GetEnabledDatabases}}
+  {{.Name}}:
+    address: ":{{.Port}}"
+{{- end}}
+{{- if serviceEnabled "redis" .Global}}
+  redis:
+    address: ":6379"
+{{- end}}
+  # Ping endpoint for the container healthcheck; not published on the host
+  ping:
+    address: ":8082"
+{{- if .Global.Traefik.ExposesAPIPort}}
+  # Dashboard, API and metrics
+  traefik:
+    address: ":8080"
+{{- end}}
 
-# API and dashboard
+# Health endpoint used by 'traefik healthcheck --ping'
+ping:
+  entryPoint: ping
+
+# API and dashboard, served on the traefik entrypoint (routers in dynamic/api.yml)
 api:
-  dashboard: true
-  insecure: true
+  dashboard: {{.Global.Traefik.Dashboard}}
 
 # Providers
 providers:
-  # Docker provider for automatic service discovery
+{{- if eq .Runtime.Name "nerdctl"}}
+  # nerdctl has no Docker API, so projects are routed through files in the dynamic directory
+{{- else}}
+  # Docker provider for automatic service discovery. Containers with a failing
+  # healthcheck are left out of routing until they report healthy again.
   docker:
     endpoint: "unix:///var/run/docker.sock"
     exposedByDefault: false
     network: "{{.Global.Network}}"
     watch: true
-    
+{{- end}}
+
   # File provider for static configuration
   file:
     directory: "/etc/traefik/dynamic"
@@ -34,12 +59,23 @@ providers:
 
 # Log configuration
 log:
-  level: "INFO"
+  level: "{{upper .Global.Traefik.LogLevel}}"
+{{- with .Global.Traefik.AccessLog}}
+{{- if .Enabled}}
 
-accessLog: {}
+accessLog:
+  format: "{{.Format}}"
+{{- if .Path}}
+  filePath: "/var/log/traefik/{{base .Path}}"
+{{- end}}
+{{- end}}
+{{- end}}
+{{- if .Global.Traefik.Metrics}}
 
-# Metrics (optional)
+# Prometheus metrics on the traefik entrypoint (/metrics)
 metrics:
   prometheus:
+    entryPoint: traefik
     addEntryPointsLabels: true
-    addServicesLabels: true
\ No newline at end of file
+    addServicesLabels: true
+{{- end}}
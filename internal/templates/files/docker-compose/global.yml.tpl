@@ -2,104 +2,149 @@ version: '3.8'
 
 services:
   traefik:
-    image: traefik:v2.10
+    image: traefik:{{.Global.Traefik.Version}}
     container_name: phpier-traefik
     restart: unless-stopped
+{{- with deployResources .Global.Traefik.Resources}}
+{{toYaml . | indent 4}}
+{{- end}}
+{{- with containerLogging .Global.Logging .Runtime}}
+{{toYaml . | indent 4}}
+{{- end}}
     ports:
       - "{{.Global.Traefik.Port}}:80"
       - "{{.Global.Traefik.SSLPort}}:443"
-      - "8080:8080"
+{{- if .Global.Traefik.ExposesAPIPort}}
+      - "{{.Global.Traefik.DashboardPort}}:8080"
+{{- end}}
+{{- if .Global.Traefik.TCPProxy}}
+{{- range .Global.GetEnabledDatabases}}
+      - "{{.Port}}:{{.Port}}"
+{{- end}}
+{{- if serviceEnabled "redis" .Global}}
+      - "{{.Global.Services.Cache.Redis.Port}}:6379"
+{{- end}}
+{{- end}}
     volumes:
-      - /var/run/docker.sock:/var/run/docker.sock:ro
+{{- if ne .Runtime.Name "nerdctl"}}
+      # The runtime's Docker-compatible API socket, read by Traefik's Docker provider
+      - {{default "/var/run/docker.sock" .Runtime.Socket}}:/var/run/docker.sock:ro
+{{- end}}
       - ./traefik/traefik.yml:/etc/traefik/traefik.yml:ro
       - ./traefik/dynamic:/etc/traefik/dynamic:ro
+      - ./certs:/etc/traefik/certs:ro
+{{- if and .Global.Traefik.AccessLog.Enabled .Global.Traefik.AccessLog.Path}}
+      - {{dir .Global.Traefik.AccessLog.Path}}:/var/log/traefik
+{{- end}}
+    healthcheck:
+      test: ["CMD", "traefik", "healthcheck", "--ping"]
+      interval: 10s
+      timeout: 5s
+      retries: 5
+{{- if eq .Runtime.Name "podman"}}
+    # SELinux labels would keep Traefik from reading the Podman socket. Podman maps
+    # host.docker.internal to the host itself, so 'phpier route add' needs no extra_hosts.
+    security_opt:
+      - label=disable
+{{- else}}
+    # Lets 'phpier route add' reach services running on the host (Linux needs the explicit mapping)
+    extra_hosts:
+      - "host.docker.internal:host-gateway"
+{{- end}}
     networks:
       - {{.Global.Network}}
+{{- if .Global.Traefik.Dashboard}}
     labels:
       - "traefik.enable=true"
       - "traefik.http.routers.traefik.rule=Host(`phpier-traefik.{{.Global.Traefik.Domain}}`)"
       - "traefik.http.routers.traefik.entrypoints=web"
+{{- if .Global.Traefik.HTTPSRedirect}}
+      - "traefik.http.routers.traefik.middlewares=redirect-to-https@file"
+{{- end}}
+      - "traefik.http.routers.traefik-secure.rule=Host(`phpier-traefik.{{.Global.Traefik.Domain}}`)"
+      - "traefik.http.routers.traefik-secure.entrypoints=websecure"
+      - "traefik.http.routers.traefik-secure.tls=true"
       - "traefik.http.services.traefik.loadbalancer.server.port=8080"
+{{- end}}
 
-  {{if .Global.Services.Databases.MySQL.Enabled}}
-  mysql:
-    image: mysql:{{.Global.Services.Databases.MySQL.Version}}
-    container_name: phpier-mysql
-    restart: unless-stopped
-    command: --default-authentication-plugin=mysql_native_password
-    environment:
-      MYSQL_ROOT_PASSWORD: {{.Global.Services.Databases.MySQL.Password}}
-      MYSQL_DATABASE: {{.Global.Services.Databases.MySQL.Database}}
-      MYSQL_USER: {{.Global.Services.Databases.MySQL.Username}}
-      MYSQL_PASSWORD: {{.Global.Services.Databases.MySQL.Password}}
-    volumes:
-      - mysql_data:/var/lib/mysql
-    ports:
-      - "{{.Global.Services.Databases.MySQL.Port}}:3306"
-    networks:
-      - {{.Global.Network}}
-    labels:
-      - "traefik.enable=true"
-      - "traefik.tcp.routers.mysql.rule=HostSNI(`*`)"
-      - "traefik.tcp.routers.mysql.entrypoints=mysql"
-      - "traefik.tcp.services.mysql.loadbalancer.server.port=3306"
-  {{end}}
+{{- range .Global.GetEnabledDatabases}}
 
-  {{if .Global.Services.Databases.PostgreSQL.Enabled}}
-  postgres:
-    image: postgres:{{.Global.Services.Databases.PostgreSQL.Version}}
-    container_name: phpier-postgres
+  {{.Name}}:
+    image: {{.Image}}
+    container_name: {{.ContainerName}}
     restart: unless-stopped
+{{- with deployResources .Resources}}
+{{toYaml . | indent 4}}
+{{- end}}
+{{- with containerLogging $.Global.Logging $.Runtime}}
+{{toYaml . | indent 4}}
+{{- end}}
+{{- if eq .Engine "postgresql"}}
     environment:
-      POSTGRES_DB: {{.Global.Services.Databases.PostgreSQL.Database}}
-      POSTGRES_USER: {{.Global.Services.Databases.PostgreSQL.Username}}
-      POSTGRES_PASSWORD: {{.Global.Services.Databases.PostgreSQL.Password}}
+      POSTGRES_DB: {{.Database}}
+      POSTGRES_USER: {{.Username}}
+      POSTGRES_PASSWORD: {{.Password}}
     volumes:
-      - postgres_data:/var/lib/postgresql/data
-    ports:
-      - "{{.Global.Services.Databases.PostgreSQL.Port}}:5432"
-    networks:
-      - {{.Global.Network}}
-    labels:
-      - "traefik.enable=true"
-      - "traefik.tcp.routers.postgres.rule=HostSNI(`*`)"
-      - "traefik.tcp.routers.postgres.entrypoints=postgres"
-      - "traefik.tcp.services.postgres.loadbalancer.server.port=5432"
-  {{end}}
-
-  {{if .Global.Services.Databases.MariaDB.Enabled}}
-  mariadb:
-    image: mariadb:{{.Global.Services.Databases.MariaDB.Version}}
-    container_name: phpier-mariadb
-    restart: unless-stopped
-    command: --default-authentication-plugin=mysql_native_password
+      - {{.Name}}_data:/var/lib/postgresql/data
+{{- else}}
+{{- with .ServerCommand}}
+    command: {{.}}
+{{- end}}
     environment:
-      MYSQL_ROOT_PASSWORD: {{.Global.Services.Databases.MariaDB.Password}}
-      MYSQL_DATABASE: {{.Global.Services.Databases.MariaDB.Database}}
-      MYSQL_USER: {{.Global.Services.Databases.MariaDB.Username}}
-      MYSQL_PASSWORD: {{.Global.Services.Databases.MariaDB.Password}}
+      MYSQL_ROOT_PASSWORD: {{.Password}}
+      MYSQL_DATABASE: {{.Database}}
+      MYSQL_USER: {{.Username}}
+      MYSQL_PASSWORD: {{.Password}}
     volumes:
-      - mariadb_data:/var/lib/mysql
+      - {{.Name}}_data:/var/lib/mysql
+{{- end}}
+{{- if not $.Global.Traefik.TCPProxy}}
     ports:
-      - "{{.Global.Services.Databases.MariaDB.Port}}:3306"
+      - "{{.Port}}:{{.ContainerPort}}"
+{{- end}}
+    healthcheck:
+{{- if eq .Engine "postgresql"}}
+      test: ["CMD-SHELL", "pg_isready -U {{.Username}} -d {{.Database}}"]
+{{- else if eq .Engine "mariadb"}}
+      test: ["CMD", "healthcheck.sh", "--connect", "--innodb_initialized"]
+{{- else}}
+      test: ["CMD", "mysqladmin", "ping", "-h", "127.0.0.1", "--silent"]
+{{- end}}
+      interval: 10s
+      timeout: 5s
+      retries: 5
+      start_period: 30s
     networks:
-      - {{.Global.Network}}
+      - {{$.Global.Network}}
     labels:
       - "traefik.enable=true"
-      - "traefik.tcp.routers.mariadb.rule=HostSNI(`*`)"
-      - "traefik.tcp.routers.mariadb.entrypoints=mariadb"
-      - "traefik.tcp.services.mariadb.loadbalancer.server.port=3306"
-  {{end}}
+      - "traefik.tcp.routers.{{.Name}}.rule=HostSNI(`*`)"
+      - "traefik.tcp.routers.{{.Name}}.entrypoints={{.Name}}"
+      - "traefik.tcp.services.{{.Name}}.loadbalancer.server.port={{.ContainerPort}}"
+{{- end}}
 
   {{if serviceEnabled "redis" .Global}}
   redis:
     image: redis:alpine
     container_name: phpier-redis
     restart: unless-stopped
+{{- with deployResources .Global.Services.Cache.Redis.Resources}}
+{{toYaml . | indent 4}}
+{{- end}}
+{{- with containerLogging .Global.Logging .Runtime}}
+{{toYaml . | indent 4}}
+{{- end}}
     volumes:
       - redis_data:/data
+{{- if not .Global.Traefik.TCPProxy}}
     ports:
       - "{{.Global.Services.Cache.Redis.Port}}:6379"
+{{- end}}
+    healthcheck:
+      test: ["CMD", "redis-cli", "ping"]
+      interval: 10s
+      timeout: 5s
+      retries: 5
     networks:
       - {{.Global.Network}}
     labels:
@@ -114,47 +159,99 @@ services:
     image: axllent/mailpit
     container_name: phpier-mailpit
     restart: unless-stopped
+{{- with deployResources .Global.Services.Tools.Mailpit.Resources}}
+{{toYaml . | indent 4}}
+{{- end}}
+{{- with containerLogging .Global.Logging .Runtime}}
+{{toYaml . | indent 4}}
+{{- end}}
     ports:
-      - "1026:1025"
-      - "8026:8025"
+      - "{{.Global.Services.Tools.Mailpit.SMTPPort}}:1025"
+      - "{{.Global.Services.Tools.Mailpit.UIPort}}:8025"
+    healthcheck:
+      test: ["CMD", "/mailpit", "readyz"]
+      interval: 10s
+      timeout: 5s
+      retries: 5
     networks:
       - {{.Global.Network}}
     labels:
       - "traefik.enable=true"
       - "traefik.http.routers.mailpit.rule=Host(`phpier-mailpit.{{.Global.Traefik.Domain}}`)"
       - "traefik.http.routers.mailpit.entrypoints=web"
+{{- if .Global.Traefik.HTTPSRedirect}}
+      - "traefik.http.routers.mailpit.middlewares=redirect-to-https@file"
+{{- end}}
+      - "traefik.http.routers.mailpit-secure.rule=Host(`phpier-mailpit.{{.Global.Traefik.Domain}}`)"
+      - "traefik.http.routers.mailpit-secure.entrypoints=websecure"
+      - "traefik.http.routers.mailpit-secure.tls=true"
       - "traefik.http.services.mailpit.loadbalancer.server.port=8025"
   {{end}}
 
   # Web interfaces for database and cache management
-  {{if or .Global.Services.Databases.MySQL.Enabled .Global.Services.Databases.MariaDB.Enabled}}
+  {{if or (databaseEngineEnabled "mysql" .Global) (databaseEngineEnabled "mariadb" .Global)}}
   adminer:
     image: adminer:latest
     container_name: phpier-adminer
     restart: unless-stopped
+{{- with containerLogging .Global.Logging .Runtime}}
+{{toYaml . | indent 4}}
+{{- end}}
     networks:
       - {{.Global.Network}}
     labels:
       - "traefik.enable=true"
       - "traefik.http.routers.adminer.rule=Host(`phpier-adminer.{{.Global.Traefik.Domain}}`)"
       - "traefik.http.routers.adminer.entrypoints=web"
+{{- if .Global.Traefik.HTTPSRedirect}}
+      - "traefik.http.routers.adminer.middlewares=redirect-to-https@file"
+{{- end}}
+      - "traefik.http.routers.adminer-secure.rule=Host(`phpier-adminer.{{.Global.Traefik.Domain}}`)"
+      - "traefik.http.routers.adminer-secure.entrypoints=websecure"
+      - "traefik.http.routers.adminer-secure.tls=true"
       - "traefik.http.services.adminer.loadbalancer.server.port=8080"
   {{end}}
 
+  {{if .Global.DNS.Enabled}}
+  # DNS responder for *.{{.Global.Traefik.Domain}}: 'phpier dns serve' run from the phpier
+  # binary the generator copies to ~/.phpier/dns
+  dns:
+    image: busybox:stable-glibc
+    container_name: phpier-dns
+    restart: unless-stopped
+{{- with deployResources .Global.DNS.Resources}}
+{{toYaml . | indent 4}}
+{{- end}}
+{{- with containerLogging .Global.Logging .Runtime}}
+{{toYaml . | indent 4}}
+{{- end}}
+    command:
+      - /phpier/phpier
+      - dns
+      - serve
+      - --domain={{.Global.Traefik.Domain}}
+      - --ip={{.DNSAnswer}}
+      - --listen=0.0.0.0
+      - --port=53
+{{- if .Global.DNS.Upstream}}
+      - --upstream={{.Global.DNS.Upstream}}
+{{- end}}
+    volumes:
+      - ./dns:/phpier:ro
+    ports:
+      - "{{if ne .Global.DNS.IP "lan"}}127.0.0.1:{{end}}{{.Global.DNS.Port}}:53/udp"
+    networks:
+      - {{.Global.Network}}
+  {{end}}
+
 networks:
   {{.Global.Network}}:
     driver: bridge
 
 volumes:
-  {{if .Global.Services.Databases.MySQL.Enabled}}
-  mysql_data:
-  {{end}}
-  {{if .Global.Services.Databases.PostgreSQL.Enabled}}
-  postgres_data:
-  {{end}}
-  {{if .Global.Services.Databases.MariaDB.Enabled}}
-  mariadb_data:
-  {{end}}
+{{- range .Global.GetEnabledDatabases}}
+  {{.Name}}_data:
+{{- end}}
   {{if serviceEnabled "redis" .Global}}
   redis_data:
   {{end}}
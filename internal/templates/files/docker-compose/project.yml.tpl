@@ -1,13 +1,51 @@
 name: {{.Project.Name}}
 
+# phpier project settings (ignored by Docker Compose)
+x-phpier:
+{{ toYaml .Project | indent 2 }}
+
 services:
   app:
     build:
       context: .
       dockerfile: .phpier/Dockerfile.php
+      # build.args, after the corporate proxy settings passed through from the host when set
+      args:
+{{toYaml .Project.Build.ComposeArgs | indent 8}}
+{{- if .Project.Build.Secrets}}
+      secrets:
+{{- range $id := sortedKeys .Project.Build.Secrets}}
+        - {{$id}}
+{{- end}}
+{{- end}}
     image: phpier-{{.Project.Name}}:{{.Project.PHP}}
     container_name: {{.Project.Name}}-app
     restart: unless-stopped
+    # Passes once nginx serves requests and php-fpm answers its ping through it. Traefik
+    # only routes to the app once it is healthy, so the first probe comes early.
+    healthcheck:
+      test: ["CMD", "curl", "-fsS", "-o", "/dev/null", "http://127.0.0.1/fpm-ping"]
+      interval: 5s
+      timeout: 3s
+      retries: 5
+      start_period: 30s
+{{- with deployResources .Project.Resources}}
+{{toYaml . | indent 4}}
+{{- end}}
+{{- with containerLogging .Global.Logging .Runtime}}
+{{toYaml . | indent 4}}
+{{- end}}
+{{- if eq .Runtime.Name "podman"}}
+    # SELinux labels would block the bind-mounted project files
+    security_opt:
+      - label=disable
+{{- if .Runtime.Rootless}}
+    # Rootless Podman: keep the host UID inside the container so WWWUSER owns the
+    # bind-mounted files; the entrypoint starts as root and drops to WWWUSER
+    userns_mode: keep-id
+    user: root
+{{- end}}
+{{- end}}
     volumes:
 {{- range $volume := .Project.App.Volumes}}
       - {{$volume}}
@@ -17,19 +55,90 @@ services:
       - ./.phpier/logs/supervisor:/var/log/supervisor
     environment:
       - WWWUSER=${WWWUSER}
+      # Corporate proxy settings are passed through from the host when set
+      - HTTP_PROXY
+      - HTTPS_PROXY
+      - NO_PROXY
 {{- if .Project.App.Environment}}
 {{- range $env := .Project.App.Environment}}
       - {{$env}}
 {{- end}}
+{{- end}}
+{{- with .Project.DatabaseEnvironment}}
+      # The project's own database on the shared {{$.Project.Database.Engine}} server
+{{- range $env := .}}
+      - {{$env}}
+{{- end}}
+{{- end}}
+{{- if .Project.DevServer.Enabled}}
+      # Frontend dev server (hot module replacement through Traefik)
+      - VITE_HMR_HOST={{.Project.DevServerDomain .Global.Traefik.Domain}}
+      - VITE_HMR_PROTOCOL={{.Project.DevServerHMRProtocol .Global.Traefik}}
+      - VITE_HMR_CLIENT_PORT={{.Project.DevServerHMRPort .Global.Traefik}}
+      - VITE_DEV_SERVER_PORT={{.Project.DevServerPort}}
 {{- end}}
     networks:
-      - {{.Global.Network}}
+      {{.Global.Network}}:
+        # Other projects reach this app by these names on the shared network
+        aliases:
+{{- range $alias := .Project.NetworkAliases .Global.Traefik.Domain}}
+          - {{$alias}}
+{{- end}}
     labels:
       # Traefik configuration
       - "traefik.enable=true"
       - "traefik.http.routers.{{.Project.Name}}.rule={{getHostRule .Project .Global}}"
       - "traefik.http.routers.{{.Project.Name}}.entrypoints=web"
+      - "traefik.http.routers.{{.Project.Name}}.service={{.Project.Name}}"
+{{- if .Global.Traefik.HTTPSRedirect}}
+      - "traefik.http.routers.{{.Project.Name}}.middlewares=redirect-to-https@file"
+{{- else if .Project.MiddlewareChain}}
+      - "traefik.http.routers.{{.Project.Name}}.middlewares={{join .Project.MiddlewareChain ","}}"
+{{- end}}
+      - "traefik.http.routers.{{.Project.Name}}-secure.rule={{getHostRule .Project .Global}}"
+      - "traefik.http.routers.{{.Project.Name}}-secure.entrypoints=websecure"
+      - "traefik.http.routers.{{.Project.Name}}-secure.tls=true"
+      - "traefik.http.routers.{{.Project.Name}}-secure.service={{.Project.Name}}"
+{{- if .Project.MiddlewareChain}}
+      - "traefik.http.routers.{{.Project.Name}}-secure.middlewares={{join .Project.MiddlewareChain ","}}"
+{{- end}}
+{{- with .Project.Middlewares}}
+{{- if .IPAllowList}}
+      - "traefik.http.middlewares.{{$.Project.Name}}-ipallowlist.{{if $.Global.Traefik.V3}}ipallowlist{{else}}ipwhitelist{{end}}.sourcerange={{join .IPAllowList ","}}"
+{{- end}}
+{{- if .BasicAuth}}
+      - "traefik.http.middlewares.{{$.Project.Name}}-auth.basicauth.users={{basicAuthUsers .BasicAuth}}"
+{{- end}}
+{{- if .RateLimit}}
+      - "traefik.http.middlewares.{{$.Project.Name}}-ratelimit.ratelimit.average={{.RateLimit.Average}}"
+{{- if .RateLimit.Burst}}
+      - "traefik.http.middlewares.{{$.Project.Name}}-ratelimit.ratelimit.burst={{.RateLimit.Burst}}"
+{{- end}}
+{{- end}}
+{{- if .Headers}}
+{{- range $name := sortedKeys .Headers}}
+      - "traefik.http.middlewares.{{$.Project.Name}}-headers.headers.customresponseheaders.{{$name}}={{index $.Project.Middlewares.Headers $name}}"
+{{- end}}
+{{- end}}
+{{- end}}
       - "traefik.http.services.{{.Project.Name}}.loadbalancer.server.port=80"
+{{- if .Project.DevServer.Enabled}}
+      # Frontend dev server; Traefik proxies the HMR websocket as-is
+      - "traefik.http.routers.{{.Project.Name}}-devserver.rule=Host(`{{.Project.DevServerDomain .Global.Traefik.Domain}}`)"
+      - "traefik.http.routers.{{.Project.Name}}-devserver.entrypoints=web"
+      - "traefik.http.routers.{{.Project.Name}}-devserver.service={{.Project.Name}}-devserver"
+      - "traefik.http.routers.{{.Project.Name}}-devserver.middlewares={{.Project.Name}}-devserver-cors"
+      - "traefik.http.routers.{{.Project.Name}}-devserver-secure.rule=Host(`{{.Project.DevServerDomain .Global.Traefik.Domain}}`)"
+      - "traefik.http.routers.{{.Project.Name}}-devserver-secure.entrypoints=websecure"
+      - "traefik.http.routers.{{.Project.Name}}-devserver-secure.tls=true"
+      - "traefik.http.routers.{{.Project.Name}}-devserver-secure.service={{.Project.Name}}-devserver"
+      - "traefik.http.routers.{{.Project.Name}}-devserver-secure.middlewares={{.Project.Name}}-devserver-cors"
+      - "traefik.http.middlewares.{{.Project.Name}}-devserver-cors.headers.accesscontrolalloworiginlist={{join (.Project.DevServerOrigins .Global.Traefik) ","}}"
+      - "traefik.http.middlewares.{{.Project.Name}}-devserver-cors.headers.accesscontrolallowmethods=GET,HEAD,OPTIONS"
+      - "traefik.http.middlewares.{{.Project.Name}}-devserver-cors.headers.accesscontrolallowheaders=*"
+      - "traefik.http.middlewares.{{.Project.Name}}-devserver-cors.headers.addvaryheader=true"
+      - "traefik.http.services.{{.Project.Name}}-devserver.loadbalancer.server.port={{.Project.DevServerPort}}"
+{{- end}}
       - "traefik.docker.network={{.Global.Network}}"
       # Phpier metadata
       - "phpier.project.name={{.Project.Name}}"
@@ -37,7 +146,16 @@ services:
       - "phpier.project.node={{.Project.Node}}"
       - "phpier.managed=true"
 
+{{- if .Project.Build.Secrets}}
+
+secrets:
+{{- range $id := sortedKeys .Project.Build.Secrets}}
+  {{$id}}:
+    file: {{index $.Project.Build.Secrets $id}}
+{{- end}}
+{{- end}}
+
 networks:
   {{.Global.Network}}:
     external: true
-    name: phpier_{{.Global.Network}}
+    name: {{.Global.DockerNetwork}}
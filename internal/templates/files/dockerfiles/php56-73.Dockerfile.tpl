@@ -1,5 +1,9 @@
+# Shared phpier base image for PHP {{.Project.PHP}}
+# Generated by phpier - project Dockerfiles build FROM this image
 FROM php:{{.Project.PHP}}-fpm
 
+LABEL phpier.base="true" phpier.php="{{.Project.PHP}}"
+
 # Set working directory
 WORKDIR /var/www/html
 
@@ -10,6 +14,12 @@ RUN sed -i 's|http://deb.debian.org/debian|http://archive.debian.org/debian|g' /
     && sed -i '/stretch-updates/d' /etc/apt/sources.list \
     && sed -i '/buster-updates/d' /etc/apt/sources.list \
     && sed -i '/bullseye-updates/d' /etc/apt/sources.list
+{{- if .Project.Build.CACertificates }}
+
+# Trust custom CA certificates (build.ca_certificates)
+COPY certs/ /usr/local/share/ca-certificates/phpier/
+RUN update-ca-certificates
+{{- end }}
 
 # Install system dependencies for older PHP versions  
 RUN apt-get update && apt-get install -y --allow-unauthenticated \
@@ -31,7 +41,7 @@ RUN apt-get update && apt-get install -y --allow-unauthenticated \
     libcurl4-openssl-dev \
     libssl-dev \
     zlib1g-dev \
-    && rm -rf /var/lib/apt/lists/*
+    && rm -rf /var/lib/apt/lists/*{{ hook "after-system-deps" . }}
 
 # Configure PHP extensions for older versions
 RUN docker-php-ext-configure gd --with-freetype-dir=/usr/include/ --with-jpeg-dir=/usr/include/
@@ -61,7 +71,7 @@ RUN docker-php-ext-install -j$(nproc) \
 
 # Install PECL extensions compatible with older PHP versions
 RUN pecl install redis-4.3.0 \
-    && docker-php-ext-enable redis
+    && docker-php-ext-enable redis{{ hook "after-extensions" . }}
 
 # Install Composer (version compatible with PHP version)
 {{- if or (eq .Project.PHP "5.6") (eq .Project.PHP "7.0") (eq .Project.PHP "7.1") (eq .Project.PHP "7.2") (eq .Project.PHP "7.3") }}
@@ -75,29 +85,8 @@ COPY --from=composer:latest /usr/bin/composer /usr/bin/composer
 # Node.js installation skipped for PHP 5.6 to avoid compatibility issues with Debian Stretch
 # If you need Node.js with PHP 5.6, consider using a newer PHP version or manual installation
 
-# Copy custom PHP configuration
-COPY .phpier/docker/php/php.ini /usr/local/etc/php/conf.d/custom.ini
-
-# Configure Nginx
-COPY .phpier/docker/nginx/nginx.conf /etc/nginx/nginx.conf
-COPY .phpier/docker/nginx/default.conf /etc/nginx/sites-available/default
-RUN ln -sf /etc/nginx/sites-available/default /etc/nginx/sites-enabled/default
-
-# Configure Supervisor
-COPY .phpier/docker/supervisor/supervisord.conf /etc/supervisor/conf.d/supervisord.conf
-
-# Copy entrypoint script and make it executable
-COPY .phpier/docker/entrypoint.sh /usr/local/bin/start
-RUN chmod +x /usr/local/bin/start
-
 # Create phpier user for permission mapping
 RUN useradd -ms /bin/bash -u 1337 phpier
 
 # Create www-data user directories
 RUN mkdir -p /var/www/html && chown www-data:www-data /var/www/html
-
-# Expose port
-EXPOSE 80
-
-# Set entrypoint
-ENTRYPOINT ["start"]
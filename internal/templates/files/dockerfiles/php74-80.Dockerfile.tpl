@@ -1,7 +1,17 @@
+# Shared phpier base image for PHP {{.Project.PHP}}
+# Generated by phpier - project Dockerfiles build FROM this image
 FROM php:{{.Project.PHP}}-fpm
 
+LABEL phpier.base="true" phpier.php="{{.Project.PHP}}"
+
 # Set working directory
 WORKDIR /var/www/html
+{{- if .Project.Build.CACertificates }}
+
+# Trust custom CA certificates (build.ca_certificates)
+COPY certs/ /usr/local/share/ca-certificates/phpier/
+RUN update-ca-certificates
+{{- end }}
 
 # Install system dependencies for PHP 7.4-8.0
 RUN apt-get update && apt-get install -y \
@@ -23,7 +33,7 @@ RUN apt-get update && apt-get install -y \
     libcurl4-openssl-dev \
     libssl-dev \
     zlib1g-dev \
-    && rm -rf /var/lib/apt/lists/*
+    && rm -rf /var/lib/apt/lists/*{{ hook "after-system-deps" . }}
 
 # Configure PHP extensions
 RUN docker-php-ext-configure gd --with-freetype --with-jpeg
@@ -61,7 +71,7 @@ RUN docker-php-ext-install \
 
 # Install PECL extensions
 RUN pecl install redis igbinary \
-    && docker-php-ext-enable redis igbinary
+    && docker-php-ext-enable redis igbinary{{ hook "after-extensions" . }}
 
 # Install Composer (version compatible with PHP version)
 {{- if or (eq .Project.PHP "5.6") (eq .Project.PHP "7.0") (eq .Project.PHP "7.1") (eq .Project.PHP "7.2") (eq .Project.PHP "7.3") }}
@@ -111,29 +121,8 @@ RUN curl -fsSL https://deb.nodesource.com/setup_{{ $majorVersion }}.x | bash - \
 # Node.js installation skipped (node: none)
 {{- end }}
 
-# Copy custom PHP configuration
-COPY .phpier/docker/php/php.ini /usr/local/etc/php/conf.d/custom.ini
-
-# Configure Nginx
-COPY .phpier/docker/nginx/nginx.conf /etc/nginx/nginx.conf
-COPY .phpier/docker/nginx/default.conf /etc/nginx/sites-available/default
-RUN ln -sf /etc/nginx/sites-available/default /etc/nginx/sites-enabled/default
-
-# Configure Supervisor
-COPY .phpier/docker/supervisor/supervisord.conf /etc/supervisor/conf.d/supervisord.conf
-
-# Copy entrypoint script and make it executable
-COPY .phpier/docker/entrypoint.sh /usr/local/bin/start
-RUN chmod +x /usr/local/bin/start
-
 # Create phpier user for permission mapping
 RUN useradd -ms /bin/bash -u 1337 phpier
 
 # Create www-data user directories
 RUN mkdir -p /var/www/html && chown www-data:www-data /var/www/html
-
-# Expose port
-EXPOSE 80
-
-# Set entrypoint
-ENTRYPOINT ["start"]
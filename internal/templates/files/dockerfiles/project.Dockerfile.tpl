@@ -0,0 +1,34 @@
+# Project image for {{.Project.Name}}
+# Generated by phpier - system packages and extensions come from the shared base image
+FROM {{.BaseImage}}
+
+# Copy custom PHP configuration
+COPY .phpier/docker/php/php.ini /usr/local/etc/php/conf.d/custom.ini
+
+# Answer php-fpm pings for the container healthcheck (proxied by nginx at /fpm-ping)
+RUN printf '[www]\nping.path = /fpm-ping\n' > /usr/local/etc/php-fpm.d/zz-phpier-healthcheck.conf
+
+# Log php-fpm to the mounted log directory, next to the PHP error log ('phpier logs --file php-fpm')
+RUN printf '[global]\nerror_log = /var/log/php/php-fpm.log\n' > /usr/local/etc/php-fpm.d/zz-phpier-logs.conf
+
+# Configure Nginx
+COPY .phpier/docker/nginx/nginx.conf /etc/nginx/nginx.conf
+COPY .phpier/docker/nginx/default.conf /etc/nginx/sites-available/default
+RUN ln -sf /etc/nginx/sites-available/default /etc/nginx/sites-enabled/default
+
+# Configure Supervisor
+COPY .phpier/docker/supervisor/supervisord.conf /etc/supervisor/conf.d/supervisord.conf
+
+# Rotation of the nginx and PHP logs, run by supervisor
+COPY .phpier/docker/logrotate.sh /usr/local/bin/phpier-logrotate
+RUN chmod +x /usr/local/bin/phpier-logrotate
+
+# Copy entrypoint script and make it executable
+COPY .phpier/docker/entrypoint.sh /usr/local/bin/start
+RUN chmod +x /usr/local/bin/start{{ hook "final" . }}
+
+# Expose port
+EXPOSE 80
+
+# Set entrypoint
+ENTRYPOINT ["start"]
@@ -0,0 +1,75 @@
+package traefik
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Router is an HTTP router as reported by the Traefik API
+type Router struct {
+	Name        string   `json:"name"`
+	Rule        string   `json:"rule"`
+	EntryPoints []string `json:"entryPoints"`
+	Middlewares []string `json:"middlewares"`
+	Service     string   `json:"service"`
+	Provider    string   `json:"provider"`
+	Status      string   `json:"status"`
+}
+
+// Client talks to the Traefik API
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Username   string // Basic auth credentials when the API is protected
+	Password   string
+}
+
+// NewClient creates a Traefik API client for baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+// HTTPRouters returns all HTTP routers known to Traefik, sorted by name
+func (c *Client) HTTPRouters(ctx context.Context) ([]Router, error) {
+	var routers []Router
+	// The API paginates; ask for a page large enough for any development setup
+	if err := c.get(ctx, "/api/http/routers?per_page=1000", &routers); err != nil {
+		return nil, err
+	}
+	sort.Slice(routers, func(i, j int) bool {
+		return routers[i].Name < routers[j].Name
+	})
+	return routers, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Traefik API at %s: %w", c.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("traefik API returned %s for %s", resp.Status, path)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode Traefik API response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package traefik
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPRouters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/http/routers", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"name":"shop@docker","rule":"Host(` + "`shop.localhost`" + `)","entryPoints":["web"],
+			 "middlewares":["shop-auth@docker","cors@file"],"service":"shop","provider":"docker","status":"enabled"},
+			{"name":"api@file","rule":"Host(` + "`traefik.localhost`" + `)","entryPoints":["web"],
+			 "service":"api@internal","provider":"file","status":"enabled"}
+		]`))
+	}))
+	defer server.Close()
+
+	routers, err := NewClient(server.URL + "/").HTTPRouters(context.Background())
+	require.NoError(t, err)
+	require.Len(t, routers, 2)
+
+	assert.Equal(t, "api@file", routers[0].Name, "routers are sorted by name")
+	assert.Empty(t, routers[0].Middlewares)
+	assert.Equal(t, "shop@docker", routers[1].Name)
+	assert.Equal(t, []string{"shop-auth@docker", "cors@file"}, routers[1].Middlewares)
+	assert.Equal(t, []string{"web"}, routers[1].EntryPoints)
+}
+
+func TestHTTPRouters_BasicAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "admin" || password != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.HTTPRouters(context.Background())
+	assert.Error(t, err)
+
+	client.Username, client.Password = "admin", "secret"
+	routers, err := client.HTTPRouters(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, routers)
+}
+
+func TestHTTPRouters_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).HTTPRouters(context.Background())
+	assert.Error(t, err)
+}